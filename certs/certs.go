@@ -0,0 +1,194 @@
+package certs
+
+import (
+	"bytes"
+	"crypto/dsa"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"kube-review/k8s"
+	"kube-review/nodelist"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Certificate is the parts of an X.509 certificate needed to review it. SANs are prefixed
+// with their type, e.g. DNS:example.com or IP:10.0.0.1, and KeySize is in bits
+type Certificate struct {
+	Subject            string
+	Issuer             string
+	SANs               []string
+	KeyType            string
+	KeySize            int
+	SignatureAlgorithm string
+	NotBefore          time.Time
+	NotAfter           time.Time
+	IsCA               bool
+	SelfSigned         bool
+}
+
+// Decode returns the certificates in a string value, either as PEM or as base64 encoded PEM
+// anywhere in the value, such as the certificate-authority-data of a kubeconfig or the
+// tls.crt of a Secret. Other PEM blocks, such as keys, are ignored
+func Decode(value string) []Certificate {
+	certificates := decodePEM([]byte(value))
+	if len(certificates) > 0 {
+		return certificates
+	}
+	for _, encoded := range encodedPEMRegex.FindAllString(value, -1) {
+		if decoded, err := base64.StdEncoding.DecodeString(encoded); err == nil {
+			certificates = append(certificates, decodePEM(decoded)...)
+		}
+	}
+	return certificates
+}
+
+// DaysRemaining returns the whole days from at until the certificate expires, which is
+// negative once it has expired
+func (c Certificate) DaysRemaining(at time.Time) int {
+	return int(math.Floor(c.NotAfter.Sub(at).Hours() / 24))
+}
+
+// Key returns the key type followed by its size, e.g. RSA 2048
+func (c Certificate) Key() string {
+	if c.KeySize == 0 {
+		return c.KeyType
+	}
+	return fmt.Sprintf("%s %d", c.KeyType, c.KeySize)
+}
+
+// WeakKey returns true for RSA keys under 2048 bits, elliptic curve keys under 256 bits and
+// any DSA key
+func (c Certificate) WeakKey() bool {
+	switch c.KeyType {
+	case "RSA":
+		return c.KeySize < 2048
+	case "ECDSA":
+		return c.KeySize < 256
+	case "DSA":
+		return true
+	}
+	return false
+}
+
+// Summary returns a line for each of the subject, issuer, SANs, key and validity, with the
+// days remaining relative to at
+func (c Certificate) Summary(at time.Time) []string {
+	sans := strings.Join(c.SANs, ", ")
+	if sans == "" {
+		sans = "None"
+	}
+	subject, issuer := c.Subject, c.Issuer
+	if c.IsCA {
+		subject += " (CA)"
+	}
+	if c.SelfSigned {
+		issuer += " (self-signed)"
+	}
+	days := c.DaysRemaining(at)
+	remaining := fmt.Sprintf("%d days remaining", days)
+	if days < 0 {
+		remaining = fmt.Sprintf("expired %d days ago", -days)
+	} else if at.Before(c.NotBefore) {
+		remaining = "not yet valid"
+	}
+	return []string{
+		"Subject:  " + subject,
+		"Issuer:   " + issuer,
+		"SANs:     " + sans,
+		"Key:      " + c.Key() + " signed with " + c.SignatureAlgorithm,
+		fmt.Sprintf("Valid:    %s to %s (%s)", c.NotBefore.Format(dateLayout), c.NotAfter.Format(dateLayout), remaining),
+	}
+}
+
+// Found is a certificate in the string value at Index of Resource
+type Found struct {
+	Certificate
+	Resource nodelist.ResourceInfo
+	Index    int
+}
+
+// Find returns every certificate in the string values of nodeList's current view
+func Find(nodeList k8s.ValueNodeList) []Found {
+	var found []Found
+	index := k8s.NewIndex(nodeList)
+	for _, nodeIndex := range k8s.GetStringNodes(nodeList, 0) {
+		certificates := Decode(nodeList.GetValue(nodeIndex))
+		if len(certificates) == 0 {
+			continue
+		}
+		resource, _ := index.GetContaining(nodeIndex)
+		for _, certificate := range certificates {
+			found = append(found, Found{certificate, resource.ResourceInfo, nodeIndex})
+		}
+	}
+	return found
+}
+
+/////////////////////////////////////////////////////////////////////////////////
+// PRIVATE FUNCTIONS
+
+const dateLayout = "2006-01-02"
+
+// encodedPEMRegex matches base64 encoded PEM, which always starts with the encoding of "-----BEGIN "
+var encodedPEMRegex = regexp.MustCompile(`LS0tLS1CRUdJTi[A-Za-z0-9+/]*={0,2}`)
+
+func decodePEM(data []byte) []Certificate {
+	var certificates []Certificate
+	for {
+		block, rest := pem.Decode(data)
+		if block == nil {
+			return certificates
+		}
+		data = rest
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		if cert, err := x509.ParseCertificate(block.Bytes); err == nil {
+			certificates = append(certificates, newCertificate(cert))
+		}
+	}
+}
+
+func newCertificate(cert *x509.Certificate) Certificate {
+	certificate := Certificate{
+		Subject:            cert.Subject.String(),
+		Issuer:             cert.Issuer.String(),
+		SignatureAlgorithm: cert.SignatureAlgorithm.String(),
+		NotBefore:          cert.NotBefore.UTC(),
+		NotAfter:           cert.NotAfter.UTC(),
+		IsCA:               cert.IsCA,
+		SelfSigned:         bytes.Equal(cert.RawSubject, cert.RawIssuer) && cert.CheckSignature(cert.SignatureAlgorithm, cert.RawTBSCertificate, cert.Signature) == nil,
+	}
+	for _, name := range cert.DNSNames {
+		certificate.SANs = append(certificate.SANs, "DNS:"+name)
+	}
+	for _, ip := range cert.IPAddresses {
+		certificate.SANs = append(certificate.SANs, "IP:"+ip.String())
+	}
+	for _, email := range cert.EmailAddresses {
+		certificate.SANs = append(certificate.SANs, "email:"+email)
+	}
+	for _, uri := range cert.URIs {
+		certificate.SANs = append(certificate.SANs, "URI:"+uri.String())
+	}
+	switch key := cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		certificate.KeyType, certificate.KeySize = "RSA", key.N.BitLen()
+	case *ecdsa.PublicKey:
+		certificate.KeyType, certificate.KeySize = "ECDSA", key.Curve.Params().BitSize
+	case ed25519.PublicKey:
+		certificate.KeyType, certificate.KeySize = "Ed25519", 256
+	case *dsa.PublicKey:
+		certificate.KeyType, certificate.KeySize = "DSA", key.P.BitLen()
+	default:
+		certificate.KeyType = cert.PublicKeyAlgorithm.String()
+	}
+	return certificate
+}
@@ -0,0 +1,106 @@
+package certs_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"kube-review/certs"
+	"kube-review/utils"
+	"math/big"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// createPEM returns a PEM certificate for name valid until notAfter, signed by its own key
+func createPEM(t *testing.T, name string, key, public interface{}, notAfter time.Time, isCA bool) string {
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		DNSNames:              []string{name + ".example.com"},
+		IPAddresses:           []net.IP{net.ParseIP("10.0.0.1")},
+		NotBefore:             time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:              notAfter,
+		IsCA:                  isCA,
+		BasicConstraintsValid: isCA,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, public, key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate - %s", err.Error())
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func createECDSA(t *testing.T, name string, notAfter time.Time, isCA bool) string {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	return createPEM(t, name, key, &key.PublicKey, notAfter, isCA)
+}
+
+func TestDecodeFindsBase64PEMInKubeconfig(t *testing.T) {
+	ca := createECDSA(t, "cluster-ca", time.Date(2034, 1, 1, 0, 0, 0, 0, time.UTC), true)
+	kubeconfig := "clusters:\n- cluster:\n    certificate-authority-data: " + base64.StdEncoding.EncodeToString([]byte(ca)) + "\n    server: https://10.0.0.1:6443\n"
+	certificates := certs.Decode(kubeconfig)
+	if len(certificates) != 1 {
+		t.Fatalf("Expected 1 certificate but got %d", len(certificates))
+	}
+	certificate := certificates[0]
+	if certificate.Subject != "CN=cluster-ca" || certificate.Key() != "ECDSA 256" || !certificate.IsCA || !certificate.SelfSigned {
+		t.Errorf("Expected a self-signed ECDSA 256 CA called CN=cluster-ca but got %+v", certificate)
+	}
+	expected := []string{"DNS:cluster-ca.example.com", "IP:10.0.0.1"}
+	if !reflect.DeepEqual(certificate.SANs, expected) {
+		t.Errorf("Expected SANs %v but got %v", expected, certificate.SANs)
+	}
+}
+
+func TestDetectMeasuresExpiryFromDate(t *testing.T) {
+	found := []certs.Found{{Certificate: certs.Decode(createECDSA(t, "web", time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC), false))[0]}}
+	tests := []struct {
+		date     string
+		expired  int
+		expiring int
+	}{
+		{"2026-06-01", 0, 0},
+		{"2026-10-15", 0, 1},
+		{"2026-11-01T00:00:00Z", 0, 1},
+		{"2026-11-02", 1, 0},
+	}
+	for _, test := range tests {
+		at, err := utils.ParseDate(test.date)
+		if err != nil {
+			t.Fatalf("Expected no error but got %s", err.Error())
+		}
+		expired, _ := certs.Detect(found, "expired", at, 30)
+		expiring, _ := certs.Detect(found, "expiring", at, 30)
+		if len(expired) != test.expired || len(expiring) != test.expiring {
+			t.Errorf("Expected %d expired and %d expiring on %s but got %d and %d", test.expired, test.expiring, test.date, len(expired), len(expiring))
+		}
+	}
+	if _, err := utils.ParseDate("15/10/2026"); err == nil {
+		t.Errorf("Expected an error for a date that is not YYYY-MM-DD or RFC3339")
+	}
+}
+
+func TestDetectFlagsWeakKeysAndSelfSignedLeaves(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Skipf("RSA 1024 keys cannot be generated - %s", err.Error())
+	}
+	notAfter := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	var found []certs.Found
+	for _, value := range []string{createPEM(t, "legacy", rsaKey, &rsaKey.PublicKey, notAfter, false), createECDSA(t, "ca", notAfter, true)} {
+		found = append(found, certs.Found{Certificate: certs.Decode(value)[0]})
+	}
+	for check, expected := range map[string]string{"weak-key": "CN=legacy", "self-signed": "CN=legacy"} {
+		findings, _ := certs.Detect(found, check, time.Now(), 30)
+		if len(findings) != 1 || findings[0].Subject != expected {
+			t.Errorf("Expected only %s to be flagged by %s but got %v", expected, check, findings)
+		}
+	}
+}
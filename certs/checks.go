@@ -0,0 +1,74 @@
+package certs
+
+import (
+	"fmt"
+	"kube-review/utils"
+	"time"
+)
+
+// Check is a problem with a certificate. detect returns the message of a finding and whether
+// the certificate has the problem at the given date, where days is how soon counts as expiring
+type Check struct {
+	Name        string
+	Description string
+	detect      func(c Certificate, at time.Time, days int) (string, bool)
+}
+
+// Finding is a certificate flagged by a check
+type Finding struct {
+	Check string
+	Found
+	Message string
+}
+
+var checks = []Check{
+	{"expired", "certificates that expired before the date", func(c Certificate, at time.Time, days int) (string, bool) {
+		remaining := c.DaysRemaining(at)
+		return fmt.Sprintf("Certificate %s expired on %s, %d days ago", c.Subject, c.NotAfter.Format(dateLayout), -remaining), at.After(c.NotAfter)
+	}},
+	{"expiring", "certificates that expire within the given number of days of the date", func(c Certificate, at time.Time, days int) (string, bool) {
+		remaining := c.DaysRemaining(at)
+		return fmt.Sprintf("Certificate %s expires on %s, %d days remaining", c.Subject, c.NotAfter.Format(dateLayout), remaining),
+			!at.After(c.NotAfter) && remaining <= days
+	}},
+	{"weak-key", "certificates with an RSA key under 2048 bits, an elliptic curve key under 256 bits or a DSA key", func(c Certificate, at time.Time, days int) (string, bool) {
+		return fmt.Sprintf("Certificate %s has a weak %s bit key", c.Subject, c.Key()), c.WeakKey()
+	}},
+	{"self-signed", "certificates that are signed by their own key without being a CA, so clients can only trust them by pinning or skipping verification", func(c Certificate, at time.Time, days int) (string, bool) {
+		return fmt.Sprintf("Certificate %s is self-signed", c.Subject), c.SelfSigned && !c.IsCA
+	}},
+}
+
+// Checks returns every built in check
+func Checks() []Check {
+	return checks
+}
+
+// GetCheck returns the check called name
+func GetCheck(name string) (Check, error) {
+	var names []string
+	for _, check := range checks {
+		names = append(names, check.Name)
+	}
+	index, err := utils.FindName("certificate check", name, names)
+	if err != nil {
+		return Check{}, err
+	}
+	return checks[index], nil
+}
+
+// Detect returns the findings of check for the certificates found, measuring expiry from at.
+// Days is how many days from at a certificate must expire within to be expiring
+func Detect(found []Found, name string, at time.Time, days int) ([]Finding, error) {
+	check, err := GetCheck(name)
+	if err != nil {
+		return nil, err
+	}
+	var findings []Finding
+	for _, certificate := range found {
+		if message, ok := check.detect(certificate.Certificate, at, days); ok {
+			findings = append(findings, Finding{check.Name, certificate, message})
+		}
+	}
+	return findings, nil
+}
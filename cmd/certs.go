@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"fmt"
+	"kube-review/certs"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	certsDays   int
+	certsOutput string
+	certsCmd    = &cobra.Command{
+		Use:   "certs",
+		Short: "Inspect X.509 certificates and check their expiry and keys",
+		Long: "This command finds the certificates held in string values, as PEM or base64 encoded" +
+			" PEM such as in the tls.crt of a Secret or the certificate-authority-data of a kubeconfig," +
+			" and shows the subject, issuer, SANs, key and validity of each with the days remaining" +
+			" from --date. It then flags certificates that have expired, expire within --days," +
+			" have a weak key or are self-signed without being a CA",
+		Run: certsRun,
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(certsCmd)
+
+	certsCmd.Flags().StringVar(&certDate, "date", "now", "Date certificate expiry is measured from, as YYYY-MM-DD or RFC3339")
+	certsCmd.Flags().IntVar(&certsDays, "days", 30, "Number of days from --date a certificate must expire within to be expiring")
+	certsCmd.Flags().StringVarP(&certsOutput, "output", "o", "", "File to write the output to instead of stdout")
+}
+
+func certsRun(cmd *cobra.Command, args []string) {
+	nodeList := getConfig()
+	at := getDate()
+	found := certs.Find(nodeList)
+
+	out := "== Certificates ==\n"
+	for _, certificate := range found {
+		out += fmt.Sprintf("%s - %s\n", certificate.Resource, nodeList.GetPath(certificate.Index))
+		out += "  " + strings.Join(certificate.Summary(at), "\n  ") + "\n"
+	}
+	if len(found) == 0 {
+		out += "No certificates\n"
+	}
+
+	out += "\n== Findings ==\n"
+	count := 0
+	for _, check := range certs.Checks() {
+		findings, err := certs.Detect(found, check.Name, at, certsDays)
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+		for _, finding := range findings {
+			out += fmt.Sprintf("%s: %s - %s - %s\n", finding.Check, finding.Resource, nodeList.GetPath(finding.Index), finding.Message)
+		}
+		count += len(findings)
+	}
+	if count == 0 {
+		out += "No findings\n"
+	}
+	writeOutput(out, certsOutput)
+}
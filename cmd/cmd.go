@@ -4,7 +4,11 @@ import (
 	"fmt"
 	"io/ioutil"
 	"kube-review/nodelist"
+	"kube-review/search"
+	"kube-review/utils"
 	"os"
+	"path/filepath"
+	"time"
 
 	"github.com/spf13/cobra"
 )
@@ -13,6 +17,9 @@ var (
 	kubeFile       string
 	kubeconfigFile string
 	kubeContext    string
+	queryPacks     []string
+	embedded       bool
+	certDate       string
 	rootCmd        = &cobra.Command{
 		Use:   "kube-review",
 		Short: "A review tool for kubernetes cluster config",
@@ -44,24 +51,43 @@ func init() {
 	rootCmd.PersistentFlags().StringVarP(&kubeFile, "file", "f", "", "Cluster config file")
 	rootCmd.PersistentFlags().StringVar(&kubeconfigFile, "kubeconfig", "", "Path to the kubeconfig file")
 	rootCmd.PersistentFlags().StringVar(&kubeContext, "context", "", "The name of the kubeconfig context to use")
+	rootCmd.PersistentFlags().StringArrayVar(&queryPacks, "query-pack", []string{}, "Query pack file or directory of packs to load")
+	rootCmd.PersistentFlags().BoolVar(&embedded, "embedded", false, "Decode JSON, YAML, base64 and PEM documents held in string values into nodes so they can be searched")
 }
 
 func getConfig() *nodelist.NodeList {
 	var rawJSON []byte
 	if kubeFile != "" {
-		rawJSON = loadFromFile()
+		rawJSON = loadFromFile(kubeFile)
 	} else {
 		// Run kubectl get ... (after asking permission and outputing what cluster it will run on)
 		fmt.Println("I have not yet implemented this so please use flag 'file'")
 		os.Exit(1)
 	}
-	return getNodeList(rawJSON)
+	nodeList := getNodeList(rawJSON)
+	if embedded {
+		if _, err := nodeList.ExpandEmbedded(); err != nil {
+			fmt.Println("Failed to decode embedded documents - " + err.Error())
+			os.Exit(1)
+		}
+	}
+	return nodeList
+}
+
+// getDate returns the date set by --date that certificate expiry is measured from
+func getDate() time.Time {
+	at, err := utils.ParseDate(certDate)
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+	return at
 }
 
-func loadFromFile() []byte {
-	rawJSON, err := ioutil.ReadFile(kubeFile)
+func loadFromFile(file string) []byte {
+	rawJSON, err := ioutil.ReadFile(file)
 	if err != nil {
-		fmt.Printf("'%s' does not exist\n", kubeFile)
+		fmt.Printf("'%s' does not exist\n", file)
 		os.Exit(1)
 	}
 	return rawJSON
@@ -78,3 +104,56 @@ func getNodeList(rawJSON []byte) *nodelist.NodeList {
 	}
 	return &jsonData
 }
+
+// systemQueryDir is searched for query packs after the built in defaults and before the user's packs
+const systemQueryDir = "/etc/kube-review/queries.d"
+
+// getQueryList merges the built in queries with packs from the search path and those
+// passed with --query-pack. Later packs take priority if a query is defined twice
+func getQueryList() *search.QueryList {
+	queryList := search.NewQueryList()
+	if err := queryList.LoadDefaults(); err != nil {
+		fmt.Println("Failed to load default queries - " + err.Error())
+		os.Exit(1)
+	}
+
+	userDir := getUserQueryDir()
+	for _, dir := range []string{systemQueryDir, userDir} {
+		if dir == "" {
+			continue
+		}
+		if err := queryList.LoadPackDir(dir); err != nil {
+			fmt.Println("Failed to load query packs - " + err.Error())
+			os.Exit(1)
+		}
+	}
+	for _, pack := range queryPacks {
+		var err error
+		if info, errStat := os.Stat(pack); errStat == nil && info.IsDir() {
+			err = queryList.LoadPackDir(pack)
+		} else {
+			err = queryList.LoadPack(pack)
+		}
+		if err != nil {
+			fmt.Println("Failed to load query pack - " + err.Error())
+			os.Exit(1)
+		}
+	}
+	if userDir != "" {
+		queryList.SetUserPack(filepath.Join(userDir, "user.json"))
+	}
+
+	for _, conflict := range queryList.Conflicts() {
+		fmt.Println("Warning: " + conflict)
+	}
+	return &queryList
+}
+
+// getUserQueryDir returns the directory holding the user's query packs or "" if there is no config directory
+func getUserQueryDir() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(configDir, "kube-review", "queries.d")
+}
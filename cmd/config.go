@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"fmt"
+	"kube-review/k8s"
+	"kube-review/nodelist"
+	"kube-review/utils"
+	"os"
+	"path/filepath"
+)
+
+// config holds the user's defaults, read from config.json in the kube-review config directory
+type config struct {
+	// Split lists separators, e.g. "items = metadata.namespace, kind", used to split views on load
+	Split []string `json:"split"`
+}
+
+// loadUserConfig returns the user's config or an empty config if they have not created one
+func loadUserConfig() (config, error) {
+	var userConfig config
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return userConfig, nil
+	}
+	file := filepath.Join(configDir, "kube-review", "config.json")
+	if _, err := os.Stat(file); os.IsNotExist(err) {
+		return userConfig, nil
+	}
+	if err := utils.LoadJSON(file, &userConfig, ""); err != nil {
+		return config{}, fmt.Errorf("Failed to load '%s' - %s", file, err.Error())
+	}
+	return userConfig, nil
+}
+
+// splitViews splits nodeList by each separator, reporting any that fail
+func splitViews(nodeList *nodelist.NodeList, separators []string) {
+	for _, separator := range separators {
+		if err := k8s.SplitViews(nodeList, separator); err != nil {
+			fmt.Printf("Could not split views by '%s' - %s\n", separator, err.Error())
+		}
+	}
+}
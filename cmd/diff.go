@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+	"kube-review/diff"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	diffFormat      string
+	diffOutput      string
+	diffInteractive bool
+	diffCmd         = &cobra.Command{
+		Use:   "diff OLD NEW",
+		Short: "Compare two cluster config files",
+		Long: "This command matches the resources in two config files, such as offline.json from" +
+			" a previous review and from today, by kind, namespace and name and shows those that" +
+			" were added, removed or changed. Use -i to view the differences in the GUI",
+		Args: cobra.ExactArgs(2),
+		Run:  diffRun,
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+
+	diffCmd.Flags().StringVar(&diffFormat, "format", "text", "Output format, either text or patch (JSON Patch)")
+	diffCmd.Flags().StringVarP(&diffOutput, "output", "o", "", "File to write the output to instead of stdout")
+	diffCmd.Flags().BoolVarP(&diffInteractive, "interactive", "i", false, "Show the differences in the GUI instead of printing them")
+}
+
+func diffRun(cmd *cobra.Command, args []string) {
+	if diffFormat != "text" && diffFormat != "patch" {
+		fmt.Printf("'%s' is not a valid format. Must be text or patch\n", diffFormat)
+		os.Exit(1)
+	}
+	oldList := getNodeList(loadFromFile(args[0]))
+	newList := getNodeList(loadFromFile(args[1]))
+	d := diff.Compare(oldList, newList)
+
+	if diffInteractive {
+		if err := d.Mark(oldList, newList); err != nil {
+			fmt.Println("Failed to mark differences - " + err.Error())
+			os.Exit(1)
+		}
+		startUI(newList)
+		return
+	}
+
+	output := d.Text(diffOutput == "" && isTerminal(os.Stdout))
+	if diffFormat == "patch" {
+		var err error
+		if output, err = d.Patch(); err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+	}
+	writeOutput(output, diffOutput)
+}
+
+// isTerminal returns true if file is a terminal rather than a pipe or file, so can show colour
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"kube-review/images"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	imagesRegistries string
+	imagesOutput     string
+	imagesCmd        = &cobra.Command{
+		Use:   "images",
+		Short: "List container images and check how they are pulled",
+		Long: "This command lists the image of every container, init container and ephemeral" +
+			" container with its registry, repository, tag, digest and pull policy. It then flags" +
+			" images pulled by the latest tag or no tag, images without a digest, registries that" +
+			" are not allowed, risky pull policies and registries configured without TLS in" +
+			" ConfigMaps and Secrets, such as docker configs, daemon.json and containerd config",
+		Run: imagesRun,
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(imagesCmd)
+
+	imagesCmd.Flags().StringVarP(&imagesRegistries, "registries", "r", "", "Regex the whole registry of an image must match to be allowed. Empty allows any")
+	imagesCmd.Flags().StringVarP(&imagesOutput, "output", "o", "", "File to write the output to instead of stdout")
+}
+
+func imagesRun(cmd *cobra.Command, args []string) {
+	nodeList := getConfig()
+	inventory := images.New(nodeList)
+	var findings []images.Finding
+	for _, check := range images.Checks() {
+		found, err := inventory.Detect(check.Name, imagesRegistries)
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+		findings = append(findings, found...)
+	}
+
+	var table bytes.Buffer
+	writer := tabwriter.NewWriter(&table, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(writer, "RESOURCE\tCONTAINER\tREGISTRY\tREPOSITORY\tTAG\tDIGEST\tPULL POLICY")
+	for _, image := range inventory.Images() {
+		policy := image.PullPolicy
+		if image.PullPolicyIndex < 0 {
+			policy += " (default)"
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", image.ResourceInfo, image.Container.Name, image.Registry,
+			image.Repository, orNone(image.Tag), orNone(image.Digest), policy)
+	}
+	writer.Flush()
+
+	out := "== Images ==\n" + table.String()
+	out += "\n== Findings ==\n"
+	for _, finding := range findings {
+		resource := finding.Resource.String()
+		if finding.Container != "" {
+			resource += " " + finding.Container
+		}
+		out += fmt.Sprintf("%s: %s - %s - %s\n", finding.Check, resource, nodeList.GetPath(finding.Index), finding.Message)
+	}
+	if len(findings) == 0 {
+		out += "No findings\n"
+	}
+	writeOutput(out, imagesOutput)
+}
+
+func orNone(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
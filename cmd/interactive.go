@@ -2,30 +2,61 @@ package cmd
 
 import (
 	"fmt"
+	"kube-review/nodelist"
 	"kube-review/search"
 	"kube-review/ui"
+	"os"
+	"path/filepath"
 
 	"github.com/spf13/cobra"
 )
 
-var interactiveCmd = &cobra.Command{
-	Use:   "interactive",
-	Short: "Start interactive session",
-	Long:  "This will start an ncurses GUI to view and search the kubernetes config manually",
-	Run:   interactiveRun,
-}
+var (
+	splits         []string
+	interactiveCmd = &cobra.Command{
+		Use:   "interactive",
+		Short: "Start interactive session",
+		Long:  "This will start an ncurses GUI to view and search the kubernetes config manually",
+		Run:   interactiveRun,
+	}
+)
 
 func init() {
 	rootCmd.AddCommand(interactiveCmd)
+
+	interactiveCmd.Flags().StringVar(&certDate, "date", "now", "Date the days remaining of certificates in the display are counted from, as YYYY-MM-DD or RFC3339")
+	interactiveCmd.Flags().StringArrayVar(&splits, "split", []string{}, "Split views on load, e.g. 'items = metadata.namespace, kind'. Replaces the split list in config.json")
 }
 
 func interactiveRun(cmd *cobra.Command, args []string) {
-	queryList := search.NewQueryList()
-	if err := queryList.Load("querylist.json", "search/queryschema.json"); err != nil {
-		fmt.Println("Failed to load 'querylist.json' - " + err.Error())
-		return
-	}
 	nodeList := getConfig()
+	if !cmd.Flags().Changed("split") {
+		userConfig, err := loadUserConfig()
+		if err != nil {
+			fmt.Println(err.Error())
+		}
+		splits = userConfig.Split
+	}
+	splitViews(nodeList, splits)
 
-	ui.Run(nodeList, &queryList)
+	startUI(nodeList)
+}
+
+// startUI loads the queries and search history and starts the GUI on nodeList
+func startUI(nodeList *nodelist.NodeList) {
+	queryList := getQueryList()
+	history := search.NewHistory(getHistoryFile(), 500)
+	if err := history.Load(); err != nil {
+		fmt.Println("Failed to load search history - " + err.Error())
+	}
+	ui.Run(nodeList, queryList, &history, getDate())
+}
+
+// getHistoryFile returns the per-user search history file or "" if there is no config directory
+func getHistoryFile() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(configDir, "kube-review", "history.json")
 }
@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"fmt"
+	"kube-review/netpol"
+	"net"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	netpolNamespace string
+	netpolOutput    string
+	netpolCmd       = &cobra.Command{
+		Use:   "netpol",
+		Short: "Analyse NetworkPolicy coverage and reachability",
+		Long: "This command resolves the pod and namespace selectors of every NetworkPolicy against" +
+			" the pods and pod templates in the config. It shows whether each namespace denies" +
+			" ingress and egress by default, what each policy selects and allows, which pods no" +
+			" policy isolates, rules that allow everything and whether egress to the cloud" +
+			" metadata endpoint is blocked",
+		Run: netpolRun,
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(netpolCmd)
+
+	netpolCmd.Flags().StringVarP(&netpolNamespace, "namespace", "n", "", "Only show namespaces fully matching this regex")
+	netpolCmd.Flags().StringVarP(&netpolOutput, "output", "o", "", "File to write the output to instead of stdout")
+}
+
+func netpolRun(cmd *cobra.Command, args []string) {
+	nodeList := getConfig()
+	analysis := netpol.New(nodeList)
+	var findings []netpol.Finding
+	for _, check := range netpol.Checks() {
+		found, err := analysis.Detect(check.Name, netpolNamespace)
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+		findings = append(findings, found...)
+	}
+	// The regex is valid as Detect has compiled it
+	namespaceRegex := regexp.MustCompile("^(?:" + netpolNamespace + ")$")
+
+	out := "== Namespaces ==\n"
+	for _, namespace := range analysis.Namespaces() {
+		if netpolNamespace != "" && !namespaceRegex.MatchString(namespace.Name) {
+			continue
+		}
+		out += fmt.Sprintf("%s: %d policies, ingress %s, egress %s\n", namespace.Name, len(namespace.Policies),
+			formatDefault(namespace.DefaultDenyIngress), formatDefault(namespace.DefaultDenyEgress))
+		for _, policy := range namespace.Policies {
+			out += fmt.Sprintf("    %s selects %s%s\n", policy.Name, formatPods(policy.Pods), formatTypes(policy))
+			for _, rule := range policy.Rules {
+				out += fmt.Sprintf("        %s %s\n", rule.Direction, formatRule(rule))
+			}
+		}
+	}
+
+	out += fmt.Sprintf("\n== Pods (metadata endpoint %s) ==\n", netpol.MetadataAddress)
+	for _, access := range analysis.EgressTo(net.ParseIP(netpol.MetadataAddress), "TCP", 80) {
+		if netpolNamespace != "" && !namespaceRegex.MatchString(access.Namespace) {
+			continue
+		}
+		metadata := "blocked"
+		if access.Allowed && access.Index == access.Spec {
+			metadata = "allowed as egress is not isolated"
+		} else if access.Allowed {
+			metadata = "allowed by " + nodeList.GetPath(access.Index)
+		}
+		out += fmt.Sprintf("%s: ingress %s, egress %s, metadata %s\n", access.ResourceInfo,
+			formatIsolation(analysis.Selecting(access.Pod, netpol.INGRESS)),
+			formatIsolation(analysis.Selecting(access.Pod, netpol.EGRESS)), metadata)
+	}
+
+	out += "\n== Findings ==\n"
+	for _, finding := range findings {
+		out += fmt.Sprintf("%s: %s - %s - %s\n", finding.Check, finding.Resource, nodeList.GetPath(finding.Index), finding.Message)
+	}
+	if len(findings) == 0 {
+		out += "No findings\n"
+	}
+	writeOutput(out, netpolOutput)
+}
+
+func formatDefault(deny bool) string {
+	if deny {
+		return "default-deny"
+	}
+	return "default-allow"
+}
+
+// formatIsolation lists the policies isolating a pod in a direction
+func formatIsolation(policies []netpol.Policy) string {
+	if len(policies) == 0 {
+		return "not isolated"
+	}
+	var names []string
+	for _, policy := range policies {
+		names = append(names, policy.Name)
+	}
+	return "isolated by " + strings.Join(names, ", ")
+}
+
+func formatTypes(policy netpol.Policy) string {
+	var types []string
+	if policy.Ingress {
+		types = append(types, netpol.INGRESS.String())
+	}
+	if policy.Egress {
+		types = append(types, netpol.EGRESS.String())
+	}
+	return " (" + strings.Join(types, ", ") + ")"
+}
+
+func formatPods(pods []netpol.Pod) string {
+	if len(pods) == 0 {
+		return "no pods"
+	}
+	var names []string
+	for _, pod := range pods {
+		names = append(names, pod.ResourceInfo.String())
+	}
+	return strings.Join(names, ", ")
+}
+
+// formatRule describes the peers and ports a rule allows
+func formatRule(rule netpol.Rule) string {
+	peers := "all peers"
+	if !rule.AllPeers {
+		var allowed []string
+		if len(rule.Pods) > 0 {
+			allowed = append(allowed, formatPods(rule.Pods))
+		}
+		for _, block := range rule.IPBlocks {
+			if block.CIDR == nil {
+				continue
+			}
+			cidr := block.CIDR.String()
+			for _, except := range block.Except {
+				cidr += " except " + except.String()
+			}
+			allowed = append(allowed, cidr)
+		}
+		peers = "no pods in config"
+		if len(allowed) > 0 {
+			peers = strings.Join(allowed, ", ")
+		}
+	}
+	ports := "all ports"
+	if len(rule.Ports) > 0 {
+		var names []string
+		for _, port := range rule.Ports {
+			name := port.Protocol
+			if port.Port != "" {
+				name += "/" + port.Port
+			}
+			if port.EndPort > 0 {
+				name += fmt.Sprintf("-%d", port.EndPort)
+			}
+			names = append(names, name)
+		}
+		ports = strings.Join(names, ", ")
+	}
+	return fmt.Sprintf("%s on %s", peers, ports)
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+	"kube-review/pss"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	pssProfile  string
+	pssControls []string
+	pssOutput   string
+	pssCmd      = &cobra.Command{
+		Use:   "pss",
+		Short: "Check pods against the Pod Security Standards",
+		Long: "This command checks the pod spec of every Pod, and the pod template of every" +
+			" Deployment, DaemonSet, StatefulSet, ReplicaSet, Job and CronJob, against the baseline" +
+			" or restricted Pod Security Standard and lists each field that breaks a control",
+		Run: pssRun,
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(pssCmd)
+
+	pssCmd.Flags().StringVar(&pssProfile, "profile", "restricted", "Profile to check against, either baseline or restricted")
+	pssCmd.Flags().StringSliceVar(&pssControls, "controls", []string{}, "Only check these controls, e.g. privileged,hostNamespaces")
+	pssCmd.Flags().StringVarP(&pssOutput, "output", "o", "", "File to write the output to instead of stdout")
+}
+
+func pssRun(cmd *cobra.Command, args []string) {
+	profile, err := pss.ParseProfile(pssProfile)
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+	violations, err := pss.Evaluate(getConfig(), profile, pssControls)
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+
+	out := fmt.Sprintf("== Pod Security Standards (%s) ==\n", profile)
+	resources := map[string]bool{}
+	previous := ""
+	for _, violation := range violations {
+		if resource := violation.Resource.String(); resource != previous {
+			out += resource + "\n"
+			previous = resource
+			resources[resource] = true
+		}
+		out += fmt.Sprintf("    %s (%s): %s - %s\n", violation.Control, violation.Title, violation.Path, violation.Message)
+	}
+	out += fmt.Sprintf("%d violations in %d workloads\n", len(violations), len(resources))
+	writeOutput(out, pssOutput)
+}
@@ -2,13 +2,39 @@ package cmd
 
 import (
 	"fmt"
+	"kube-review/report"
+	"kube-review/search"
+	"kube-review/utils"
+	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 )
 
+// findingsExitCode is used when a query at or above the --fail-on severity matches
+const findingsExitCode = 2
+
 var (
-	queryList []string
-	queryCmd  = &cobra.Command{
+	queryList    []string
+	outputFormat string
+	outputFile   string
+	failOn       string
+	baselineFile string
+	// formatters are given the results of --baseline, which are nil if it is not set
+	formatters = map[string]func([]report.Finding, []report.Result) (string, error){
+		"text": formatText,
+		"json": report.ResultsJSON,
+		"vulnxml": func(findings []report.Finding, baseline []report.Result) (string, error) {
+			return report.VulnXML(findings)
+		},
+		"sarif": func(findings []report.Finding, baseline []report.Result) (string, error) {
+			return report.SARIF(findings, kubeFile)
+		},
+		"junit": func(findings []report.Finding, baseline []report.Result) (string, error) {
+			return report.JUnit(findings)
+		},
+	}
+	queryCmd = &cobra.Command{
 		Use:   "query",
 		Short: "Find common issues in config",
 		Long:  "This tool will automatically run and output the defined list of search commands",
@@ -19,9 +45,155 @@ var (
 func init() {
 	rootCmd.AddCommand(queryCmd)
 
-	queryCmd.Flags().StringArrayVarP(&queryList, "queries", "q", []string{}, "List of queries to run")
+	queryCmd.Flags().StringArrayVarP(&queryList, "queries", "q", []string{}, "List of queries to run. Arguments can be given as 'name:param=value,param=value'")
+	queryCmd.Flags().StringVar(&outputFormat, "format", "text", "Output format, one of text, json, vulnxml, sarif or junit")
+	queryCmd.Flags().StringVarP(&outputFile, "output", "o", "", "File to write the output to instead of stdout")
+	queryCmd.Flags().StringVar(&failOn, "fail-on", "", fmt.Sprintf("Exit with status %d if a query of at least this severity (Info, Low, Medium, High or Critical) matches", findingsExitCode))
+	queryCmd.Flags().StringVar(&baselineFile, "baseline", "", "Results of a previous run (from --format json) to label findings as new, fixed or unchanged. --fail-on then only counts new findings")
 }
 
 func queryRun(cmd *cobra.Command, args []string) {
-	fmt.Println()
+	formatter, ok := formatters[outputFormat]
+	if !ok {
+		fmt.Printf("'%s' is not a valid format. Must be one of text, json, vulnxml, sarif or junit\n", outputFormat)
+		os.Exit(1)
+	}
+	threshold, err := getThreshold()
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+	baseline := loadBaseline(baselineFile)
+	findings := runQueries(queryList)
+	output, err := formatter(findings, baseline)
+	if err != nil {
+		fmt.Printf("Failed to create %s output - %s\n", outputFormat, err.Error())
+		os.Exit(1)
+	}
+	writeOutput(output, outputFile)
+	if failOn == "" {
+		return
+	}
+	if baseline != nil {
+		if report.NewAtOrAbove(report.Label(baseline, findings), threshold) {
+			os.Exit(findingsExitCode)
+		}
+	} else if report.AtOrAbove(findings, threshold) {
+		os.Exit(findingsExitCode)
+	}
+}
+
+// loadBaseline returns the results in file or nil if file is ""
+func loadBaseline(file string) []report.Result {
+	if file == "" {
+		return nil
+	}
+	results, err := report.LoadResults(file)
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+	return results
+}
+
+// runQueries runs each query in inputs, or every query if empty, against the config. Queries
+// that fail are reported and left out of the returned findings
+func runQueries(inputs []string) []report.Finding {
+	ql := getQueryList()
+	if len(inputs) == 0 {
+		inputs = ql.GetNames()
+	}
+	nodeList := getConfig()
+
+	var findings []report.Finding
+	for _, input := range inputs {
+		finding, err := report.NewFinding(ql, input, *nodeList)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "'%s' - %s\n\n", input, err.Error())
+			continue
+		}
+		findings = append(findings, finding)
+	}
+	return findings
+}
+
+func getThreshold() (search.SeverityEnum, error) {
+	if failOn == "" {
+		return search.INFO, nil
+	}
+	return search.ParseSeverity(failOn)
+}
+
+// writeOutput writes output to file or stdout if file is ""
+func writeOutput(output, file string) {
+	if file == "" {
+		fmt.Print(output)
+		return
+	}
+	if err := utils.Save(file, output, true); err != nil {
+		fmt.Printf("Failed to write to '%s' - %s\n", file, err.Error())
+		os.Exit(1)
+	}
+}
+
+func formatText(findings []report.Finding, baseline []report.Result) (string, error) {
+	var output string
+	for _, finding := range findings {
+		output += formatFinding(finding)
+	}
+	if baseline != nil {
+		output += formatChanges(report.Label(baseline, findings))
+	}
+	return output, nil
+}
+
+// formatChanges lists the new and fixed results, along with a count of each status
+func formatChanges(results []report.Result) string {
+	newResults, fixed := report.WithStatus(results, report.NEW), report.WithStatus(results, report.FIXED)
+	out := "== Changes since baseline ==\n"
+	out += fmt.Sprintf("%d new, %d fixed, %d unchanged\n", len(newResults), len(fixed), len(report.WithStatus(results, report.UNCHANGED)))
+	for _, result := range append(newResults, fixed...) {
+		out += fmt.Sprintf("%s: %s (%s) %s %s\n", result.Status, result.Query, result.Severity, result.Object(), result.Path)
+	}
+	return out
+}
+
+func formatFinding(finding report.Finding) string {
+	out := formatQueryData(finding.Name, finding.Query, finding.Args)
+	if finding.Matched() {
+		for _, resource := range finding.Resources {
+			out += resource.String() + "\n"
+		}
+		out += finding.JSON + "\n"
+	} else {
+		out += "No matches found\n"
+	}
+	return out + "\n"
+}
+
+func formatQueryData(name string, data search.QueryData, args map[string]string) string {
+	out := fmt.Sprintf("== %s ==\n", name)
+	out += fmt.Sprintf("Severity: %s\n", data.Severity)
+	if data.Category != "" {
+		out += fmt.Sprintf("Category: %s\n", data.Category)
+	}
+	if len(data.Tags) > 0 {
+		out += fmt.Sprintf("Tags: %s\n", strings.Join(data.Tags, ", "))
+	}
+	if len(data.References) > 0 {
+		out += fmt.Sprintf("References: %s\n", strings.Join(data.References, ", "))
+	}
+	out += fmt.Sprintf("Description: %s\n", data.Description)
+	if data.Remediation != "" {
+		out += fmt.Sprintf("Remediation: %s\n", data.Remediation)
+	}
+	for _, param := range data.Parameters {
+		if value, ok := args[param.Name]; ok {
+			out += fmt.Sprintf("Parameter %s: %s\n", param.Name, value)
+		} else {
+			out += fmt.Sprintf("Parameter %s: %s (default)\n", param.Name, param.Default)
+		}
+	}
+	out += fmt.Sprintf("Version: %d\n", data.Version)
+	return out
 }
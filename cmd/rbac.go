@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"fmt"
+	"kube-review/rbac"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	rbacNamespace string
+	rbacOutput    string
+	rbacCmd       = &cobra.Command{
+		Use:   "rbac",
+		Short: "Analyse the RBAC permissions in config",
+		Long: "These commands resolve the RoleBindings and ClusterRoleBindings in the config to" +
+			" their Roles and ClusterRoles, including aggregated ClusterRoles, to show what each" +
+			" user, group and service account can do",
+	}
+	whoCanCmd = &cobra.Command{
+		Use:   "who-can VERB RESOURCE",
+		Short: "List the subjects that can use a verb on a resource",
+		Long: "This command lists every subject bound to a role allowing VERB on RESOURCE, e.g." +
+			" 'who-can get secrets -n kube-system'. RESOURCE is given as kubectl would, such as" +
+			" deployments.apps or pods/exec, or can be a non-resource URL such as /metrics." +
+			" Without -n access in any namespace is listed",
+		Args: cobra.ExactArgs(2),
+		Run:  whoCanRun,
+	}
+	permissionsCmd = &cobra.Command{
+		Use:   "permissions [SUBJECT]",
+		Short: "List the effective permissions of subjects",
+		Long: "This command lists what SUBJECT, given as User:NAME, Group:NAME or" +
+			" ServiceAccount:NAMESPACE/NAME, can do in each namespace. This includes what is" +
+			" granted to the groups it is implicitly part of, such as system:authenticated." +
+			" Without SUBJECT every bound subject is listed",
+		Args: cobra.MaximumNArgs(1),
+		Run:  permissionsRun,
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(rbacCmd)
+	rbacCmd.AddCommand(whoCanCmd)
+	rbacCmd.AddCommand(permissionsCmd)
+
+	rbacCmd.PersistentFlags().StringVarP(&rbacOutput, "output", "o", "", "File to write the output to instead of stdout")
+	whoCanCmd.Flags().StringVarP(&rbacNamespace, "namespace", "n", "", "Namespace the access is needed in")
+}
+
+func whoCanRun(cmd *cobra.Command, args []string) {
+	r := rbac.New(getConfig())
+	title := fmt.Sprintf("%s %s", args[0], args[1])
+	if rbacNamespace != "" {
+		title += " in " + rbacNamespace
+	}
+	out := fmt.Sprintf("== Who can %s ==\n", title)
+	permissions := r.WhoCan(args[0], args[1], rbacNamespace)
+	for _, permission := range permissions {
+		out += fmt.Sprintf("%s - %s -> %s%s\n", permission.Subject, permission.Binding, permission.Role, formatScope(permission))
+	}
+	if len(permissions) == 0 {
+		out += "No subjects found\n"
+	}
+	writeOutput(out+formatUnresolved(r), rbacOutput)
+}
+
+func permissionsRun(cmd *cobra.Command, args []string) {
+	r := rbac.New(getConfig())
+	subjects := r.Subjects()
+	if len(args) > 0 {
+		subject, err := rbac.ParseSubject(args[0])
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+		subjects = []rbac.Subject{subject}
+	}
+
+	var out string
+	for _, subject := range subjects {
+		out += fmt.Sprintf("== %s ==\n", subject)
+		accesses := r.Effective(subject)
+		for _, access := range accesses {
+			resource := access.Resource
+			if len(access.ResourceNames) > 0 {
+				resource += " (" + strings.Join(access.ResourceNames, ", ") + ")"
+			}
+			out += fmt.Sprintf("%s: %s [%s]\n", formatNamespace(access.Namespace), resource, strings.Join(access.Verbs, ", "))
+		}
+		if len(accesses) == 0 {
+			out += "No permissions found\n"
+		}
+		out += "\n"
+	}
+	writeOutput(out+formatUnresolved(r), rbacOutput)
+}
+
+// formatScope describes where a permission applies and, if restricted, the objects it is for
+func formatScope(permission rbac.Permission) string {
+	scope := " (" + formatNamespace(permission.Namespace)
+	if len(permission.Rule.ResourceNames) > 0 {
+		scope += ", only " + strings.Join(permission.Rule.ResourceNames, ", ")
+	}
+	return scope + ")"
+}
+
+func formatNamespace(namespace string) string {
+	if namespace == rbac.AllNamespaces {
+		return "all namespaces"
+	}
+	return namespace
+}
+
+// formatUnresolved warns about bindings whose permissions could not be included
+func formatUnresolved(r rbac.RBAC) string {
+	var out string
+	for _, binding := range r.Unresolved() {
+		out += fmt.Sprintf("Warning: %s refers to a role that is not in the config\n", binding)
+	}
+	return out
+}
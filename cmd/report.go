@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"fmt"
+	"kube-review/report"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	reportQueries  []string
+	reportFormat   string
+	reportFile     string
+	reportTemplate string
+	reportTitle    string
+	reportBaseline string
+	reportCmd      = &cobra.Command{
+		Use:   "report",
+		Short: "Create a report of query findings",
+		Long: "This will run the defined list of queries and render an HTML or Markdown report with a summary" +
+			" table and a section for each finding. The built in template can be replaced with --template",
+		Run: reportRun,
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(reportCmd)
+
+	reportCmd.Flags().StringArrayVarP(&reportQueries, "queries", "q", []string{}, "List of queries to run. Arguments can be given as 'name:param=value,param=value'")
+	reportCmd.Flags().StringVar(&reportFormat, "format", "html", "Report format, either html or markdown")
+	reportCmd.Flags().StringVarP(&reportFile, "output", "o", "", "File to write the report to instead of stdout")
+	reportCmd.Flags().StringVar(&reportTemplate, "template", "", "Go template file to use instead of the built in one for the format")
+	reportCmd.Flags().StringVar(&reportTitle, "title", "kube-review Report", "Title of the report")
+	reportCmd.Flags().StringVar(&reportBaseline, "baseline", "", "Results of a previous run (from query --format json) to show which findings are new, fixed or unchanged")
+}
+
+func reportRun(cmd *cobra.Command, args []string) {
+	if reportFormat != "html" && reportFormat != "markdown" {
+		fmt.Printf("'%s' is not a valid format. Must be either html or markdown\n", reportFormat)
+		os.Exit(1)
+	}
+	previous := loadBaseline(reportBaseline)
+	doc := report.NewDocument(reportTitle, kubeFile, runQueries(reportQueries))
+	if previous != nil {
+		doc.SetBaseline(previous)
+	}
+	output, err := report.Render(doc, reportFormat, reportTemplate)
+	if err != nil {
+		fmt.Println("Failed to create report - " + err.Error())
+		os.Exit(1)
+	}
+	writeOutput(output, reportFile)
+}
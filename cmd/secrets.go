@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"fmt"
+	"kube-review/secrets"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	secretsRule       string
+	secretsConfidence string
+	secretsOutput     string
+	secretsCmd        = &cobra.Command{
+		Use:   "secrets",
+		Short: "Find credentials outside of Secrets",
+		Long: "This command checks every string value in the config for private keys, cloud provider" +
+			" keys, tokens, JWTs, URLs with passwords, docker config JSON and high entropy strings." +
+			" Environment variable values and annotations are also checked after base64 decoding," +
+			" as are the values in the last-applied-configuration annotation. The data of Secrets" +
+			" is skipped. Each finding has a confidence and a masked preview of the credential",
+		Run: secretsRun,
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(secretsCmd)
+
+	secretsCmd.Flags().StringVarP(&secretsRule, "rule", "r", "", "Only show findings of rules fully matching this regex")
+	secretsCmd.Flags().StringVarP(&secretsConfidence, "confidence", "c", "Low", "Minimum confidence of a finding: Low, Medium or High")
+	secretsCmd.Flags().StringVarP(&secretsOutput, "output", "o", "", "File to write the output to instead of stdout")
+}
+
+func secretsRun(cmd *cobra.Command, args []string) {
+	confidence, err := secrets.ParseConfidence(secretsConfidence)
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+	nodeList := getConfig()
+	findings, err := secrets.Find(nodeList, secretsRule, confidence)
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+
+	out := ""
+	for _, finding := range findings {
+		location := nodeList.GetPath(finding.Index)
+		if finding.Location != "" {
+			location += " [" + finding.Location + "]"
+		}
+		out += fmt.Sprintf("%s (%s): %s - %s - %s\n", finding.Rule, finding.Confidence, finding.Resource, location, finding.Preview)
+	}
+	if len(findings) == 0 {
+		out += "No findings\n"
+	}
+	writeOutput(out, secretsOutput)
+}
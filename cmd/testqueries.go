@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"fmt"
+	"kube-review/querytest"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	showPassed     bool
+	testQueriesCmd = &cobra.Command{
+		Use:   "test-queries [test files or directories]",
+		Short: "Test queries against fixture manifests",
+		Long: "This will check every loaded query is a valid regex or expression and then run" +
+			" each test file against its query. A test file names a query and lists manifests" +
+			" that should and should not match, optionally with the node paths expected to match",
+		Run: testQueriesRun,
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(testQueriesCmd)
+
+	testQueriesCmd.Flags().BoolVarP(&showPassed, "verbose", "v", false, "Show passing tests as well as failures")
+}
+
+func testQueriesRun(cmd *cobra.Command, args []string) {
+	ql := getQueryList()
+	testFiles, err := querytest.LoadAll(args...)
+	if err != nil {
+		fmt.Println("Failed to load query tests - " + err.Error())
+		os.Exit(1)
+	}
+
+	results := querytest.RunAll(ql, testFiles)
+	failed := querytest.Failed(results)
+	for _, result := range results {
+		if showPassed || !result.Passed {
+			fmt.Println(result)
+		}
+	}
+	fmt.Printf("%d passed, %d failed\n", len(results)-len(failed), len(failed))
+	if len(failed) > 0 {
+		os.Exit(1)
+	}
+}
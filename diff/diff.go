@@ -0,0 +1,308 @@
+package diff
+
+import (
+	"fmt"
+	"kube-review/k8s"
+	"kube-review/nodelist"
+	"strings"
+)
+
+// ChangeEnum lists the ways a resource or field can differ between two configs
+type ChangeEnum int
+
+const (
+	// ADDED a
+	ADDED ChangeEnum = iota
+	// REMOVED a
+	REMOVED
+	// CHANGED a
+	CHANGED
+)
+
+func (ce ChangeEnum) String() string {
+	return [...]string{"Added", "Removed", "Changed"}[ce]
+}
+
+// Symbol returns the character used to mark the change in text output
+func (ce ChangeEnum) Symbol() string {
+	return [...]string{"+", "-", "~"}[ce]
+}
+
+func (ce ChangeEnum) colour() nodelist.ColourEnum {
+	return [...]nodelist.ColourEnum{nodelist.GREEN, nodelist.RED, nodelist.YELLOW}[ce]
+}
+
+// Change is a resource or field that differs between the old and new configs. Field is the
+// path below the resource, e.g. spec.replicas, or "" for the resource itself. Pointer is
+// the JSON Pointer to the node in the old config, or where it should be added. Indices are
+// of the node in each config's main view, or -1 if it is not in that config
+type Change struct {
+	Type     ChangeEnum
+	Field    string
+	Pointer  string
+	Old      string
+	New      string
+	OldIndex int
+	NewIndex int
+}
+
+// Resource is a Kubernetes object that was added, removed or changed. If changed,
+// Fields lists each field that differs
+type Resource struct {
+	nodelist.ResourceInfo
+	Change
+	Fields []Change
+}
+
+// Diff is every difference between two configs
+type Diff struct {
+	Resources []Resource
+}
+
+// Compare matches the resources of oldList and newList by kind, namespace and name, falling
+// back to their path if unnamed, and returns those that were added, removed or changed
+func Compare(oldList, newList *nodelist.NodeList) Diff {
+	c := comparer{oldList, newList}
+	newResources := k8s.NewIndex(newList).Resources()
+	newByKey := map[string]k8s.Resource{}
+	for _, resource := range newResources {
+		newByKey[getKey(resource)] = resource
+	}
+
+	var d Diff
+	matched := map[string]bool{}
+	for _, oldResource := range k8s.NewIndex(oldList).Resources() {
+		key := getKey(oldResource)
+		newResource, ok := newByKey[key]
+		if !ok {
+			d.Resources = append(d.Resources, Resource{oldResource.ResourceInfo, c.removed(oldResource.Start, ""), nil})
+			continue
+		}
+		matched[key] = true
+		fields := c.compare(oldResource.Start, newResource.Start, newResource.Path)
+		if len(fields) > 0 {
+			change := Change{CHANGED, "", oldList.GetPointer(oldResource.Start), oldList.GetNodeJSON(oldResource.Start),
+				newList.GetNodeJSON(newResource.Start), oldResource.Start, newResource.Start}
+			d.Resources = append(d.Resources, Resource{newResource.ResourceInfo, change, fields})
+		}
+	}
+	for _, newResource := range newResources {
+		if !matched[getKey(newResource)] {
+			change := c.added(newResource.Start, getParentPointer(newList.GetPointer(newResource.Start))+"/-", "")
+			d.Resources = append(d.Resources, Resource{newResource.ResourceInfo, change, nil})
+		}
+	}
+	return d
+}
+
+// Count returns the number of resources with each type of change
+func (d Diff) Count() map[ChangeEnum]int {
+	counts := map[ChangeEnum]int{}
+	for _, resource := range d.Resources {
+		counts[resource.Type]++
+	}
+	return counts
+}
+
+// Text lists each resource that differs, with the fields that changed below it. If colour is
+// true each line is coloured by its type of change
+func (d Diff) Text(colour bool) string {
+	var out string
+	for _, resource := range d.Resources {
+		out += formatLine(resource.Type, resource.ResourceInfo.String(), colour)
+		for _, field := range resource.Fields {
+			line := "    " + field.Field + ": "
+			switch field.Type {
+			case ADDED:
+				line += summarise(field.New)
+			case REMOVED:
+				line += summarise(field.Old)
+			case CHANGED:
+				line += summarise(field.Old) + " -> " + summarise(field.New)
+			}
+			out += formatLine(field.Type, line, colour)
+		}
+	}
+	counts := d.Count()
+	return out + fmt.Sprintf("%d added, %d removed, %d changed\n", counts[ADDED], counts[REMOVED], counts[CHANGED])
+}
+
+// Mark colours the nodes that differ in both lists and adds views of the added, removed
+// and changed resources to newList, with the old version of changed resources under "changed/old"
+func (d Diff) Mark(oldList, newList *nodelist.NodeList) error {
+	views := map[string][]int{}
+	oldViews := map[string][]int{}
+	for _, resource := range d.Resources {
+		switch resource.Type {
+		case ADDED:
+			markTree(newList, resource.NewIndex, ADDED)
+			views["added"] = append(views["added"], resource.NewIndex)
+		case REMOVED:
+			markTree(oldList, resource.OldIndex, REMOVED)
+			oldViews["removed"] = append(oldViews["removed"], resource.OldIndex)
+		case CHANGED:
+			newList.SetColour([]int{resource.NewIndex}, nodelist.YELLOW)
+			oldList.SetColour([]int{resource.OldIndex}, nodelist.YELLOW)
+			views["changed"] = append(views["changed"], resource.NewIndex)
+			oldViews["changed/old"] = append(oldViews["changed/old"], resource.OldIndex)
+		}
+		for _, field := range resource.Fields {
+			if field.NewIndex >= 0 {
+				markTree(newList, field.NewIndex, field.Type)
+			}
+			if field.OldIndex >= 0 {
+				markTree(oldList, field.OldIndex, field.Type)
+			}
+		}
+	}
+
+	for name, indices := range views {
+		if err := newList.AddView(name, *newList, indices); err != nil {
+			return err
+		}
+	}
+	for name, indices := range oldViews {
+		if err := newList.AddView(name, *oldList, indices); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/////////////////////////////////////////////////////////////////////////////////
+// PRIVATE FUNCTIONS
+
+type comparer struct {
+	old *nodelist.NodeList
+	new *nodelist.NodeList
+}
+
+// compare returns the changes below the nodes at oldIndex and newIndex. resourcePath is the
+// path of the resource in the new list, which is removed from the path of each field
+func (c comparer) compare(oldIndex, newIndex int, resourcePath string) []Change {
+	oldValue, newValue := c.old.GetRawValue(oldIndex), c.new.GetRawValue(newIndex)
+	if oldValue != newValue {
+		return []Change{{CHANGED, getField(c.new.GetPath(newIndex), resourcePath), c.old.GetPointer(oldIndex),
+			c.old.GetNodeJSON(oldIndex), c.new.GetNodeJSON(newIndex), oldIndex, newIndex}}
+	} else if oldValue == "{" {
+		return c.compareObjects(oldIndex, newIndex, resourcePath)
+	} else if oldValue == "[" {
+		return c.compareArrays(oldIndex, newIndex, resourcePath)
+	}
+	return nil
+}
+
+func (c comparer) compareObjects(oldIndex, newIndex int, resourcePath string) []Change {
+	newChildren := map[string]int{}
+	for _, child := range c.new.GetChildren(newIndex) {
+		newChildren[c.new.GetKey(child)] = child
+	}
+
+	var changes []Change
+	oldKeys := map[string]bool{}
+	for _, oldChild := range c.old.GetChildren(oldIndex) {
+		key := c.old.GetKey(oldChild)
+		oldKeys[key] = true
+		if newChild, ok := newChildren[key]; ok {
+			changes = append(changes, c.compare(oldChild, newChild, resourcePath)...)
+		} else {
+			field := getField(c.new.GetPath(newIndex)+"."+key, resourcePath)
+			changes = append(changes, c.removed(oldChild, field))
+		}
+	}
+	for _, newChild := range c.new.GetChildren(newIndex) {
+		if !oldKeys[c.new.GetKey(newChild)] {
+			pointer := c.old.GetPointer(oldIndex) + "/" + getLastSegment(c.new.GetPointer(newChild))
+			changes = append(changes, c.added(newChild, pointer, getField(c.new.GetPath(newChild), resourcePath)))
+		}
+	}
+	return changes
+}
+
+// compareArrays compares elements by position, so an element inserted part way through
+// shows as every following element having changed
+func (c comparer) compareArrays(oldIndex, newIndex int, resourcePath string) []Change {
+	oldChildren, newChildren := c.old.GetChildren(oldIndex), c.new.GetChildren(newIndex)
+	var changes []Change
+	for i, oldChild := range oldChildren {
+		if i < len(newChildren) {
+			changes = append(changes, c.compare(oldChild, newChildren[i], resourcePath)...)
+		} else {
+			field := getField(fmt.Sprintf("%s[%d]", c.new.GetPath(newIndex), i), resourcePath)
+			changes = append(changes, c.removed(oldChild, field))
+		}
+	}
+	for i := len(oldChildren); i < len(newChildren); i++ {
+		pointer := fmt.Sprintf("%s/%d", c.old.GetPointer(oldIndex), i)
+		changes = append(changes, c.added(newChildren[i], pointer, getField(c.new.GetPath(newChildren[i]), resourcePath)))
+	}
+	return changes
+}
+
+// removed creates a change for the node at oldIndex, and everything below it, being removed
+func (c comparer) removed(oldIndex int, field string) Change {
+	return Change{REMOVED, field, c.old.GetPointer(oldIndex), c.old.GetNodeJSON(oldIndex), "", oldIndex, -1}
+}
+
+// added creates a change for the node at newIndex, and everything below it, being added at pointer
+func (c comparer) added(newIndex int, pointer, field string) Change {
+	return Change{ADDED, field, pointer, "", c.new.GetNodeJSON(newIndex), -1, newIndex}
+}
+
+// getKey identifies a resource across configs by its kind, namespace and name, or by its
+// path if it has no name
+func getKey(resource k8s.Resource) string {
+	if resource.Name == "" {
+		return resource.Path
+	}
+	return resource.Kind + "/" + resource.Namespace + "/" + resource.Name
+}
+
+// getField returns path relative to the resource at resourcePath
+func getField(path, resourcePath string) string {
+	return strings.TrimPrefix(strings.TrimPrefix(path, resourcePath), ".")
+}
+
+func getParentPointer(pointer string) string {
+	if index := strings.LastIndex(pointer, "/"); index >= 0 {
+		return pointer[:index]
+	}
+	return ""
+}
+
+func getLastSegment(pointer string) string {
+	return pointer[strings.LastIndex(pointer, "/")+1:]
+}
+
+func markTree(nodeList *nodelist.NodeList, nodeIndex int, change ChangeEnum) {
+	var indices []int
+	for i := nodeIndex; i <= nodeList.GetLastChild(nodeIndex); i++ {
+		indices = append(indices, i)
+	}
+	nodeList.SetColour(indices, change.colour())
+}
+
+// summarise returns value, or a placeholder if it is an object or array
+func summarise(value string) string {
+	if strings.HasPrefix(value, "{") {
+		return "{...}"
+	} else if strings.HasPrefix(value, "[") {
+		return "[...]"
+	}
+	return value
+}
+
+const (
+	green  = "\033[32m"
+	red    = "\033[31m"
+	yellow = "\033[33m"
+	reset  = "\033[0m"
+)
+
+func formatLine(change ChangeEnum, line string, colour bool) string {
+	line = change.Symbol() + " " + line
+	if colour {
+		line = [...]string{green, red, yellow}[change] + line + reset
+	}
+	return line + "\n"
+}
@@ -0,0 +1,128 @@
+package diff_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"kube-review/diff"
+	"kube-review/nodelist"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func getLists(t *testing.T) (*nodelist.NodeList, *nodelist.NodeList) {
+	var lists []*nodelist.NodeList
+	for _, file := range []string{"../testdata/diff/old.json", "../testdata/diff/new.json"} {
+		rawJSON, err := ioutil.ReadFile(file)
+		if err != nil {
+			t.Fatalf("Failed to read %s - %s", file, err.Error())
+		}
+		nodeList, err := nodelist.NewNodeList(rawJSON, true)
+		if err != nil {
+			t.Fatalf("Failed to parse %s - %s", file, err.Error())
+		}
+		lists = append(lists, &nodeList)
+	}
+	return lists[0], lists[1]
+}
+
+func TestCompareMatchesResourcesByIdentity(t *testing.T) {
+	d := diff.Compare(getLists(t))
+	var actual []string
+	for _, resource := range d.Resources {
+		actual = append(actual, resource.Type.String()+" "+resource.ResourceInfo.String())
+	}
+	expected := []string{"Changed Deployment prod/web", "Removed Service prod/old", "Added Secret prod/new"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected %v but got %v", expected, actual)
+	}
+}
+
+func TestCompareListsChangedFields(t *testing.T) {
+	d := diff.Compare(getLists(t))
+	var actual []string
+	for _, field := range d.Resources[0].Fields {
+		actual = append(actual, field.Type.Symbol()+" "+field.Field)
+	}
+	expected := []string{
+		"- metadata.labels.app",
+		"+ metadata.labels.team",
+		"~ spec.replicas",
+		"~ spec.template.spec.containers[0].image",
+		"- spec.template.spec.containers[1]",
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected %v but got %v", expected, actual)
+	}
+}
+
+func TestTextSummarisesChanges(t *testing.T) {
+	actual := diff.Compare(getLists(t)).Text(false)
+	for _, expected := range []string{
+		"~ Deployment prod/web\n",
+		"~     spec.replicas: 1 -> 3\n",
+		"-     spec.template.spec.containers[1]: {...}\n",
+		"1 added, 1 removed, 1 changed\n",
+	} {
+		if !strings.Contains(actual, expected) {
+			t.Errorf("Expected output to contain '%s' but got '%s'", expected, actual)
+		}
+	}
+}
+
+func TestPatchRemovesFromTheEndFirst(t *testing.T) {
+	patch, err := diff.Compare(getLists(t)).Patch()
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err.Error())
+	}
+	var operations []map[string]interface{}
+	if err := json.Unmarshal([]byte(patch), &operations); err != nil {
+		t.Fatalf("Expected valid JSON but got %s", err.Error())
+	}
+	var actual []string
+	for _, operation := range operations {
+		actual = append(actual, operation["op"].(string)+" "+operation["path"].(string))
+	}
+	expected := []string{
+		"add /items/0/metadata/labels/team",
+		"replace /items/0/spec/replicas",
+		"replace /items/0/spec/template/spec/containers/0/image",
+		"add /items/-",
+		"remove /items/1",
+		"remove /items/0/spec/template/spec/containers/1",
+		"remove /items/0/metadata/labels/app",
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected %v but got %v", expected, actual)
+	}
+	if value := operations[3]["value"].(map[string]interface{}); value["kind"] != "Secret" {
+		t.Errorf("Expected the added Secret as the value but got %v", value)
+	}
+}
+
+func TestPatchOfIdenticalConfigsIsEmpty(t *testing.T) {
+	oldList, _ := getLists(t)
+	patch, _ := diff.Compare(oldList, oldList).Patch()
+	if expected := "[]\n"; patch != expected {
+		t.Errorf("Expected '%s' but got '%s'", expected, patch)
+	}
+}
+
+func TestMarkAddsViewsOfChangedResources(t *testing.T) {
+	oldList, newList := getLists(t)
+	if err := diff.Compare(oldList, newList).Mark(oldList, newList); err != nil {
+		t.Fatalf("Expected no error but got %s", err.Error())
+	}
+	expected := []string{"added", "changed", "changed/old", "main", "removed"}
+	if actual := newList.ListViews(); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected %v but got %v", expected, actual)
+	}
+	newList.SetView("removed")
+	newList.SetActiveNode(2)
+	if actual := newList.GetJSON(-1); !strings.Contains(actual, `"old"`) {
+		t.Errorf("Expected the removed Service but got %s", actual)
+	}
+	if actual := newList.GetColouredJSON(1); actual != "\033[31m{\033[0m" {
+		t.Errorf("Expected the removed Service to be red but got %q", actual)
+	}
+}
@@ -0,0 +1,46 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+type patchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// Patch returns a JSON Patch (RFC 6902) that turns the old config into the new one. Removals
+// come last, from the end of the document backwards, so earlier pointers are not shifted
+func (d Diff) Patch() (string, error) {
+	operations := []patchOperation{}
+	var removals []Change
+	for _, resource := range d.Resources {
+		changes := resource.Fields
+		if resource.Type != CHANGED {
+			changes = []Change{resource.Change}
+		}
+		for _, change := range changes {
+			switch change.Type {
+			case ADDED:
+				operations = append(operations, patchOperation{"add", change.Pointer, json.RawMessage(change.New)})
+			case CHANGED:
+				operations = append(operations, patchOperation{"replace", change.Pointer, json.RawMessage(change.New)})
+			case REMOVED:
+				removals = append(removals, change)
+			}
+		}
+	}
+	sort.SliceStable(removals, func(i, j int) bool { return removals[i].OldIndex > removals[j].OldIndex })
+	for _, removal := range removals {
+		operations = append(operations, patchOperation{"remove", removal.Pointer, nil})
+	}
+
+	patch, err := json.MarshalIndent(operations, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("Could not create patch - %s", err.Error())
+	}
+	return string(patch) + "\n", nil
+}
@@ -0,0 +1,128 @@
+package images
+
+import (
+	"fmt"
+	"kube-review/nodelist"
+	"kube-review/utils"
+	"regexp"
+)
+
+// Check is a risky way of referencing or pulling a container image
+type Check struct {
+	Name        string
+	Description string
+	detect      func(i Inventory, allowed *regexp.Regexp) []Finding
+}
+
+// Finding is a node flagged by a check. Resource is the workload, ConfigMap or Secret it is in
+type Finding struct {
+	Check     string
+	Resource  nodelist.ResourceInfo
+	Container string
+	Index     int
+	Message   string
+}
+
+var checks = []Check{
+	{"latest-tag", "images pulled by the latest tag or no tag, so what runs can change without the spec changing", func(i Inventory, allowed *regexp.Regexp) []Finding {
+		var findings []Finding
+		for _, image := range i.images {
+			if image.IsMutable() {
+				tag := image.Tag
+				if tag == "" {
+					tag = "no tag"
+				}
+				findings = append(findings, image.finding("latest-tag", image.ImageIndex, fmt.Sprintf("Image %s is pulled by %s", image.Reference, tag)))
+			}
+		}
+		return findings
+	}},
+	{"no-digest", "images not pinned to a digest", func(i Inventory, allowed *regexp.Regexp) []Finding {
+		var findings []Finding
+		for _, image := range i.images {
+			if image.Digest == "" {
+				findings = append(findings, image.finding("no-digest", image.ImageIndex, fmt.Sprintf("Image %s is not pinned to a digest", image.Reference)))
+			}
+		}
+		return findings
+	}},
+	{"untrusted-registry", "images from registries not fully matching the allowed registries regex", func(i Inventory, allowed *regexp.Regexp) []Finding {
+		var findings []Finding
+		for _, image := range i.images {
+			if allowed != nil && !allowed.MatchString(image.Registry) {
+				findings = append(findings, image.finding("untrusted-registry", image.ImageIndex, fmt.Sprintf("Registry %s is not allowed", image.Registry)))
+			}
+		}
+		return findings
+	}},
+	{"pull-policy", "imagePullPolicy Never, or IfNotPresent for an image pulled by the latest tag or no tag", func(i Inventory, allowed *regexp.Regexp) []Finding {
+		var findings []Finding
+		for _, image := range i.images {
+			if image.PullPolicyIndex < 0 {
+				continue
+			}
+			if image.PullPolicy == "Never" {
+				findings = append(findings, image.finding("pull-policy", image.PullPolicyIndex, "Image is never pulled, so whatever is cached on the node runs without registry credentials being checked"))
+			} else if image.PullPolicy == "IfNotPresent" && image.IsMutable() {
+				findings = append(findings, image.finding("pull-policy", image.PullPolicyIndex, fmt.Sprintf("Image %s is mutable but only pulled if not cached, so nodes can run different versions", image.Reference)))
+			}
+		}
+		return findings
+	}},
+	{"insecure-registry", "registries configured without TLS and the images pulled from them", func(i Inventory, allowed *regexp.Regexp) []Finding {
+		var findings []Finding
+		hosts := map[string]bool{}
+		for _, registry := range i.insecure {
+			hosts[registry.Host] = true
+			findings = append(findings, Finding{"insecure-registry", registry.Resource.ResourceInfo, "", registry.Index,
+				fmt.Sprintf("Registry %s is configured without TLS by %s", registry.Host, registry.Source)})
+		}
+		for _, image := range i.images {
+			if image.Scheme == "http" {
+				findings = append(findings, image.finding("insecure-registry", image.ImageIndex, fmt.Sprintf("Image %s is pulled over http", image.Reference)))
+			} else if hosts[image.Registry] {
+				findings = append(findings, image.finding("insecure-registry", image.ImageIndex, fmt.Sprintf("Registry %s is configured without TLS", image.Registry)))
+			}
+		}
+		return findings
+	}},
+}
+
+// Checks returns every built in check
+func Checks() []Check {
+	return checks
+}
+
+// GetCheck returns the check called name
+func GetCheck(name string) (Check, error) {
+	var names []string
+	for _, check := range checks {
+		names = append(names, check.Name)
+	}
+	index, err := utils.FindName("image check", name, names)
+	if err != nil {
+		return Check{}, err
+	}
+	return checks[index], nil
+}
+
+// Detect returns the findings of check. Registries is a regex the whole registry of an image must
+// match for the untrusted-registry check to allow it. An empty regex allows any registry
+func (i Inventory) Detect(name, registries string) ([]Finding, error) {
+	check, err := GetCheck(name)
+	if err != nil {
+		return nil, err
+	}
+	allowed, err := utils.CompileFullMatch(registries)
+	if err != nil {
+		return nil, err
+	}
+	return check.detect(i, allowed), nil
+}
+
+/////////////////////////////////////////////////////////////////////////////////
+// PRIVATE FUNCTIONS
+
+func (ci ContainerImage) finding(check string, index int, message string) Finding {
+	return Finding{check, ci.ResourceInfo, ci.Container.Name, index, message}
+}
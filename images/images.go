@@ -0,0 +1,224 @@
+package images
+
+import (
+	"encoding/json"
+	"kube-review/k8s"
+	"kube-review/utils"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// DefaultRegistry is the registry of images that do not name one
+const DefaultRegistry = "docker.io"
+
+// Image is a parsed container image reference. Tag and Digest are empty if not given, and
+// Scheme is only set if the reference wrongly includes one, e.g. http
+type Image struct {
+	Reference  string
+	Scheme     string
+	Registry   string
+	Repository string
+	Tag        string
+	Digest     string
+}
+
+// Parse splits an image reference such as registry.example.com:5000/team/app:1.2@sha256:...
+// into its parts. Images without a registry are from Docker Hub, where official images are
+// in the library repository, e.g. nginx is docker.io/library/nginx
+func Parse(reference string) Image {
+	image := Image{Reference: reference, Registry: DefaultRegistry}
+	name := strings.TrimSpace(reference)
+	if index := strings.Index(name, "://"); index >= 0 {
+		image.Scheme, name = strings.ToLower(name[:index]), name[index+3:]
+	}
+	if index := strings.Index(name, "@"); index >= 0 {
+		image.Digest, name = name[index+1:], name[:index]
+	}
+	if index := strings.LastIndex(name, ":"); index > strings.LastIndex(name, "/") {
+		image.Tag, name = name[index+1:], name[:index]
+	}
+	if index := strings.Index(name, "/"); index >= 0 {
+		if host := name[:index]; strings.ContainsAny(host, ".:") || host == "localhost" {
+			image.Registry, name = strings.ToLower(host), name[index+1:]
+		}
+	}
+	if image.Registry == DefaultRegistry && !strings.Contains(name, "/") {
+		name = "library/" + name
+	}
+	image.Repository = name
+	return image
+}
+
+// IsMutable returns true if the image is pulled by the latest tag, or no tag, which means latest
+func (i Image) IsMutable() bool {
+	return i.Digest == "" && (i.Tag == "" || i.Tag == "latest")
+}
+
+// DefaultPullPolicy is the imagePullPolicy the API server gives a container that does not set one
+func (i Image) DefaultPullPolicy() string {
+	if i.Tag == "latest" || (i.Tag == "" && i.Digest == "") {
+		return "Always"
+	}
+	return "IfNotPresent"
+}
+
+// ContainerImage is the image of a container. PullPolicyIndex is -1 if the container does not
+// set imagePullPolicy, in which case PullPolicy is the default
+type ContainerImage struct {
+	k8s.Container
+	Image
+	ImageIndex      int
+	PullPolicy      string
+	PullPolicyIndex int
+}
+
+// InsecureRegistry is a registry configured to be pulled from without TLS, such as an http
+// entry in a docker config, daemon.json insecure-registries or a containerd mirror endpoint
+type InsecureRegistry struct {
+	Host     string
+	Resource k8s.Resource
+	Index    int
+	Source   string
+}
+
+// Inventory holds the image of every container in a config and the registries it configures
+// without TLS
+type Inventory struct {
+	images   []ContainerImage
+	insecure []InsecureRegistry
+}
+
+// New builds the inventory of the images of every container, init container and ephemeral
+// container in nodeList, and finds insecure registries in ConfigMaps and Secrets
+func New(nodeList k8s.NodeList) Inventory {
+	inventory := Inventory{}
+	index := k8s.NewIndex(nodeList)
+	for _, container := range index.Containers(nodeList) {
+		imageIndex, ok := nodeList.GetChild(container.Index, "image")
+		if !ok {
+			continue
+		}
+		image := Parse(nodeList.GetValue(imageIndex))
+		containerImage := ContainerImage{container, image, imageIndex, image.DefaultPullPolicy(), -1}
+		if policy, ok := nodeList.GetChild(container.Index, "imagePullPolicy"); ok {
+			containerImage.PullPolicy, containerImage.PullPolicyIndex = nodeList.GetValue(policy), policy
+		}
+		inventory.images = append(inventory.images, containerImage)
+	}
+	for _, resource := range index.Resources() {
+		if resource.Kind == "ConfigMap" || resource.Kind == "Secret" {
+			inventory.insecure = append(inventory.insecure, findInsecure(nodeList, resource)...)
+		}
+	}
+	return inventory
+}
+
+// Images returns the image of every container
+func (i Inventory) Images() []ContainerImage {
+	return i.images
+}
+
+// InsecureRegistries returns the registries configured without TLS
+func (i Inventory) InsecureRegistries() []InsecureRegistry {
+	return i.insecure
+}
+
+/////////////////////////////////////////////////////////////////////////////////
+// PRIVATE FUNCTIONS
+
+var (
+	httpHostRegex     = regexp.MustCompile(`http://([^/"'\s\]]+)`)
+	endpointRegex     = regexp.MustCompile(`(?m)^\s*(?:endpoint\s*=\s*\[[^\]]*|server\s*=\s*"|\[host\.")http://`)
+	skipVerifyRegex   = regexp.MustCompile(`registry\.configs\."([^"]+)"\.tls\][^\[]*insecure_skip_verify\s*=\s*true`)
+	hostsSkipTLSRegex = regexp.MustCompile(`\[host\."https?://([^/"]+)[^"]*"\][^\[]*skip_verify\s*=\s*true`)
+)
+
+// findInsecure returns the registries configured without TLS in the data of a ConfigMap or
+// Secret, decoding the base64 data of Secrets
+func findInsecure(nodeList k8s.NodeList, resource k8s.Resource) []InsecureRegistry {
+	var registries []InsecureRegistry
+	for _, key := range []string{"data", "stringData"} {
+		data, ok := nodeList.GetChild(resource.Start, key)
+		if !ok {
+			continue
+		}
+		for _, child := range nodeList.GetChildren(data) {
+			value := nodeList.GetValue(child)
+			if resource.Kind == "Secret" && key == "data" {
+				decoded, ok := utils.DecodeBase64Text(value, 4)
+				if !ok {
+					continue
+				}
+				value = decoded
+			}
+			for _, found := range parseInsecure(value) {
+				registries = append(registries, InsecureRegistry{found.host, resource, child, found.source})
+			}
+		}
+	}
+	return registries
+}
+
+type insecureHost struct {
+	host   string
+	source string
+}
+
+// parseInsecure returns the registries a docker config, daemon.json or containerd config
+// allows to be pulled from over http or without verifying their certificate
+func parseInsecure(value string) []insecureHost {
+	var hosts []insecureHost
+	var config struct {
+		Auths              map[string]interface{} `json:"auths"`
+		InsecureRegistries []string               `json:"insecure-registries"`
+	}
+	if err := json.Unmarshal([]byte(value), &config); err == nil {
+		for _, registry := range getSortedKeys(config.Auths) {
+			if strings.HasPrefix(strings.ToLower(registry), "http://") {
+				hosts = append(hosts, insecureHost{hostOf(registry), "docker config auth over http"})
+			}
+		}
+		for _, registry := range config.InsecureRegistries {
+			hosts = append(hosts, insecureHost{hostOf(registry), "daemon.json insecure-registries"})
+		}
+		return hosts
+	}
+	for _, line := range strings.Split(value, "\n") {
+		if endpointRegex.MatchString(line) {
+			for _, match := range httpHostRegex.FindAllStringSubmatch(line, -1) {
+				hosts = append(hosts, insecureHost{strings.ToLower(match[1]), "containerd endpoint over http"})
+			}
+		}
+	}
+	for _, regex := range []*regexp.Regexp{skipVerifyRegex, hostsSkipTLSRegex} {
+		for _, match := range regex.FindAllStringSubmatch(value, -1) {
+			hosts = append(hosts, insecureHost{strings.ToLower(match[1]), "containerd skips TLS verification"})
+		}
+	}
+	return hosts
+}
+
+// hostOf returns the host and port of a registry given as a URL or host
+func hostOf(registry string) string {
+	host := strings.ToLower(registry)
+	if index := strings.Index(host, "://"); index >= 0 {
+		host = host[index+3:]
+	}
+	if index := strings.Index(host, "/"); index >= 0 {
+		host = host[:index]
+	}
+	if host == "index.docker.io" || host == "registry-1.docker.io" {
+		return DefaultRegistry
+	}
+	return host
+}
+
+func getSortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,92 @@
+package images_test
+
+import (
+	"kube-review/images"
+	"kube-review/nodelist"
+	"testing"
+)
+
+// imagesJSON is a Deployment with a latest sidecar, a Pod pulled from an insecure mirror and the
+// containerd config that makes it insecure
+const imagesJSON = `{"apiVersion": "v1", "kind": "List", "items": [
+	{"kind": "Deployment", "metadata": {"name": "api", "namespace": "prod"}, "spec": {"template": {"spec": {"containers": [
+		{"name": "api", "image": "registry.example.com/team/api:1.0@sha256:abc"},
+		{"name": "proxy", "image": "envoyproxy/envoy:latest", "imagePullPolicy": "IfNotPresent"}]}}}},
+	{"kind": "Pod", "metadata": {"name": "batch", "namespace": "prod"}, "spec": {"containers": [
+		{"name": "batch", "image": "mirror.internal:5000/batch:2.0", "imagePullPolicy": "Never"}]}},
+	{"kind": "ConfigMap", "metadata": {"name": "containerd", "namespace": "kube-system"}, "data": {
+		"config.toml": "[plugins.\"io.containerd.grpc.v1.cri\".registry.mirrors.\"mirror.internal:5000\"]\n  endpoint = [\"http://mirror.internal:5000\"]\n"}}
+]}`
+
+func getInventory(t *testing.T) (images.Inventory, nodelist.NodeList) {
+	nl, err := nodelist.NewNodeList([]byte(imagesJSON), true)
+	if err != nil {
+		t.Fatalf("Failed to parse test data - %s", err.Error())
+	}
+	return images.New(nl), nl
+}
+
+func TestParseSplitsImageReferences(t *testing.T) {
+	tests := map[string]images.Image{
+		"nginx":                      {Registry: "docker.io", Repository: "library/nginx"},
+		"bitnami/redis:7.2":          {Registry: "docker.io", Repository: "bitnami/redis", Tag: "7.2"},
+		"localhost/app":              {Registry: "localhost", Repository: "app"},
+		"quay.io/org/app@sha256:abc": {Registry: "quay.io", Repository: "org/app", Digest: "sha256:abc"},
+		"REGISTRY.io:5000/app:1.0":   {Registry: "registry.io:5000", Repository: "app", Tag: "1.0"},
+		"http://registry.lab/app:v1": {Scheme: "http", Registry: "registry.lab", Repository: "app", Tag: "v1"},
+	}
+	for reference, expected := range tests {
+		expected.Reference = reference
+		if actual := images.Parse(reference); actual != expected {
+			t.Errorf("Expected %+v but got %+v", expected, actual)
+		}
+	}
+}
+
+func TestNewDefaultsPullPolicy(t *testing.T) {
+	inventory, _ := getInventory(t)
+	expected := []string{"IfNotPresent", "IfNotPresent", "Never"}
+	actual := inventory.Images()
+	if len(actual) != len(expected) {
+		t.Fatalf("Expected %d images but got %d", len(expected), len(actual))
+	}
+	for i, policy := range expected {
+		if actual[i].PullPolicy != policy {
+			t.Errorf("Expected pull policy %s for %s but got %s", policy, actual[i].Reference, actual[i].PullPolicy)
+		}
+	}
+	if actual[0].PullPolicyIndex != -1 {
+		t.Errorf("Expected the default pull policy to have no index but got %d", actual[0].PullPolicyIndex)
+	}
+}
+
+func TestDetectFindsImagesFromInsecureRegistries(t *testing.T) {
+	inventory, nl := getInventory(t)
+	findings, err := inventory.Detect("insecure-registry", "")
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err.Error())
+	}
+	expected := []string{"items[2].data.config.toml", "items[1].spec.containers[0].image"}
+	if len(findings) != len(expected) {
+		t.Fatalf("Expected %d findings but got %v", len(expected), findings)
+	}
+	for i, path := range expected {
+		if actual := nl.GetPath(findings[i].Index); actual != path {
+			t.Errorf("Expected finding at %s but got %s", path, actual)
+		}
+	}
+}
+
+func TestDetectMatchesWholeRegistry(t *testing.T) {
+	inventory, _ := getInventory(t)
+	findings, _ := inventory.Detect("untrusted-registry", `registry\.example\.com|mirror\.internal`)
+	if len(findings) != 2 {
+		t.Fatalf("Expected 2 findings but got %v", findings)
+	}
+	if findings[0].Container != "proxy" || findings[1].Container != "batch" {
+		t.Errorf("Expected proxy and batch to be untrusted but got %s and %s", findings[0].Container, findings[1].Container)
+	}
+	if _, err := inventory.Detect("unknown", ""); err == nil {
+		t.Errorf("Expected an error for an unknown check")
+	}
+}
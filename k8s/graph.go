@@ -0,0 +1,286 @@
+package k8s
+
+// EdgeEnum is the kind of relationship between two resources
+type EdgeEnum int
+
+const (
+	// SELECTS a
+	SELECTS EdgeEnum = iota
+	// OWNS a
+	OWNS
+	// MOUNTS a
+	MOUNTS
+	// REFERENCES a
+	REFERENCES
+	// USES a
+	USES
+	// BINDS a
+	BINDS
+)
+
+var edgeNames = [...]string{"selects", "owns", "mounts", "references", "uses", "binds"}
+
+func (ee EdgeEnum) String() string {
+	return edgeNames[ee]
+}
+
+// Inverse returns the name of the edge as seen from the resource it points to, e.g. selected-by
+func (ee EdgeEnum) Inverse() string {
+	return [...]string{"selected-by", "owned-by", "mounted-by", "referenced-by", "used-by", "bound-by"}[ee]
+}
+
+// EdgeNames returns the name of every kind of edge
+func EdgeNames() []string {
+	return edgeNames[:]
+}
+
+// Edge is a relationship From one resource To another. Index is the node that creates it,
+// such as the selector, ownerReference, volume or subject
+type Edge struct {
+	Type  EdgeEnum
+	From  Resource
+	To    Resource
+	Index int
+}
+
+// Graph holds the relationships between the resources in an Index
+type Graph struct {
+	edges []Edge
+}
+
+// selectorPaths is where each kind keeps the selector for the pods it manages or applies to.
+// Services and ReplicationControllers use a map of labels rather than a label selector
+var selectorPaths = map[string][]string{
+	"Service":               {"spec", "selector"},
+	"ReplicationController": {"spec", "selector"},
+	"Deployment":            {"spec", "selector"},
+	"DaemonSet":             {"spec", "selector"},
+	"StatefulSet":           {"spec", "selector"},
+	"ReplicaSet":            {"spec", "selector"},
+	"Job":                   {"spec", "selector"},
+	"PodDisruptionBudget":   {"spec", "selector"},
+	"NetworkPolicy":         {"spec", "podSelector"},
+}
+
+// Graph links the resources in the index by the pods their selectors match, ownerReferences,
+// the ConfigMaps, Secrets, PersistentVolumeClaims and ServiceAccounts pods refer to, and the
+// roles and service accounts of bindings. Only resources in the index are linked
+func (i Index) Graph(nodeList NodeList) Graph {
+	g := Graph{}
+	templates := i.PodTemplates(nodeList)
+	for _, resource := range i.resources {
+		g.addOwners(nodeList, i, resource)
+		g.addSelected(nodeList, resource, templates)
+		switch resource.Kind {
+		case "ServiceAccount":
+			for _, key := range []string{"secrets", "imagePullSecrets"} {
+				g.addNamed(nodeList, i, REFERENCES, resource, "Secret", key, "name")
+			}
+		case "PersistentVolumeClaim":
+			if volume, ok := nodeList.GetChild(resource.Start, "spec", "volumeName"); ok {
+				g.add(i, REFERENCES, resource, "PersistentVolume", "", nodeList.GetValue(volume), volume)
+			}
+		case "Ingress":
+			g.addIngressBackends(nodeList, i, resource)
+		case "RoleBinding", "ClusterRoleBinding":
+			g.addBinding(nodeList, i, resource)
+		}
+	}
+	for _, template := range templates {
+		g.addPodReferences(nodeList, i, template)
+	}
+	return g
+}
+
+// Edges returns every edge in the graph
+func (g Graph) Edges() []Edge {
+	return g.edges
+}
+
+// Related returns the edges from and to resource
+func (g Graph) Related(resource Resource) []Edge {
+	var related []Edge
+	for _, edge := range g.edges {
+		if edge.From.Start == resource.Start || edge.To.Start == resource.Start {
+			related = append(related, edge)
+		}
+	}
+	return related
+}
+
+/////////////////////////////////////////////////////////////////////////////////
+// PRIVATE FUNCTIONS
+
+// add links from to the resource with kind, namespace and name if it is in the index
+func (g *Graph) add(i Index, edge EdgeEnum, from Resource, kind, namespace, name string, index int) {
+	if to, ok := i.Get(kind, namespace, name); ok && to.Start != from.Start {
+		g.edges = append(g.edges, Edge{edge, from, to, index})
+	}
+}
+
+// addNamed links from to the kind named by the key of each element of the array at path
+func (g *Graph) addNamed(nodeList NodeList, i Index, edge EdgeEnum, from Resource, kind, path, key string) {
+	if parent, ok := nodeList.GetChild(from.Start, path); ok {
+		for _, child := range nodeList.GetChildren(parent) {
+			g.add(i, edge, from, kind, from.Namespace, nodeList.GetChildValue(child, key), child)
+		}
+	}
+}
+
+func (g *Graph) addOwners(nodeList NodeList, i Index, resource Resource) {
+	owners, ok := nodeList.GetChild(resource.Start, "metadata", "ownerReferences")
+	if !ok {
+		return
+	}
+	for _, owner := range nodeList.GetChildren(owners) {
+		kind, name := nodeList.GetChildValue(owner, "kind"), nodeList.GetChildValue(owner, "name")
+		// Owners are in the same namespace or, like Nodes, cluster scoped
+		for _, namespace := range []string{resource.Namespace, ""} {
+			if from, ok := i.Get(kind, namespace, name); ok {
+				g.edges = append(g.edges, Edge{OWNS, from, resource, owner})
+				break
+			}
+		}
+	}
+}
+
+// addSelected links a resource with a selector to the pods and pod templates of other
+// resources in its namespace that it matches. Services and ReplicationControllers without a
+// selector match nothing
+func (g *Graph) addSelected(nodeList NodeList, resource Resource, templates []PodTemplate) {
+	path, ok := selectorPaths[resource.Kind]
+	if !ok {
+		return
+	}
+	selector, ok := nodeList.GetChild(resource.Start, path...)
+	if !ok {
+		return
+	}
+	isMap := resource.Kind == "Service" || resource.Kind == "ReplicationController"
+	if isMap && len(nodeList.GetChildren(selector)) == 0 {
+		return
+	}
+	for _, template := range templates {
+		if template.Start == resource.Start || template.Namespace != resource.Namespace {
+			continue
+		}
+		labels := template.PodLabels(nodeList)
+		if (isMap && matchesMap(nodeList, selector, labels)) || (!isMap && MatchesSelector(nodeList, selector, labels)) {
+			g.edges = append(g.edges, Edge{SELECTS, resource, template.Resource, selector})
+		}
+	}
+}
+
+// addPodReferences links a workload to what its pod spec mounts as volumes, references in the
+// environment of its containers or image pull secrets and runs as
+func (g *Graph) addPodReferences(nodeList NodeList, i Index, template PodTemplate) {
+	from := template.Resource
+	if volumes, ok := nodeList.GetChild(template.Spec, "volumes"); ok {
+		for _, volume := range nodeList.GetChildren(volumes) {
+			g.addVolume(nodeList, i, from, volume)
+			if sources, ok := nodeList.GetChild(volume, "projected", "sources"); ok {
+				for _, source := range nodeList.GetChildren(sources) {
+					g.addVolume(nodeList, i, from, source)
+				}
+			}
+		}
+	}
+	for _, container := range GetContainers(nodeList, template.Spec) {
+		if env, ok := nodeList.GetChild(container, "env"); ok {
+			for _, variable := range nodeList.GetChildren(env) {
+				for _, ref := range []struct{ key, kind string }{{"configMapKeyRef", "ConfigMap"}, {"secretKeyRef", "Secret"}} {
+					if index, ok := nodeList.GetChild(variable, "valueFrom", ref.key); ok {
+						g.add(i, REFERENCES, from, ref.kind, from.Namespace, nodeList.GetChildValue(index, "name"), variable)
+					}
+				}
+			}
+		}
+		if envFrom, ok := nodeList.GetChild(container, "envFrom"); ok {
+			for _, source := range nodeList.GetChildren(envFrom) {
+				for _, ref := range []struct{ key, kind string }{{"configMapRef", "ConfigMap"}, {"secretRef", "Secret"}} {
+					if index, ok := nodeList.GetChild(source, ref.key); ok {
+						g.add(i, REFERENCES, from, ref.kind, from.Namespace, nodeList.GetChildValue(index, "name"), source)
+					}
+				}
+			}
+		}
+	}
+	if secrets, ok := nodeList.GetChild(template.Spec, "imagePullSecrets"); ok {
+		for _, secret := range nodeList.GetChildren(secrets) {
+			g.add(i, REFERENCES, from, "Secret", from.Namespace, nodeList.GetChildValue(secret, "name"), secret)
+		}
+	}
+	for _, key := range []string{"serviceAccountName", "serviceAccount"} {
+		if account, ok := nodeList.GetChild(template.Spec, key); ok {
+			g.add(i, USES, from, "ServiceAccount", from.Namespace, nodeList.GetValue(account), account)
+			break
+		}
+	}
+}
+
+// addVolume links from to the ConfigMap, Secret or PersistentVolumeClaim of a volume or
+// projected volume source
+func (g *Graph) addVolume(nodeList NodeList, i Index, from Resource, volume int) {
+	for _, source := range []struct{ key, kind, name string }{
+		{"configMap", "ConfigMap", "name"},
+		{"secret", "Secret", "secretName"},
+		{"secret", "Secret", "name"},
+		{"persistentVolumeClaim", "PersistentVolumeClaim", "claimName"},
+	} {
+		if name, ok := nodeList.GetChild(volume, source.key, source.name); ok {
+			g.add(i, MOUNTS, from, source.kind, from.Namespace, nodeList.GetValue(name), volume)
+		}
+	}
+}
+
+func (g *Graph) addIngressBackends(nodeList NodeList, i Index, resource Resource) {
+	if backend, ok := nodeList.GetChild(resource.Start, "spec", "defaultBackend", "service", "name"); ok {
+		g.add(i, REFERENCES, resource, "Service", resource.Namespace, nodeList.GetValue(backend), backend)
+	}
+	rules, ok := nodeList.GetChild(resource.Start, "spec", "rules")
+	if !ok {
+		return
+	}
+	for _, rule := range nodeList.GetChildren(rules) {
+		if paths, ok := nodeList.GetChild(rule, "http", "paths"); ok {
+			for _, path := range nodeList.GetChildren(paths) {
+				if backend, ok := nodeList.GetChild(path, "backend", "service", "name"); ok {
+					g.add(i, REFERENCES, resource, "Service", resource.Namespace, nodeList.GetValue(backend), backend)
+				}
+			}
+		}
+	}
+}
+
+// addBinding links a binding to its role and the service accounts it binds it to
+func (g *Graph) addBinding(nodeList NodeList, i Index, resource Resource) {
+	if roleRef, ok := nodeList.GetChild(resource.Start, "roleRef"); ok {
+		kind, namespace := nodeList.GetChildValue(roleRef, "kind"), resource.Namespace
+		if kind == "ClusterRole" {
+			namespace = ""
+		}
+		g.add(i, BINDS, resource, kind, namespace, nodeList.GetChildValue(roleRef, "name"), roleRef)
+	}
+	if subjects, ok := nodeList.GetChild(resource.Start, "subjects"); ok {
+		for _, subject := range nodeList.GetChildren(subjects) {
+			if nodeList.GetChildValue(subject, "kind") != "ServiceAccount" {
+				continue
+			}
+			namespace := nodeList.GetChildValue(subject, "namespace")
+			if namespace == "" {
+				namespace = resource.Namespace
+			}
+			g.add(i, BINDS, resource, "ServiceAccount", namespace, nodeList.GetChildValue(subject, "name"), subject)
+		}
+	}
+}
+
+// matchesMap returns true if labels have every label in the map at selector
+func matchesMap(nodeList NodeList, selector int, labels map[string]string) bool {
+	for _, child := range nodeList.GetChildren(selector) {
+		if value, ok := labels[nodeList.GetKey(child)]; !ok || value != nodeList.GetValue(child) {
+			return false
+		}
+	}
+	return true
+}
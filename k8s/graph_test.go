@@ -0,0 +1,58 @@
+package k8s_test
+
+import (
+	"reflect"
+	"testing"
+)
+
+const graphJSON = `{"apiVersion": "v1", "kind": "List", "items": [
+	{"kind": "Deployment", "metadata": {"name": "web", "namespace": "prod"}, "spec": {
+		"selector": {"matchLabels": {"app": "web"}},
+		"template": {"metadata": {"labels": {"app": "web"}}, "spec": {"serviceAccountName": "web",
+			"volumes": [{"name": "tls", "secret": {"secretName": "tls"}}],
+			"containers": [{"name": "web", "envFrom": [{"configMapRef": {"name": "settings"}}]}]}}}},
+	{"kind": "ReplicaSet", "metadata": {"name": "web-1", "namespace": "prod",
+		"ownerReferences": [{"kind": "Deployment", "name": "web"}]}, "spec": {
+		"template": {"metadata": {"labels": {"app": "web", "hash": "1"}}, "spec": {"containers": []}}}},
+	{"kind": "Service", "metadata": {"name": "web", "namespace": "prod"}, "spec": {"selector": {"app": "web"}}},
+	{"kind": "Service", "metadata": {"name": "other", "namespace": "dev"}, "spec": {"selector": {"app": "web"}}},
+	{"kind": "Secret", "metadata": {"name": "tls", "namespace": "prod"}},
+	{"kind": "ConfigMap", "metadata": {"name": "settings", "namespace": "prod"}},
+	{"kind": "ServiceAccount", "metadata": {"name": "web", "namespace": "prod"}},
+	{"kind": "RoleBinding", "metadata": {"name": "web", "namespace": "prod"},
+		"roleRef": {"kind": "Role", "name": "missing"}, "subjects": [{"kind": "ServiceAccount", "name": "web"}]}
+]}`
+
+func TestGraphLinksSelectorsOwnersAndReferences(t *testing.T) {
+	index, nodeList := getIndex(t, graphJSON)
+	var actual []string
+	for _, edge := range index.Graph(&nodeList).Edges() {
+		actual = append(actual, edge.From.Name+" "+edge.Type.String()+" "+edge.To.Kind+" "+nodeList.GetPath(edge.Index))
+	}
+	expected := []string{
+		"web selects ReplicaSet items[0].spec.selector",
+		"web owns ReplicaSet items[1].metadata.ownerReferences[0]",
+		"web selects Deployment items[2].spec.selector",
+		"web selects ReplicaSet items[2].spec.selector",
+		"web binds ServiceAccount items[7].subjects[0]",
+		"web mounts Secret items[0].spec.template.spec.volumes[0]",
+		"web references ConfigMap items[0].spec.template.spec.containers[0].envFrom[0]",
+		"web uses ServiceAccount items[0].spec.template.spec.serviceAccountName",
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected %v but got %v", expected, actual)
+	}
+}
+
+func TestRelatedIncludesEdgesInBothDirections(t *testing.T) {
+	index, nodeList := getIndex(t, graphJSON)
+	account, _ := index.Get("ServiceAccount", "prod", "web")
+	var actual []string
+	for _, edge := range index.Graph(&nodeList).Related(account) {
+		actual = append(actual, edge.From.Kind+" "+edge.Type.Inverse())
+	}
+	expected := []string{"RoleBinding bound-by", "Deployment used-by"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected %v but got %v", expected, actual)
+	}
+}
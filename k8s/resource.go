@@ -0,0 +1,152 @@
+package k8s
+
+import (
+	"kube-review/nodelist"
+	"kube-review/utils"
+	"regexp"
+)
+
+// NodeList is what an Index needs from nodelist.NodeList
+type NodeList interface {
+	GetResourceIndices() []int
+	GetChild(nodeIndex int, keys ...string) (int, bool)
+	GetChildren(nodeIndex int) []int
+	GetKey(nodeIndex int) string
+	GetValue(nodeIndex int) string
+	GetChildValue(nodeIndex int, keys ...string) string
+	GetLastChild(nodeIndex int) int
+	GetPath(nodeIndex int) string
+}
+
+// OwnerReference points to the object that controls a resource
+type OwnerReference struct {
+	APIVersion string
+	Kind       string
+	Name       string
+	UID        string
+	Controller bool
+}
+
+// Resource is a handle to a Kubernetes object in a NodeList. The object's nodes are
+// Start to End inclusive, in the view the Index was created from
+type Resource struct {
+	nodelist.ResourceInfo
+	APIVersion      string
+	Labels          map[string]string
+	Annotations     map[string]string
+	OwnerReferences []OwnerReference
+	Start           int
+	End             int
+}
+
+// Contains returns true if nodeIndex is part of the resource
+func (r Resource) Contains(nodeIndex int) bool {
+	return nodeIndex >= r.Start && nodeIndex <= r.End
+}
+
+// Index holds every resource in a NodeList
+type Index struct {
+	resources []Resource
+}
+
+// NewIndex creates an Index of the objects in the items array of nodeList's current view
+func NewIndex(nodeList NodeList) Index {
+	var resources []Resource
+	for _, index := range nodeList.GetResourceIndices() {
+		resources = append(resources, newResource(nodeList, index))
+	}
+	return Index{resources}
+}
+
+// Resources returns every resource in the order they appear
+func (i Index) Resources() []Resource {
+	return i.resources
+}
+
+// Find returns the resources whose kind, namespace and name fully match the regexes given.
+// An empty regex matches anything, including a missing field
+func (i Index) Find(kind, namespace, name string) ([]Resource, error) {
+	var regexes []*regexp.Regexp
+	for _, pattern := range []string{kind, namespace, name} {
+		r, err := utils.CompileFullMatch(pattern)
+		if err != nil {
+			return nil, err
+		}
+		regexes = append(regexes, r)
+	}
+
+	var matched []Resource
+	for _, resource := range i.resources {
+		if matchesAll(regexes, resource.Kind, resource.Namespace, resource.Name) {
+			matched = append(matched, resource)
+		}
+	}
+	return matched, nil
+}
+
+// Get returns the resource with exactly the kind, namespace and name given
+func (i Index) Get(kind, namespace, name string) (Resource, bool) {
+	for _, resource := range i.resources {
+		if resource.Kind == kind && resource.Namespace == namespace && resource.Name == name {
+			return resource, true
+		}
+	}
+	return Resource{}, false
+}
+
+// GetContaining returns the resource that nodeIndex is part of
+func (i Index) GetContaining(nodeIndex int) (Resource, bool) {
+	for _, resource := range i.resources {
+		if resource.Contains(nodeIndex) {
+			return resource, true
+		}
+	}
+	return Resource{}, false
+}
+
+func newResource(nodeList NodeList, index int) Resource {
+	resource := Resource{
+		ResourceInfo: nodelist.ResourceInfo{
+			Path:      nodeList.GetPath(index),
+			Kind:      nodeList.GetChildValue(index, "kind"),
+			Namespace: nodeList.GetChildValue(index, "metadata", "namespace"),
+			Name:      nodeList.GetChildValue(index, "metadata", "name"),
+		},
+		APIVersion:  nodeList.GetChildValue(index, "apiVersion"),
+		Labels:      getStringMap(nodeList, index, "metadata", "labels"),
+		Annotations: getStringMap(nodeList, index, "metadata", "annotations"),
+		Start:       index,
+		End:         nodeList.GetLastChild(index),
+	}
+	if owners, ok := nodeList.GetChild(index, "metadata", "ownerReferences"); ok {
+		for _, owner := range nodeList.GetChildren(owners) {
+			resource.OwnerReferences = append(resource.OwnerReferences, OwnerReference{
+				APIVersion: nodeList.GetChildValue(owner, "apiVersion"),
+				Kind:       nodeList.GetChildValue(owner, "kind"),
+				Name:       nodeList.GetChildValue(owner, "name"),
+				UID:        nodeList.GetChildValue(owner, "uid"),
+				Controller: nodeList.GetChildValue(owner, "controller") == "true",
+			})
+		}
+	}
+	return resource
+}
+
+func matchesAll(regexes []*regexp.Regexp, values ...string) bool {
+	for index, r := range regexes {
+		if r != nil && !r.MatchString(values[index]) {
+			return false
+		}
+	}
+	return true
+}
+
+func getStringMap(nodeList NodeList, index int, keys ...string) map[string]string {
+	values := map[string]string{}
+	if parent, ok := nodeList.GetChild(index, keys...); ok {
+		for _, child := range nodeList.GetChildren(parent) {
+			values[nodeList.GetKey(child)] = nodeList.GetValue(child)
+		}
+	}
+	return values
+}
@@ -0,0 +1,102 @@
+package k8s_test
+
+import (
+	"kube-review/k8s"
+	"kube-review/nodelist"
+	"reflect"
+	"testing"
+)
+
+const testJSON = `{"apiVersion": "v1", "kind": "List", "items": [
+	{"apiVersion": "apps/v1", "kind": "Deployment", "metadata": {"name": "web", "namespace": "prod",
+		"labels": {"app": "web", "tier": "frontend"}, "annotations": {"owner": "team-a"}}},
+	{"apiVersion": "apps/v1", "kind": "ReplicaSet", "metadata": {"name": "web-1", "namespace": "prod",
+		"ownerReferences": [{"apiVersion": "apps/v1", "kind": "Deployment", "name": "web", "uid": "123", "controller": true}]}},
+	{"apiVersion": "rbac.authorization.k8s.io/v1", "kind": "ClusterRole", "metadata": {"name": "admin"}}
+]}`
+
+func getIndex(t *testing.T, jsonData string) (k8s.Index, nodelist.NodeList) {
+	nodeList, err := nodelist.NewNodeList([]byte(jsonData), true)
+	if err != nil {
+		t.Fatalf("Failed to parse test data - %s", err.Error())
+	}
+	return k8s.NewIndex(&nodeList), nodeList
+}
+
+func TestNewIndexReadsResourceMetadata(t *testing.T) {
+	index, _ := getIndex(t, testJSON)
+	actual := index.Resources()
+	if len(actual) != 3 {
+		t.Fatalf("Expected 3 resources but got %d", len(actual))
+	}
+	deployment := actual[0]
+	if deployment.APIVersion != "apps/v1" || deployment.Kind != "Deployment" || deployment.Namespace != "prod" ||
+		deployment.Name != "web" || deployment.Path != "items[0]" {
+		t.Errorf("Expected apps/v1 Deployment prod/web at items[0] but got %v", deployment)
+	}
+	if expected := map[string]string{"app": "web", "tier": "frontend"}; !reflect.DeepEqual(deployment.Labels, expected) {
+		t.Errorf("Expected labels %v but got %v", expected, deployment.Labels)
+	}
+	if deployment.Annotations["owner"] != "team-a" {
+		t.Errorf("Expected owner annotation but got %v", deployment.Annotations)
+	}
+}
+
+func TestNewIndexReadsOwnerReferences(t *testing.T) {
+	index, _ := getIndex(t, testJSON)
+	expected := []k8s.OwnerReference{{APIVersion: "apps/v1", Kind: "Deployment", Name: "web", UID: "123", Controller: true}}
+	if actual := index.Resources()[1].OwnerReferences; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected %v but got %v", expected, actual)
+	}
+}
+
+func TestResourceRangeCoversAllOfItsNodes(t *testing.T) {
+	index, nodeList := getIndex(t, testJSON)
+	resources := index.Resources()
+	for i, resource := range resources {
+		if nodeList.GetPath(resource.Start) != resource.Path {
+			t.Errorf("Expected resource to start at %s but got %s", resource.Path, nodeList.GetPath(resource.Start))
+		}
+		if i > 0 && resources[i-1].End != resource.Start-1 {
+			t.Errorf("Expected %s to end just before %s", resources[i-1].Path, resource.Path)
+		}
+	}
+	if found, ok := index.GetContaining(resources[1].End); !ok || found.Name != "web-1" {
+		t.Errorf("Expected last node of web-1 to be contained by it but got %v", found)
+	}
+}
+
+func TestFindMatchesWholeValues(t *testing.T) {
+	index, _ := getIndex(t, testJSON)
+	actual, _ := index.Find("Deployment|ReplicaSet", "prod", "")
+	if len(actual) != 2 || actual[0].Name != "web" || actual[1].Name != "web-1" {
+		t.Errorf("Expected web and web-1 but got %v", actual)
+	}
+	if actual, _ := index.Find("Deploy", "", ""); len(actual) != 0 {
+		t.Errorf("Expected partial kinds not to match but got %v", actual)
+	}
+}
+
+func TestFindReturnsErrorForInvalidRegex(t *testing.T) {
+	index, _ := getIndex(t, testJSON)
+	if _, err := index.Find("[", "", ""); err == nil {
+		t.Errorf("Expected an error but got nothing")
+	}
+}
+
+func TestGetReturnsExactResource(t *testing.T) {
+	index, _ := getIndex(t, testJSON)
+	if actual, ok := index.Get("ClusterRole", "", "admin"); !ok || actual.Path != "items[2]" {
+		t.Errorf("Expected ClusterRole admin at items[2] but got %v", actual)
+	}
+	if _, ok := index.Get("ClusterRole", "prod", "admin"); ok {
+		t.Errorf("Expected no ClusterRole in prod")
+	}
+}
+
+func TestNewIndexTreatsSingleObjectAsResource(t *testing.T) {
+	index, _ := getIndex(t, `{"kind": "Pod", "metadata": {"name": "solo"}}`)
+	if actual := index.Resources(); len(actual) != 1 || actual[0].Name != "solo" || actual[0].Start != 0 {
+		t.Errorf("Expected the document to be the only resource but got %v", actual)
+	}
+}
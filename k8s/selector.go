@@ -0,0 +1,61 @@
+package k8s
+
+// MatchesSelector returns true if labels match the label selector at selector, which has
+// matchLabels and matchExpressions as in a Deployment or NetworkPolicy. A selector without
+// either matches everything
+func MatchesSelector(nodeList NodeList, selector int, labels map[string]string) bool {
+	if matchLabels, ok := nodeList.GetChild(selector, "matchLabels"); ok {
+		for _, child := range nodeList.GetChildren(matchLabels) {
+			if value, ok := labels[nodeList.GetKey(child)]; !ok || value != nodeList.GetValue(child) {
+				return false
+			}
+		}
+	}
+	if expressions, ok := nodeList.GetChild(selector, "matchExpressions"); ok {
+		for _, expression := range nodeList.GetChildren(expressions) {
+			value, exists := labels[nodeList.GetChildValue(expression, "key")]
+			values := getValues(nodeList, expression, "values")
+			switch nodeList.GetChildValue(expression, "operator") {
+			case "In":
+				if !exists || !values[value] {
+					return false
+				}
+			case "NotIn":
+				if exists && values[value] {
+					return false
+				}
+			case "Exists":
+				if !exists {
+					return false
+				}
+			case "DoesNotExist":
+				if exists {
+					return false
+				}
+			default:
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// IsEmptySelector returns true if the label selector at selector has no requirements
+func IsEmptySelector(nodeList NodeList, selector int) bool {
+	for _, key := range []string{"matchLabels", "matchExpressions"} {
+		if child, ok := nodeList.GetChild(selector, key); ok && len(nodeList.GetChildren(child)) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func getValues(nodeList NodeList, index int, key string) map[string]bool {
+	values := map[string]bool{}
+	if parent, ok := nodeList.GetChild(index, key); ok {
+		for _, child := range nodeList.GetChildren(parent) {
+			values[nodeList.GetValue(child)] = true
+		}
+	}
+	return values
+}
@@ -0,0 +1,31 @@
+package k8s_test
+
+import (
+	"kube-review/k8s"
+	"testing"
+)
+
+func TestMatchesSelectorAppliesLabelsAndExpressions(t *testing.T) {
+	_, nodeList := getIndex(t, `{"items": [{"kind": "Deployment", "spec": {"selector": {
+		"matchLabels": {"app": "web"},
+		"matchExpressions": [{"key": "tier", "operator": "In", "values": ["frontend", "edge"]},
+			{"key": "canary", "operator": "DoesNotExist"}]}}}]}`)
+	selector, _ := nodeList.GetChild(nodeList.GetResourceIndices()[0], "spec", "selector")
+	tests := []struct {
+		labels   map[string]string
+		expected bool
+	}{
+		{map[string]string{"app": "web", "tier": "edge"}, true},
+		{map[string]string{"app": "web", "tier": "backend"}, false},
+		{map[string]string{"app": "web", "tier": "edge", "canary": "true"}, false},
+		{map[string]string{"tier": "frontend"}, false},
+	}
+	for _, test := range tests {
+		if actual := k8s.MatchesSelector(&nodeList, selector, test.labels); actual != test.expected {
+			t.Errorf("Expected %v for %v but got %v", test.expected, test.labels, actual)
+		}
+	}
+	if k8s.IsEmptySelector(&nodeList, selector) {
+		t.Errorf("Expected selector with requirements not to be empty")
+	}
+}
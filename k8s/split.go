@@ -0,0 +1,96 @@
+package k8s
+
+import (
+	"fmt"
+	"kube-review/nodelist"
+)
+
+const (
+	// PodsRoot is the split root that matches the pod spec of every workload
+	PodsRoot = "@pods"
+	// ContainersRoot is the split root that matches every container of every workload
+	ContainersRoot = "@containers"
+)
+
+// SplitViews splits nodeList by separator as nodelist.NodeList.SplitViews does, except that a
+// root of @pods or @containers splits the pod spec or containers of every workload in the
+// current view, whatever its kind. Targets are looked up with GetEffective, so
+// "@containers = metadata.namespace, securityContext.runAsNonRoot" groups containers by the
+// namespace of their workload and then by runAsNonRoot, whether set on the container or pod
+func SplitViews(nodeList *nodelist.NodeList, separator string) error {
+	root, targets, err := nodelist.ParseSeparator(separator)
+	if err != nil {
+		return err
+	}
+	if len(root) != 1 || (root[0] != PodsRoot && root[0] != ContainersRoot) {
+		return nodeList.SplitViews(separator)
+	}
+
+	var items []splitItem
+	index := NewIndex(nodeList)
+	if root[0] == PodsRoot {
+		for _, template := range index.PodTemplates(nodeList) {
+			items = append(items, splitItem{template.Spec, template})
+		}
+	} else {
+		for _, container := range index.Containers(nodeList) {
+			items = append(items, splitItem{container.Index, container})
+		}
+	}
+
+	groups := map[string][]int{}
+	grouped := false
+	for _, item := range items {
+		names := getSplitNames(nodeList, item.source, targets)
+		for _, name := range names {
+			groups[name] = append(groups[name], item.index)
+		}
+		grouped = grouped || names[0] != nodelist.UngroupedView
+	}
+	if !grouped {
+		return fmt.Errorf("There are no nodes at target")
+	}
+	for name, indices := range groups {
+		if err := nodeList.AddView(name, *nodeList, indices); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/////////////////////////////////////////////////////////////////////////////////
+// PRIVATE FUNCTIONS
+
+// effectiveSource is a pod template or container whose fields can be looked up with GetEffective
+type effectiveSource interface {
+	GetEffective(nodeList NodeList, keys ...string) (int, bool)
+}
+
+type splitItem struct {
+	index  int
+	source effectiveSource
+}
+
+// getSplitNames returns the name of the view, and each view above it, that source belongs in
+func getSplitNames(nodeList NodeList, source effectiveSource, targets []nodelist.SplitTarget) []string {
+	var names []string
+	name := ""
+	for _, target := range targets {
+		if name != "" {
+			name += "/"
+		}
+		value, ok := "", false
+		if index, found := source.GetEffective(nodeList, target.Path...); found {
+			// Objects and arrays cannot name a view
+			if value = nodeList.GetValue(index); value != "{" && value != "[" {
+				value, ok = target.Name(value)
+			}
+		}
+		if !ok {
+			value = nodelist.UngroupedView
+		}
+		name += value
+		names = append(names, name)
+	}
+	return names
+}
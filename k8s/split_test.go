@@ -0,0 +1,48 @@
+package k8s_test
+
+import (
+	"kube-review/k8s"
+	"kube-review/nodelist"
+	"reflect"
+	"testing"
+)
+
+func TestSplitViewsGroupsContainersOfEveryKind(t *testing.T) {
+	nodeList, _ := nodelist.NewNodeList([]byte(workloadJSON), true)
+	if err := k8s.SplitViews(&nodeList, "@containers = name"); err != nil {
+		t.Fatalf("Expected no error but got '%s'", err.Error())
+	}
+	var actual []string
+	for _, item := range nodeList.GetViewTree() {
+		actual = append(actual, item.Name)
+	}
+	expected := []string{"a", "b", "init", "main"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected %v but got %v", expected, actual)
+	}
+}
+
+func TestSplitViewsUsesEffectiveValues(t *testing.T) {
+	nodeList, _ := nodelist.NewNodeList([]byte(workloadJSON), true)
+	if err := k8s.SplitViews(&nodeList, "@pods = metadata.name, metadata.labels.app"); err != nil {
+		t.Fatalf("Expected no error but got '%s'", err.Error())
+	}
+	var actual []string
+	for _, item := range nodeList.GetViewTree() {
+		actual = append(actual, item.Name)
+	}
+	expected := []string{"a", "a/ungrouped", "b", "b/b", "main"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected %v but got %v", expected, actual)
+	}
+}
+
+func TestSplitViewsFallsBackToPathSplits(t *testing.T) {
+	nodeList, _ := nodelist.NewNodeList([]byte(workloadJSON), true)
+	if err := k8s.SplitViews(&nodeList, "items = kind"); err != nil {
+		t.Fatalf("Expected no error but got '%s'", err.Error())
+	}
+	if err := nodeList.SetView("Service"); err != nil {
+		t.Errorf("Expected a Service view but got '%s'", err.Error())
+	}
+}
@@ -0,0 +1,23 @@
+package k8s
+
+import "strings"
+
+// ValueNodeList is a NodeList that can also return the raw JSON of a value, which tells
+// strings apart from other values
+type ValueNodeList interface {
+	NodeList
+	GetRawValue(nodeIndex int) string
+}
+
+// GetStringNodes returns every node below nodeIndex with a string value
+func GetStringNodes(nodeList ValueNodeList, nodeIndex int) []int {
+	var indices []int
+	for _, child := range nodeList.GetChildren(nodeIndex) {
+		if raw := nodeList.GetRawValue(child); strings.HasPrefix(raw, "\"") {
+			indices = append(indices, child)
+		} else {
+			indices = append(indices, GetStringNodes(nodeList, child)...)
+		}
+	}
+	return indices
+}
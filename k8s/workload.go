@@ -0,0 +1,117 @@
+package k8s
+
+// podTemplatePaths is where each kind keeps the metadata and spec of the pods it runs
+var podTemplatePaths = map[string][]string{
+	"Pod":                   {},
+	"PodTemplate":           {"template"},
+	"Deployment":            {"spec", "template"},
+	"DaemonSet":             {"spec", "template"},
+	"StatefulSet":           {"spec", "template"},
+	"ReplicaSet":            {"spec", "template"},
+	"ReplicationController": {"spec", "template"},
+	"Job":                   {"spec", "template"},
+	"CronJob":               {"spec", "jobTemplate", "spec", "template"},
+}
+
+// PodTemplate locates the pod metadata and spec of a workload, which for a Pod are its own.
+// Metadata is -1 if the template has none
+type PodTemplate struct {
+	Resource
+	Metadata int
+	Spec     int
+}
+
+// PodTemplates returns the pod template of each resource that runs pods
+func (i Index) PodTemplates(nodeList NodeList) []PodTemplate {
+	var templates []PodTemplate
+	for _, resource := range i.resources {
+		path, ok := podTemplatePaths[resource.Kind]
+		if !ok {
+			continue
+		}
+		spec, ok := nodeList.GetChild(resource.Start, append(append([]string{}, path...), "spec")...)
+		if !ok {
+			continue
+		}
+		metadata, ok := nodeList.GetChild(resource.Start, append(append([]string{}, path...), "metadata")...)
+		if !ok {
+			metadata = -1
+		}
+		templates = append(templates, PodTemplate{resource, metadata, spec})
+	}
+	return templates
+}
+
+// PodLabels returns the labels of the pod, which for a workload are those of its template
+func (pt PodTemplate) PodLabels(nodeList NodeList) map[string]string {
+	if pt.Metadata < 0 {
+		return map[string]string{}
+	}
+	return getStringMap(nodeList, pt.Metadata, "labels")
+}
+
+// containerKeys are the arrays of a pod spec that hold containers
+var containerKeys = []string{"initContainers", "containers", "ephemeralContainers"}
+
+// GetContainers returns the index of every container, init container and ephemeral container
+// in the pod spec at spec
+func GetContainers(nodeList NodeList, spec int) []int {
+	var containers []int
+	for _, key := range containerKeys {
+		if parent, ok := nodeList.GetChild(spec, key); ok {
+			containers = append(containers, nodeList.GetChildren(parent)...)
+		}
+	}
+	return containers
+}
+
+// Container is a container, init container or ephemeral container in the pod template of a
+// workload. Type is the array it is in, e.g. initContainers
+type Container struct {
+	PodTemplate
+	Index int
+	Type  string
+	Name  string
+}
+
+// Containers returns every container of every workload, so a check written once for a container
+// covers Pods and each kind of controller
+func (i Index) Containers(nodeList NodeList) []Container {
+	var containers []Container
+	for _, template := range i.PodTemplates(nodeList) {
+		for _, key := range containerKeys {
+			if parent, ok := nodeList.GetChild(template.Spec, key); ok {
+				for _, index := range nodeList.GetChildren(parent) {
+					containers = append(containers, Container{template, index, key, nodeList.GetChildValue(index, "name")})
+				}
+			}
+		}
+	}
+	return containers
+}
+
+// GetEffective returns the node that sets the field at keys for the container. A field the
+// container does not set is looked up in its pod spec and then in its workload, so as with
+// the API server securityContext.runAsNonRoot falls back to the pod's security context and
+// e.g. metadata.namespace is the workload's
+func (c Container) GetEffective(nodeList NodeList, keys ...string) (int, bool) {
+	if index, ok := nodeList.GetChild(c.Index, keys...); ok {
+		return index, true
+	}
+	return c.PodTemplate.GetEffective(nodeList, keys...)
+}
+
+// GetEffective returns the node that sets the field at keys for the pod, looking in the pod
+// spec and then in the workload. Metadata is taken from the pod template if it sets it, so
+// metadata.labels are the pod's labels but metadata.namespace is usually the workload's
+func (pt PodTemplate) GetEffective(nodeList NodeList, keys ...string) (int, bool) {
+	if index, ok := nodeList.GetChild(pt.Spec, keys...); ok {
+		return index, true
+	}
+	if len(keys) > 1 && keys[0] == "metadata" && pt.Metadata >= 0 {
+		if index, ok := nodeList.GetChild(pt.Metadata, keys[1:]...); ok {
+			return index, true
+		}
+	}
+	return nodeList.GetChild(pt.Start, keys...)
+}
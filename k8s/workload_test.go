@@ -0,0 +1,62 @@
+package k8s_test
+
+import (
+	"kube-review/k8s"
+	"reflect"
+	"testing"
+)
+
+const workloadJSON = `{"apiVersion": "v1", "kind": "List", "items": [
+	{"kind": "Pod", "metadata": {"name": "a"}, "spec": {"containers": [{"name": "a"}]}},
+	{"kind": "CronJob", "metadata": {"name": "b"}, "spec": {"jobTemplate": {"spec": {"template": {
+		"metadata": {"labels": {"app": "b"}},
+		"spec": {"initContainers": [{"name": "init"}], "containers": [{"name": "b"}]}}}}}},
+	{"kind": "Service", "metadata": {"name": "c"}, "spec": {"ports": []}}
+]}`
+
+func TestPodTemplatesFollowEachKindsTemplatePath(t *testing.T) {
+	index, nodeList := getIndex(t, workloadJSON)
+	var actual []string
+	for _, template := range index.PodTemplates(&nodeList) {
+		actual = append(actual, nodeList.GetPath(template.Spec))
+		if template.Metadata >= 0 {
+			actual = append(actual, nodeList.GetPath(template.Metadata))
+		}
+	}
+	expected := []string{"items[0].spec", "items[0].metadata", "items[1].spec.jobTemplate.spec.template.spec",
+		"items[1].spec.jobTemplate.spec.template.metadata"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected %v but got %v", expected, actual)
+	}
+}
+
+func TestGetContainersIncludesInitContainers(t *testing.T) {
+	index, nodeList := getIndex(t, workloadJSON)
+	var actual []string
+	for _, container := range k8s.GetContainers(&nodeList, index.PodTemplates(&nodeList)[1].Spec) {
+		actual = append(actual, nodeList.GetChildValue(container, "name"))
+	}
+	expected := []string{"init", "b"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected %v but got %v", expected, actual)
+	}
+}
+
+func TestGetEffectiveFallsBackToPodSpecThenWorkload(t *testing.T) {
+	index, nodeList := getIndex(t, `{"items": [{"kind": "Deployment", "metadata": {"name": "a", "namespace": "prod"},
+		"spec": {"template": {"spec": {"securityContext": {"runAsNonRoot": true},
+			"containers": [{"name": "a"}, {"name": "b", "securityContext": {"runAsNonRoot": false}}]}}}}]}`)
+	var actual []string
+	for _, container := range index.Containers(&nodeList) {
+		for _, path := range [][]string{{"securityContext", "runAsNonRoot"}, {"metadata", "namespace"}, {"hostNetwork"}} {
+			if effective, ok := container.GetEffective(&nodeList, path...); ok {
+				actual = append(actual, nodeList.GetPath(effective))
+			}
+		}
+	}
+	expected := []string{"items[0].spec.template.spec.securityContext.runAsNonRoot", "items[0].metadata.namespace",
+		"items[0].spec.template.spec.containers[1].securityContext.runAsNonRoot", "items[0].metadata.namespace"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected %v but got %v", expected, actual)
+	}
+}
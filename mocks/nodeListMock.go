@@ -1,6 +1,7 @@
 package mocks
 
 import (
+	"fmt"
 	"kube-review/nodelist"
 	"regexp"
 )
@@ -72,3 +73,80 @@ func (n *NodeListMock) FindNextHighlight() error {
 func (n *NodeListMock) ResetView() {
 	n.Calls = append(n.Calls, "ResetView")
 }
+
+// GetPath is a mock function
+func (n *NodeListMock) GetPath(nodeIndex int) string {
+	n.Calls = append(n.Calls, "GetPath")
+	n.Args = append(n.Args, []interface{}{nodeIndex})
+	return fmt.Sprintf("[%d]", nodeIndex)
+}
+
+// GetLine is a mock function
+func (n *NodeListMock) GetLine(nodeIndex int) (int, error) {
+	n.Calls = append(n.Calls, "GetLine")
+	n.Args = append(n.Args, []interface{}{nodeIndex})
+	return 0, nil
+}
+
+// GetResourceInfo is a mock function
+func (n *NodeListMock) GetResourceInfo(nodeIndex int) nodelist.ResourceInfo {
+	n.Calls = append(n.Calls, "GetResourceInfo")
+	n.Args = append(n.Args, []interface{}{nodeIndex})
+	return nodelist.ResourceInfo{Path: fmt.Sprintf("[%d]", nodeIndex)}
+}
+
+// GetResourceIndices is a mock function
+func (n *NodeListMock) GetResourceIndices() []int {
+	n.Calls = append(n.Calls, "GetResourceIndices")
+	n.Args = append(n.Args, []interface{}{})
+	return []int{}
+}
+
+// GetChild is a mock function
+func (n *NodeListMock) GetChild(nodeIndex int, keys ...string) (int, bool) {
+	n.Calls = append(n.Calls, "GetChild")
+	n.Args = append(n.Args, []interface{}{nodeIndex, keys})
+	return 0, false
+}
+
+// GetChildren is a mock function
+func (n *NodeListMock) GetChildren(nodeIndex int) []int {
+	n.Calls = append(n.Calls, "GetChildren")
+	n.Args = append(n.Args, []interface{}{nodeIndex})
+	return []int{}
+}
+
+// GetKey is a mock function
+func (n *NodeListMock) GetKey(nodeIndex int) string {
+	n.Calls = append(n.Calls, "GetKey")
+	n.Args = append(n.Args, []interface{}{nodeIndex})
+	return ""
+}
+
+// GetValue is a mock function
+func (n *NodeListMock) GetValue(nodeIndex int) string {
+	n.Calls = append(n.Calls, "GetValue")
+	n.Args = append(n.Args, []interface{}{nodeIndex})
+	return ""
+}
+
+// GetChildValue is a mock function
+func (n *NodeListMock) GetChildValue(nodeIndex int, keys ...string) string {
+	n.Calls = append(n.Calls, "GetChildValue")
+	n.Args = append(n.Args, []interface{}{nodeIndex, keys})
+	return ""
+}
+
+// GetLastChild is a mock function
+func (n *NodeListMock) GetLastChild(nodeIndex int) int {
+	n.Calls = append(n.Calls, "GetLastChild")
+	n.Args = append(n.Args, []interface{}{nodeIndex})
+	return nodeIndex
+}
+
+// GetRawValue is a mock function
+func (n *NodeListMock) GetRawValue(nodeIndex int) string {
+	n.Calls = append(n.Calls, "GetRawValue")
+	n.Args = append(n.Args, []interface{}{nodeIndex})
+	return ""
+}
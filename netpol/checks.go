@@ -0,0 +1,176 @@
+package netpol
+
+import (
+	"fmt"
+	"kube-review/nodelist"
+	"kube-review/utils"
+	"net"
+	"strings"
+)
+
+// Check is a gap in NetworkPolicy coverage or a rule that allows more than it should
+type Check struct {
+	Name        string
+	Description string
+	detect      func(Analysis) []Finding
+}
+
+// Finding is a node flagged by a check. Resource is the namespace, workload or policy it is in
+type Finding struct {
+	Check     string
+	Namespace string
+	Resource  nodelist.ResourceInfo
+	Index     int
+	Message   string
+}
+
+var checks = []Check{
+	{"no-policy", "namespaces without any NetworkPolicy", func(a Analysis) []Finding {
+		var findings []Finding
+		for _, namespace := range a.namespaces {
+			if len(namespace.Policies) == 0 {
+				findings = append(findings, namespace.finding("no-policy", "No NetworkPolicy in namespace"))
+			}
+		}
+		return findings
+	}},
+	{"ingress-default-allow", "namespaces without a default-deny ingress policy", func(a Analysis) []Finding {
+		var findings []Finding
+		for _, namespace := range a.namespaces {
+			if !namespace.DefaultDenyIngress {
+				findings = append(findings, namespace.finding("ingress-default-allow", "No policy denies ingress to every pod by default"))
+			}
+		}
+		return findings
+	}},
+	{"egress-default-allow", "namespaces without a default-deny egress policy", func(a Analysis) []Finding {
+		var findings []Finding
+		for _, namespace := range a.namespaces {
+			if !namespace.DefaultDenyEgress {
+				findings = append(findings, namespace.finding("egress-default-allow", "No policy denies egress from every pod by default"))
+			}
+		}
+		return findings
+	}},
+	{"unisolated-pods", "pods that no policy isolates for ingress or egress", func(a Analysis) []Finding {
+		var findings []Finding
+		for _, pod := range a.pods {
+			var directions []string
+			for _, direction := range []DirectionEnum{INGRESS, EGRESS} {
+				if len(a.Selecting(pod, direction)) == 0 {
+					directions = append(directions, strings.ToLower(direction.String()))
+				}
+			}
+			if len(directions) > 0 {
+				findings = append(findings, Finding{"unisolated-pods", pod.Namespace, pod.ResourceInfo, pod.Spec,
+					"No policy selects the pod for " + strings.Join(directions, " or ")})
+			}
+		}
+		return findings
+	}},
+	{"allow-all", "rules allowing every source or destination, every pod in the cluster or every address", func(a Analysis) []Finding {
+		var findings []Finding
+		for _, policy := range a.policies {
+			for _, rule := range policy.Rules {
+				for _, f := range rule.permissive() {
+					findings = append(findings, Finding{"allow-all", policy.Namespace, policy.ResourceInfo, f.index, f.message})
+				}
+			}
+		}
+		return findings
+	}},
+	{"metadata-egress", "pods whose egress to the cloud metadata endpoint is not blocked", func(a Analysis) []Finding {
+		var findings []Finding
+		for _, access := range a.EgressTo(net.ParseIP(MetadataAddress), "TCP", 80) {
+			if !access.Allowed {
+				continue
+			}
+			message := "No policy isolates the pod's egress"
+			if access.Index != access.Spec {
+				message = "Egress rule allows " + MetadataAddress
+			}
+			findings = append(findings, Finding{"metadata-egress", access.Namespace, access.ResourceInfo, access.Index, message})
+		}
+		return findings
+	}},
+}
+
+// Checks returns every built in check
+func Checks() []Check {
+	return checks
+}
+
+// GetCheck returns the check called name
+func GetCheck(name string) (Check, error) {
+	var names []string
+	for _, check := range checks {
+		names = append(names, check.Name)
+	}
+	index, err := utils.FindName("NetworkPolicy check", name, names)
+	if err != nil {
+		return Check{}, err
+	}
+	return checks[index], nil
+}
+
+// Detect returns the findings of check in a namespace fully matching the namespace regex. An
+// empty regex matches any namespace
+func (a Analysis) Detect(name, namespace string) ([]Finding, error) {
+	check, err := GetCheck(name)
+	if err != nil {
+		return nil, err
+	}
+	namespaceRegex, err := utils.CompileFullMatch(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	var findings []Finding
+	for _, finding := range check.detect(a) {
+		if namespaceRegex == nil || namespaceRegex.MatchString(finding.Namespace) {
+			findings = append(findings, finding)
+		}
+	}
+	return findings, nil
+}
+
+/////////////////////////////////////////////////////////////////////////////////
+// PRIVATE FUNCTIONS
+
+type finding struct {
+	index   int
+	message string
+}
+
+func (n Namespace) finding(check, message string) Finding {
+	return Finding{check, n.Name, nodelist.ResourceInfo{Kind: "Namespace", Name: n.Name}, n.Index, message}
+}
+
+// permissive returns the parts of the rule that allow all traffic, every pod in every namespace
+// or every address
+func (r Rule) permissive() []finding {
+	ports := "on the ports given"
+	if len(r.Ports) == 0 {
+		ports = "on every port"
+	}
+	peers := "sources"
+	if r.Direction == EGRESS {
+		peers = "destinations"
+	}
+	if r.AllPeers {
+		return []finding{{r.Index, fmt.Sprintf("%s rule allows all %s %s", r.Direction, peers, ports)}}
+	}
+	var findings []finding
+	for _, block := range r.IPBlocks {
+		if block.CIDR == nil {
+			continue
+		}
+		if ones, _ := block.CIDR.Mask.Size(); ones == 0 {
+			findings = append(findings, finding{block.Index, fmt.Sprintf("%s rule allows every address %s", r.Direction, ports)})
+		}
+	}
+	for _, peer := range r.allPods {
+		findings = append(findings, finding{peer, fmt.Sprintf("%s rule allows every pod in every namespace %s", r.Direction, ports)})
+	}
+	return findings
+}
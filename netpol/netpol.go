@@ -0,0 +1,394 @@
+package netpol
+
+import (
+	"kube-review/k8s"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MetadataAddress is the cloud metadata endpoint of AWS, GCP, Azure and most other providers,
+// which can hand out the credentials of the node a pod runs on
+const MetadataAddress = "169.254.169.254"
+
+// DirectionEnum is the direction of traffic a NetworkPolicy rule applies to
+type DirectionEnum int
+
+const (
+	// INGRESS a
+	INGRESS DirectionEnum = iota
+	// EGRESS a
+	EGRESS
+)
+
+func (de DirectionEnum) String() string {
+	return [...]string{"Ingress", "Egress"}[de]
+}
+
+// Pod is a Pod, or the pod template of a workload, that NetworkPolicies can select. Labels
+// are those of the pod, not the workload
+type Pod struct {
+	k8s.PodTemplate
+	Labels map[string]string
+}
+
+// Namespace is a namespace with pods or policies in the config. Index is the Namespace object
+// or, if the config does not have it, the first resource in the namespace. A namespace is
+// default-deny for a direction if a policy selecting every pod in it isolates that direction
+// without a rule allowing all traffic, every pod in the cluster or every address
+type Namespace struct {
+	Name               string
+	Index              int
+	Labels             map[string]string
+	Policies           []Policy
+	DefaultDenyIngress bool
+	DefaultDenyEgress  bool
+}
+
+// Policy is a NetworkPolicy with the pods its podSelector selects. Ingress and Egress are
+// set for each direction it isolates the selected pods in
+type Policy struct {
+	k8s.Resource
+	Selector int
+	Ingress  bool
+	Egress   bool
+	Pods     []Pod
+	Rules    []Rule
+}
+
+// Rule is an ingress or egress rule of a NetworkPolicy. Pods are the pods in the config that
+// its peers select and IPBlocks its address ranges. A rule without peers allows all traffic,
+// and one without ports allows every port
+type Rule struct {
+	Index     int
+	Direction DirectionEnum
+	AllPeers  bool
+	Peers     []int
+	Pods      []Pod
+	IPBlocks  []IPBlock
+	Ports     []Port
+	allPods   []int
+}
+
+// IPBlock is an ipBlock peer, allowing CIDR apart from the ranges in Except
+type IPBlock struct {
+	Index  int
+	CIDR   *net.IPNet
+	Except []*net.IPNet
+}
+
+// Contains returns true if the block allows ip
+func (b IPBlock) Contains(ip net.IP) bool {
+	if b.CIDR == nil || !b.CIDR.Contains(ip) {
+		return false
+	}
+	for _, except := range b.Except {
+		if except.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// Port is a port of a rule. Port is a number, a named port or empty for every port, and
+// EndPort is the end of a range if set
+type Port struct {
+	Protocol string
+	Port     string
+	EndPort  int
+}
+
+// Allows returns true if the port allows protocol to port number. Named ports are never
+// matched as they are resolved against the destination pod's containers
+func (p Port) Allows(protocol string, port int) bool {
+	if !strings.EqualFold(p.Protocol, protocol) {
+		return false
+	}
+	if p.Port == "" {
+		return true
+	}
+	number, err := strconv.Atoi(p.Port)
+	if err != nil {
+		return false
+	}
+	return port == number || (p.EndPort >= number && port >= number && port <= p.EndPort)
+}
+
+// Access is whether a pod can send traffic to an address. Index is the egress rule allowing
+// it or, if no policy isolates the pod's egress, its pod spec
+type Access struct {
+	Pod
+	Allowed bool
+	Index   int
+}
+
+// Analysis holds the NetworkPolicies in a config resolved against its pods and namespaces
+type Analysis struct {
+	namespaces []Namespace
+	pods       []Pod
+	policies   []Policy
+}
+
+// New resolves the pod and namespace selectors of every NetworkPolicy in the items array of
+// nodeList's current view. Namespaces have their labels, including the
+// kubernetes.io/metadata.name label the API server sets
+func New(nodeList k8s.NodeList) Analysis {
+	index := k8s.NewIndex(nodeList)
+	namespaces := map[string]*Namespace{}
+	addNamespace := func(name string, start int) *Namespace {
+		if _, ok := namespaces[name]; !ok {
+			namespaces[name] = &Namespace{Name: name, Index: start,
+				Labels: map[string]string{"kubernetes.io/metadata.name": name}}
+		}
+		return namespaces[name]
+	}
+	for _, resource := range index.Resources() {
+		if resource.Kind == "Namespace" {
+			namespace := addNamespace(resource.Name, resource.Start)
+			namespace.Index = resource.Start
+			for key, value := range resource.Labels {
+				namespace.Labels[key] = value
+			}
+		}
+	}
+
+	var a Analysis
+	for _, template := range index.PodTemplates(nodeList) {
+		template.Namespace = getNamespace(template.Resource)
+		a.pods = append(a.pods, Pod{template, template.PodLabels(nodeList)})
+		addNamespace(template.Namespace, template.Start)
+	}
+	for _, resource := range index.Resources() {
+		if resource.Kind == "NetworkPolicy" {
+			resource.Namespace = getNamespace(resource)
+			addNamespace(resource.Namespace, resource.Start)
+		}
+	}
+	for _, resource := range index.Resources() {
+		if resource.Kind == "NetworkPolicy" {
+			resource.Namespace = getNamespace(resource)
+			policy := a.newPolicy(nodeList, resource, namespaces)
+			a.policies = append(a.policies, policy)
+			namespace := namespaces[resource.Namespace]
+			namespace.Policies = append(namespace.Policies, policy)
+			if policy.Selector >= 0 && k8s.IsEmptySelector(nodeList, policy.Selector) {
+				namespace.DefaultDenyIngress = namespace.DefaultDenyIngress || (policy.Ingress && !policy.allowsAll(INGRESS))
+				namespace.DefaultDenyEgress = namespace.DefaultDenyEgress || (policy.Egress && !policy.allowsAll(EGRESS))
+			}
+		}
+	}
+	for _, namespace := range namespaces {
+		a.namespaces = append(a.namespaces, *namespace)
+	}
+	sort.Slice(a.namespaces, func(i, j int) bool { return a.namespaces[i].Name < a.namespaces[j].Name })
+	return a
+}
+
+// Namespaces returns every namespace with pods or policies in the config, sorted by name
+func (a Analysis) Namespaces() []Namespace {
+	return a.namespaces
+}
+
+// Pods returns every pod and pod template in the order they appear
+func (a Analysis) Pods() []Pod {
+	return a.pods
+}
+
+// Policies returns every NetworkPolicy in the order they appear
+func (a Analysis) Policies() []Policy {
+	return a.policies
+}
+
+// Selecting returns the policies that select pod and isolate it in direction. A pod no policy
+// selects for a direction allows all traffic in that direction
+func (a Analysis) Selecting(pod Pod, direction DirectionEnum) []Policy {
+	var selecting []Policy
+	for _, policy := range a.policies {
+		if (direction == INGRESS && !policy.Ingress) || (direction == EGRESS && !policy.Egress) {
+			continue
+		}
+		for _, selected := range policy.Pods {
+			if selected.Start == pod.Start {
+				selecting = append(selecting, policy)
+				break
+			}
+		}
+	}
+	return selecting
+}
+
+// EgressTo returns whether each pod can send traffic to ip on protocol and port
+func (a Analysis) EgressTo(ip net.IP, protocol string, port int) []Access {
+	var accesses []Access
+	for _, pod := range a.pods {
+		policies := a.Selecting(pod, EGRESS)
+		if len(policies) == 0 {
+			accesses = append(accesses, Access{pod, true, pod.Spec})
+			continue
+		}
+		access := Access{pod, false, -1}
+		for _, policy := range policies {
+			for _, rule := range policy.Rules {
+				if rule.Direction == EGRESS && !access.Allowed && rule.allows(ip, protocol, port) {
+					access = Access{pod, true, rule.Index}
+				}
+			}
+		}
+		accesses = append(accesses, access)
+	}
+	return accesses
+}
+
+/////////////////////////////////////////////////////////////////////////////////
+// PRIVATE FUNCTIONS
+
+// getNamespace returns the namespace of resource, which is default if it is not set
+func getNamespace(resource k8s.Resource) string {
+	if resource.Namespace == "" {
+		return "default"
+	}
+	return resource.Namespace
+}
+
+// newPolicy reads the NetworkPolicy resource. As the API server does, a policy without
+// policyTypes isolates ingress, and egress if it has egress rules
+func (a Analysis) newPolicy(nodeList k8s.NodeList, resource k8s.Resource, namespaces map[string]*Namespace) Policy {
+	policy := Policy{Resource: resource, Selector: -1}
+	spec, ok := nodeList.GetChild(resource.Start, "spec")
+	if !ok {
+		return policy
+	}
+	if selector, ok := nodeList.GetChild(spec, "podSelector"); ok {
+		policy.Selector = selector
+		for _, pod := range a.pods {
+			if pod.Namespace == resource.Namespace && k8s.MatchesSelector(nodeList, selector, pod.Labels) {
+				policy.Pods = append(policy.Pods, pod)
+			}
+		}
+	}
+	if types, ok := nodeList.GetChild(spec, "policyTypes"); ok {
+		for _, child := range nodeList.GetChildren(types) {
+			policy.Ingress = policy.Ingress || nodeList.GetValue(child) == INGRESS.String()
+			policy.Egress = policy.Egress || nodeList.GetValue(child) == EGRESS.String()
+		}
+	} else {
+		_, policy.Egress = nodeList.GetChild(spec, "egress")
+		policy.Ingress = true
+	}
+
+	for _, direction := range []DirectionEnum{INGRESS, EGRESS} {
+		key, peersKey := "ingress", "from"
+		if direction == EGRESS {
+			key, peersKey = "egress", "to"
+		}
+		if rules, ok := nodeList.GetChild(spec, key); ok {
+			for _, index := range nodeList.GetChildren(rules) {
+				policy.Rules = append(policy.Rules, a.newRule(nodeList, index, direction, peersKey, resource.Namespace, namespaces))
+			}
+		}
+	}
+	return policy
+}
+
+// allowsAll returns true if a rule of the policy in direction allows all traffic, every pod in
+// the cluster or every address, so the policy does not deny that direction by default
+func (p Policy) allowsAll(direction DirectionEnum) bool {
+	for _, rule := range p.Rules {
+		if rule.Direction == direction && len(rule.permissive()) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (a Analysis) newRule(nodeList k8s.NodeList, index int, direction DirectionEnum, peersKey, namespace string,
+	namespaces map[string]*Namespace) Rule {
+	rule := Rule{Index: index, Direction: direction}
+	if peers, ok := nodeList.GetChild(index, peersKey); ok {
+		rule.Peers = nodeList.GetChildren(peers)
+	}
+	rule.AllPeers = len(rule.Peers) == 0
+	for _, peer := range rule.Peers {
+		if block, ok := nodeList.GetChild(peer, "ipBlock"); ok {
+			rule.IPBlocks = append(rule.IPBlocks, newIPBlock(nodeList, block))
+			continue
+		}
+		if selectsAllPods(nodeList, peer) {
+			rule.allPods = append(rule.allPods, peer)
+		}
+		for _, pod := range a.pods {
+			if matchesPeer(nodeList, peer, namespace, pod, namespaces) {
+				rule.Pods = append(rule.Pods, pod)
+			}
+		}
+	}
+	if ports, ok := nodeList.GetChild(index, "ports"); ok {
+		for _, port := range nodeList.GetChildren(ports) {
+			protocol := nodeList.GetChildValue(port, "protocol")
+			if protocol == "" {
+				protocol = "TCP"
+			}
+			endPort, _ := strconv.Atoi(nodeList.GetChildValue(port, "endPort"))
+			rule.Ports = append(rule.Ports, Port{protocol, nodeList.GetChildValue(port, "port"), endPort})
+		}
+	}
+	return rule
+}
+
+// matchesPeer returns true if pod is selected by the podSelector and namespaceSelector of peer.
+// Without a namespaceSelector only pods in the policy's namespace are selected
+func matchesPeer(nodeList k8s.NodeList, peer int, namespace string, pod Pod, namespaces map[string]*Namespace) bool {
+	if selector, ok := nodeList.GetChild(peer, "namespaceSelector"); ok {
+		if !k8s.MatchesSelector(nodeList, selector, namespaces[pod.Namespace].Labels) {
+			return false
+		}
+	} else if pod.Namespace != namespace {
+		return false
+	}
+	if selector, ok := nodeList.GetChild(peer, "podSelector"); ok {
+		return k8s.MatchesSelector(nodeList, selector, pod.Labels)
+	}
+	return true
+}
+
+// selectsAllPods returns true if peer has an empty namespaceSelector and so, without a
+// podSelector with requirements, selects every pod in the cluster
+func selectsAllPods(nodeList k8s.NodeList, peer int) bool {
+	selector, ok := nodeList.GetChild(peer, "namespaceSelector")
+	if !ok || !k8s.IsEmptySelector(nodeList, selector) {
+		return false
+	}
+	selector, ok = nodeList.GetChild(peer, "podSelector")
+	return !ok || k8s.IsEmptySelector(nodeList, selector)
+}
+
+func newIPBlock(nodeList k8s.NodeList, index int) IPBlock {
+	block := IPBlock{Index: index}
+	_, block.CIDR, _ = net.ParseCIDR(nodeList.GetChildValue(index, "cidr"))
+	if except, ok := nodeList.GetChild(index, "except"); ok {
+		for _, child := range nodeList.GetChildren(except) {
+			if _, cidr, err := net.ParseCIDR(nodeList.GetValue(child)); err == nil {
+				block.Except = append(block.Except, cidr)
+			}
+		}
+	}
+	return block
+}
+
+// allows returns true if the rule allows traffic to or from ip on protocol and port
+func (r Rule) allows(ip net.IP, protocol string, port int) bool {
+	allowed := r.AllPeers
+	for _, block := range r.IPBlocks {
+		allowed = allowed || block.Contains(ip)
+	}
+	if !allowed || len(r.Ports) == 0 {
+		return allowed
+	}
+	for _, p := range r.Ports {
+		if p.Allows(protocol, port) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package netpol_test
+
+import (
+	"io/ioutil"
+	"kube-review/netpol"
+	"kube-review/nodelist"
+	"net"
+	"reflect"
+	"testing"
+)
+
+func getAnalysis(t *testing.T) (netpol.Analysis, nodelist.NodeList) {
+	rawJSON, err := ioutil.ReadFile("../testdata/querytests/fixtures/netpol.json")
+	if err != nil {
+		t.Fatalf("Failed to read fixture - %s", err.Error())
+	}
+	nodeList, err := nodelist.NewNodeList(rawJSON, true)
+	if err != nil {
+		t.Fatalf("Failed to parse test data - %s", err.Error())
+	}
+	return netpol.New(&nodeList), nodeList
+}
+
+func TestNamespacesAreDefaultDenyWithEmptyPodSelector(t *testing.T) {
+	analysis, _ := getAnalysis(t)
+	var actual []string
+	for _, namespace := range analysis.Namespaces() {
+		actual = append(actual, namespace.Name)
+		if namespace.DefaultDenyIngress {
+			actual = append(actual, "ingress")
+		}
+		if namespace.DefaultDenyEgress {
+			actual = append(actual, "egress")
+		}
+	}
+	expected := []string{"dev", "monitoring", "prod", "ingress", "egress"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected %v but got %v", expected, actual)
+	}
+}
+
+func TestPeersResolveNamespaceAndPodSelectors(t *testing.T) {
+	analysis, _ := getAnalysis(t)
+	var actual []string
+	for _, policy := range analysis.Policies() {
+		if policy.Name != "scrape" {
+			continue
+		}
+		for _, pod := range policy.Rules[0].Pods {
+			actual = append(actual, pod.ResourceInfo.String())
+		}
+	}
+	expected := []string{"Deployment prod/web"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected %v but got %v", expected, actual)
+	}
+}
+
+func TestEgressToHonoursExceptAndPorts(t *testing.T) {
+	analysis, nodeList := getAnalysis(t)
+	var actual []string
+	for _, access := range analysis.EgressTo(net.ParseIP(netpol.MetadataAddress), "TCP", 80) {
+		if access.Allowed {
+			actual = append(actual, access.Name+" "+nodeList.GetPath(access.Index))
+		}
+	}
+	expected := []string{"db items[10].spec.egress[0]", "debug items[5].spec"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected %v but got %v", expected, actual)
+	}
+	for _, access := range analysis.EgressTo(net.ParseIP(netpol.MetadataAddress), "TCP", 443) {
+		if access.Allowed && access.Name == "db" {
+			t.Errorf("Expected db to be blocked on port 443")
+		}
+	}
+}
+
+func TestDetectRejectsUnknownChecks(t *testing.T) {
+	analysis, _ := getAnalysis(t)
+	if _, err := analysis.Detect("unknown", ""); err == nil {
+		t.Errorf("Expected error but got nil")
+	}
+}
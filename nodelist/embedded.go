@@ -0,0 +1,224 @@
+package nodelist
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"kube-review/utils"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EmbedEnum is the format of a document held in a string value
+type EmbedEnum int8
+
+const (
+	// NOTEMBEDDED a
+	NOTEMBEDDED EmbedEnum = iota
+	// JSON a
+	JSON
+	// YAML a
+	YAML
+	// BASE64 a
+	BASE64
+	// PEM a
+	PEM
+)
+
+func (ee EmbedEnum) String() string {
+	return [...]string{"None", "JSON", "YAML", "Base64", "PEM"}[ee]
+}
+
+// Key returns the key of the node an embedded document of this format is placed under, e.g. (json)
+func (ee EmbedEnum) Key() string {
+	return "(" + strings.ToLower(ee.String()) + ")"
+}
+
+// maxEmbedDepth limits how many documents can be nested, e.g. JSON in base64 in a Secret
+const maxEmbedDepth = 4
+
+// ExpandEmbedded returns a view with the documents held in string values added below them, along
+// with the number of values expanded. Objects and arrays in JSON and YAML, the data of Secrets
+// and other base64 holding a document, and a summary of each PEM block are placed under a node
+// keyed with their format, e.g. (json), and every node of the document is marked with it.
+// Documents are expanded in turn, e.g. the data of a Secret in its last-applied-configuration
+func (v View) ExpandEmbedded() (View, int, error) {
+	expanded := 0
+	for depth := 0; depth < maxEmbedDepth; depth++ {
+		nodes := make([]*Node, 0, len(v.nodes))
+		added := 0
+		for index, nodeView := range v.nodes {
+			nodes = append(nodes, nodeView.node)
+			if len(nodeView.children) > 0 || !isString(nodeView.node.value) || (depth > 0 && nodeView.node.embedded == NOTEMBEDDED) {
+				continue
+			}
+			if embedded := embedNodes(v.GetValue(index), nodeView.node.level+1, v.isSecretData(index)); len(embedded) > 0 {
+				nodes = append(nodes, embedded...)
+				added++
+			}
+		}
+		if added == 0 {
+			break
+		} else if depth == 0 {
+			expanded = added
+		}
+		var err error
+		if v, err = NewView(nodes); err != nil {
+			return View{}, 0, err
+		}
+	}
+	return v, expanded, nil
+}
+
+// GetEmbedded returns the format of the document nodeIndex was decoded from, or NOTEMBEDDED
+func (v View) GetEmbedded(nodeIndex int) EmbedEnum {
+	return v.nodes[nodeIndex].node.embedded
+}
+
+/////////////////////////////////////////////////////////////////////////////////
+// PRIVATE FUNCTIONS
+
+// indexOf returns the index of node in the view or 0 if it is not in it
+func (v View) indexOf(node *Node) int {
+	for index, nodeView := range v.nodes {
+		if nodeView.node == node {
+			return index
+		}
+	}
+	return 0
+}
+
+// isSecretData returns true if nodeIndex is a value in the data of a Secret, including one
+// decoded from a last-applied-configuration
+func (v View) isSecretData(nodeIndex int) bool {
+	parent := v.nodes[nodeIndex].parent
+	if parent <= 0 || v.nodes[parent].node.key != "data" {
+		return false
+	}
+	return v.GetChildValue(v.nodes[parent].parent, "kind") == "Secret"
+}
+
+// embedNodes returns the nodes of the document in value starting at level. Base64 is only
+// decoded if isData is set or it holds a document
+func embedNodes(value string, level int, isData bool) []*Node {
+	format, data, ok := decodeDocument(value, isData)
+	if !ok {
+		return nil
+	}
+	parsed := []Node{NewNode(format.Key(), "", level)}
+	parser := NewParser(&parsed, nil)
+	if err := parser.createNode(data, level); err != nil {
+		return nil
+	}
+	nodes := make([]*Node, 0, len(parsed))
+	for index := range parsed {
+		parsed[index].embedded = format
+		nodes = append(nodes, &parsed[index])
+	}
+	return nodes
+}
+
+// decodeDocument returns the format of the document in value and its contents, ready to be
+// parsed into nodes, or false if value does not hold one
+func decodeDocument(value string, isData bool) (EmbedEnum, interface{}, bool) {
+	trimmed := strings.TrimSpace(value)
+	if strings.Contains(trimmed, "-----BEGIN ") {
+		if blocks := summarisePEM(trimmed); len(blocks) > 0 {
+			return PEM, blocks, true
+		}
+	}
+	if strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+		var data interface{}
+		if err := json.Unmarshal([]byte(trimmed), &data); err == nil && isCollection(data) {
+			return JSON, data, true
+		}
+	}
+	if strings.Contains(trimmed, "\n") {
+		if data, ok := decodeYAML(trimmed); ok {
+			return YAML, data, true
+		}
+	}
+	if text, ok := utils.DecodeBase64Text(trimmed, 4); ok {
+		if _, _, ok := decodeDocument(text, false); ok || isData {
+			return BASE64, text, true
+		}
+	}
+	return NOTEMBEDDED, nil, false
+}
+
+// decodeYAML returns the objects and arrays in YAML, with several documents returned as an
+// array, converted to the types that JSON decodes to
+func decodeYAML(text string) (interface{}, bool) {
+	var documents []interface{}
+	decoder := yaml.NewDecoder(strings.NewReader(text))
+	for {
+		var document interface{}
+		if err := decoder.Decode(&document); err == io.EOF {
+			break
+		} else if err != nil || !isCollection(document) {
+			return nil, false
+		}
+		documents = append(documents, document)
+	}
+	if len(documents) == 0 {
+		return nil, false
+	}
+	var data interface{} = documents
+	if len(documents) == 1 {
+		data = documents[0]
+	}
+	// Maps with keys that are not strings cannot be converted and are left alone
+	rawJSON, err := json.Marshal(data)
+	if err != nil {
+		return nil, false
+	}
+	var converted interface{}
+	err = json.Unmarshal(rawJSON, &converted)
+	return converted, err == nil
+}
+
+// summarisePEM returns the type of each PEM block and, for certificates, their subject, issuer,
+// validity and DNS names. Keys themselves are never included
+func summarisePEM(text string) []interface{} {
+	var blocks []interface{}
+	rest := []byte(text)
+	for {
+		block, remaining := pem.Decode(rest)
+		if block == nil {
+			return blocks
+		}
+		rest = remaining
+		summary := map[string]interface{}{"type": block.Type}
+		if block.Type == "CERTIFICATE" {
+			if cert, err := x509.ParseCertificate(block.Bytes); err == nil {
+				summary["subject"] = cert.Subject.String()
+				summary["issuer"] = cert.Issuer.String()
+				summary["notBefore"] = cert.NotBefore.UTC().Format(time.RFC3339)
+				summary["notAfter"] = cert.NotAfter.UTC().Format(time.RFC3339)
+				dnsNames := []interface{}{}
+				for _, name := range cert.DNSNames {
+					dnsNames = append(dnsNames, name)
+				}
+				summary["dnsNames"] = dnsNames
+			}
+		}
+		blocks = append(blocks, summary)
+	}
+}
+
+func isString(value string) bool {
+	return strings.HasPrefix(value, "\"")
+}
+
+func isCollection(data interface{}) bool {
+	switch collection := data.(type) {
+	case map[string]interface{}:
+		return len(collection) > 0
+	case []interface{}:
+		return len(collection) > 0
+	}
+	return false
+}
@@ -0,0 +1,119 @@
+package nodelist_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"kube-review/nodelist"
+	"math/big"
+	"regexp"
+	"testing"
+	"time"
+)
+
+// embeddedJSON is a Secret applied with kubectl and a ConfigMap holding YAML, JSON and plain strings
+const embeddedJSON = `{"apiVersion": "v1", "kind": "List", "items": [
+	{"kind": "Secret", "metadata": {"name": "db", "annotations": {"kubectl.kubernetes.io/last-applied-configuration": "{\"kind\":\"Secret\",\"data\":{\"password\":\"aHVudGVyMg==\"}}"}}, "data": {"password": "aHVudGVyMg=="}},
+	{"kind": "ConfigMap", "metadata": {"name": "app"}, "data": {"app.yaml": "replicas: 2\nhosts:\n  - a.example.com\n", "empty.json": "{}", "name": "aHVudGVyMg==", "note": "not: yaml"}}
+]}`
+
+func getExpanded(t *testing.T, jsonData string) (nodelist.NodeList, int) {
+	nl, err := nodelist.NewNodeList([]byte(jsonData), true)
+	if err != nil {
+		t.Fatalf("Failed to parse test data - %s", err.Error())
+	}
+	count, err := nl.ExpandEmbedded()
+	if err != nil {
+		t.Fatalf("Failed to expand embedded documents - %s", err.Error())
+	}
+	return nl, count
+}
+
+// getPaths returns the path, format and value of each node fully matching the key regex
+func getPaths(nl nodelist.NodeList, key string) map[string]string {
+	paths := map[string]string{}
+	for _, index := range nl.GetNodesMatching(regexp.MustCompile("^"+key+"$"), nodelist.KEY, true) {
+		paths[nl.GetPath(index)] = nl.GetEmbedded(index).String() + " " + nl.GetValue(index)
+	}
+	return paths
+}
+
+func TestExpandEmbeddedDecodesSecretDataInLastApplied(t *testing.T) {
+	nl, count := getExpanded(t, embeddedJSON)
+	if count != 3 {
+		t.Errorf("Expected 3 values to be expanded but got %d", count)
+	}
+	paths := getPaths(nl, `\(base64\)`)
+	expected := map[string]string{
+		"items[0].data.password.(base64)": "Base64 hunter2",
+		"items[0].metadata.annotations.kubectl.kubernetes.io/last-applied-configuration.(json).data.password.(base64)": "Base64 hunter2",
+	}
+	if len(paths) != len(expected) {
+		t.Errorf("Expected %v but got %v", expected, paths)
+	}
+	for path, value := range expected {
+		if paths[path] != value {
+			t.Errorf("Expected '%s' at %s but got '%s'", value, path, paths[path])
+		}
+	}
+}
+
+func TestExpandEmbeddedDecodesYAML(t *testing.T) {
+	nl, _ := getExpanded(t, embeddedJSON)
+	expected := "YAML a.example.com"
+	if actual := getPaths(nl, `\[\]0`)["items[1].data.app.yaml.(yaml).hosts[0]"]; actual != expected {
+		t.Errorf("Expected '%s' but got '%s'", expected, actual)
+	}
+	if paths := getPaths(nl, `\(yaml\)|\(json\)`); len(paths) != 2 {
+		t.Errorf("Expected only app.yaml and last-applied-configuration to be expanded but got %v", paths)
+	}
+}
+
+func TestExpandEmbeddedKeepsJSONAndSelection(t *testing.T) {
+	nl, _ := nodelist.NewNodeList([]byte(embeddedJSON), true)
+	expected := nl.GetNodeJSON(0)
+	nl.SetActiveNode(nl.GetNodesMatching(regexp.MustCompile("^note$"), nodelist.KEY, true)[0])
+	nl.ExpandEmbedded()
+	if actual := nl.GetNodeJSON(0); actual != expected {
+		t.Errorf("Expected '%s' but got '%s'", expected, actual)
+	}
+	if actual := nl.GetPath(nl.GetActiveNode()); actual != "items[1].data.note" {
+		t.Errorf("Expected items[1].data.note to stay selected but got %s", actual)
+	}
+	nl.ResetView()
+	if paths := getPaths(nl, `\(json\)`); len(paths) != 1 {
+		t.Errorf("Expected the view to stay expanded when reset but got %v", paths)
+	}
+}
+
+func TestExpandEmbeddedSummarisesCertificates(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "web"},
+		DNSNames:     []string{"web.example.com"},
+		NotBefore:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:     time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate - %s", err.Error())
+	}
+	cert, _ := json.Marshal(string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})))
+	nl, _ := getExpanded(t, `{"kind": "ConfigMap", "data": {"ca.crt": `+string(cert)+`}}`)
+
+	expected := map[string]string{
+		"data.ca.crt.(pem)[0].subject":     "PEM CN=web",
+		"data.ca.crt.(pem)[0].notAfter":    "PEM 2025-01-01T00:00:00Z",
+		"data.ca.crt.(pem)[0].dnsNames[0]": "PEM web.example.com",
+	}
+	for path, value := range expected {
+		if actual := getPaths(nl, `subject|notAfter|\[\]0`)[path]; actual != value {
+			t.Errorf("Expected '%s' at %s but got '%s'", value, path, actual)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package nodelist
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// lineIndex maps paths to the line they appear on in jsonData. As this takes a second pass
+// over the whole document, the map is only built the first time a line is needed
+type lineIndex struct {
+	once     sync.Once
+	jsonData []byte
+	lines    map[string]int
+	err      error
+}
+
+func (l *lineIndex) get(path string) (int, error) {
+	l.once.Do(func() {
+		l.lines, l.err = getLines(l.jsonData)
+		l.jsonData = nil
+	})
+	if l.err != nil {
+		return 0, fmt.Errorf("Could not find the line of '%s' - %s", path, l.err.Error())
+	}
+	return l.lines[path], nil
+}
+
+type lineFrame struct {
+	path      string
+	isArray   bool
+	index     int
+	expectKey bool
+	keyPath   string
+}
+
+// getLines maps the path of every key and array element in jsonData, in the same
+// form as View.GetPath, to the line it appears on in jsonData
+func getLines(jsonData []byte) (map[string]int, error) {
+	lines := map[string]int{}
+	var newlines []int
+	for index, char := range jsonData {
+		if char == '\n' {
+			newlines = append(newlines, index)
+		}
+	}
+	lineAt := func(offset int64) int {
+		return sort.SearchInts(newlines, int(offset)) + 1
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(jsonData))
+	var stack []*lineFrame
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return lines, nil
+		} else if err != nil {
+			return lines, err
+		}
+		line := lineAt(decoder.InputOffset() - 1)
+
+		if delim, ok := token.(json.Delim); ok && (delim == '}' || delim == ']') {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+
+		path := ""
+		if len(stack) > 0 {
+			top := stack[len(stack)-1]
+			if top.expectKey {
+				top.keyPath = joinPath(top.path, token.(string))
+				top.expectKey = false
+				lines[top.keyPath] = line
+				continue
+			}
+			if top.isArray {
+				path = top.path + "[" + strconv.Itoa(top.index) + "]"
+				top.index++
+				lines[path] = line
+			} else {
+				path = top.keyPath
+				top.expectKey = true
+			}
+		}
+
+		if delim, ok := token.(json.Delim); ok {
+			stack = append(stack, &lineFrame{path: path, isArray: delim == '[', expectKey: delim == '{'})
+		}
+	}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -6,11 +6,12 @@ import "fmt"
 type MasterNodeList struct {
 	nodes      []Node
 	loadStatus error
+	lines      *lineIndex
 }
 
 // NewMasterNodeList stuff
 func NewMasterNodeList(jsonData []byte, blocking bool) (MasterNodeList, error) {
-	m := MasterNodeList{[]Node{}, fmt.Errorf("Incomplete")}
+	m := MasterNodeList{[]Node{}, fmt.Errorf("Incomplete"), &lineIndex{jsonData: jsonData}}
 	parser := NewParser(&m.nodes, m.updateLoadStatus)
 	return m, parser.Parse(jsonData, blocking)
 }
@@ -34,6 +35,12 @@ func (m MasterNodeList) GetNodeView() (View, error) {
 	return view, m.loadStatus
 }
 
+// GetLine returns the line in the original JSON that path, e.g. items[0].metadata.name,
+// appears on or 0 if it is unknown. The lines are found the first time this is called
+func (m MasterNodeList) GetLine(path string) (int, error) {
+	return m.lines.get(path)
+}
+
 func (m *MasterNodeList) updateLoadStatus(err error) {
 	m.loadStatus = err
 }
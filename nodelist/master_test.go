@@ -26,3 +26,39 @@ func TestReturnsNodeViewForMaster(t *testing.T) {
 		t.Errorf("Expected size to be 17 but got %d", actual.Size())
 	}
 }
+
+const linesJSON = `{
+    "items": [
+        {
+            "kind": "Pod",
+            "spec": {"containers": [{"name": "app"}, {"name": "sidecar"}]}
+        },
+        "plain"
+    ],
+    "kind": "List"
+}`
+
+func TestGetLineReturnsLineOfKeysAndElements(t *testing.T) {
+	m, _ := nodelist.NewMasterNodeList([]byte(linesJSON), true)
+	expected := map[string]int{"items": 2, "items[0]": 3, "items[0].kind": 4,
+		"items[0].spec.containers[1].name": 5, "items[1]": 7, "kind": 9}
+	for path, line := range expected {
+		if actual, _ := m.GetLine(path); actual != line {
+			t.Errorf("Expected line %d for '%s' but got %d", line, path, actual)
+		}
+	}
+}
+
+func TestGetLineReturnsZeroForUnknownPath(t *testing.T) {
+	m, _ := nodelist.NewMasterNodeList([]byte(linesJSON), true)
+	if actual, err := m.GetLine("items[5]"); actual != 0 || err != nil {
+		t.Errorf("Expected 0 but got %d (%v)", actual, err)
+	}
+}
+
+func TestGetLineReturnsErrorForInvalidJSON(t *testing.T) {
+	m, _ := nodelist.NewMasterNodeList([]byte(`{"items": [}`), true)
+	if _, err := m.GetLine("items"); err == nil {
+		t.Errorf("Expected an error but got nothing")
+	}
+}
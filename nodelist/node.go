@@ -22,16 +22,44 @@ func (mt MatchType) String() string {
 	return [...]string{"Any", "Key", "Value"}[mt]
 }
 
+// ColourEnum lists the colours a node can be shown in, e.g. to mark differences between two configs
+type ColourEnum int8
+
+const (
+	// NOCOLOUR a
+	NOCOLOUR ColourEnum = iota
+	// GREEN a
+	GREEN
+	// RED a
+	RED
+	// YELLOW a
+	YELLOW
+)
+
+func (ce ColourEnum) String() string {
+	return [...]string{"None", "Green", "Red", "Yellow"}[ce]
+}
+
+// apply wraps text in the escape codes for the colour
+func (ce ColourEnum) apply(text string) string {
+	if ce == NOCOLOUR || text == "" {
+		return text
+	}
+	return [...]string{"", "\033[32m", "\033[31m", "\033[33m"}[ce] + text + "\033[0m"
+}
+
 // Node stored information about node is JSON data
 type Node struct {
-	key   string
-	value string
-	level int
+	key      string
+	value    string
+	level    int
+	colour   ColourEnum
+	embedded EmbedEnum
 }
 
 // NewNode stuff
 func NewNode(key, value string, level int) Node {
-	return Node{key, value, level}
+	return Node{key, value, level, NOCOLOUR, NOTEMBEDDED}
 }
 
 // GetJSON returns formatted JSON for the node. If full is false, the key is excluded
@@ -47,6 +75,12 @@ func (n Node) GetNode() string {
 	return strings.TrimLeft(n.key, "[]")
 }
 
+// GetEmbedded returns the format of the document the node was decoded from, or NOTEMBEDDED if it
+// is part of the original JSON
+func (n Node) GetEmbedded() EmbedEnum {
+	return n.embedded
+}
+
 // GetLevel returns the nodes level
 func (n Node) GetLevel() int {
 	return n.level
@@ -12,6 +12,7 @@ import (
 type NodeList struct {
 	master          MasterNodeList
 	views           map[string]View
+	viewCounts      map[string]int
 	currentView     View
 	currentViewName string
 	topNodeIndex    int
@@ -32,9 +33,9 @@ func NewNodeList(jsonData []byte, blocking bool) (NodeList, error) {
 		if err != nil {
 			return NodeList{}, err
 		}
-		return NodeList{master, map[string]View{"main": view}, view, "main", 0, 0, 0}, nil
+		return NodeList{master, map[string]View{"main": view}, map[string]int{}, view, "main", 0, 0, 0}, nil
 	}
-	nodeList := NodeList{master, map[string]View{}, View{}, "", 0, 0, 0}
+	nodeList := NodeList{master, map[string]View{}, map[string]int{}, View{}, "", 0, 0, 0}
 	//subscribe to master callback
 	return nodeList, nil
 }
@@ -46,6 +47,16 @@ func (n NodeList) GetJSON(num int) string {
 	return n.currentView.GetJSON(n.activeNodeIndex, n.jsonViewOffset, num)
 }
 
+// GetColouredJSON is the same as GetJSON but with nodes shown in the colour set by SetColour
+func (n NodeList) GetColouredJSON(num int) string {
+	return n.currentView.GetColouredJSON(n.activeNodeIndex, n.jsonViewOffset, num)
+}
+
+// GetNodeJSON returns formatted json for nodeIndex in the current view and everything below it
+func (n NodeList) GetNodeJSON(nodeIndex int) string {
+	return n.currentView.GetJSON(nodeIndex, 0, -1)
+}
+
 // GetNodes returns a formated string list of visible nodes from topNode
 // and is only num nodes long
 func (n NodeList) GetNodes(num int) string {
@@ -74,6 +85,11 @@ func (n *NodeList) SetActiveNode(index int) {
 	n.jsonViewOffset = 0
 }
 
+// GetActiveNode returns the index of the node set by SetActiveNode
+func (n NodeList) GetActiveNode() int {
+	return n.activeNodeIndex
+}
+
 // MoveJSONView offsets the Json view returned
 func (n *NodeList) MoveJSONView(offset int) {
 	n.jsonViewOffset += offset
@@ -95,6 +111,99 @@ func (n NodeList) GetRelativesMatching(nodeIndex, relativeStartLevel, depth int,
 	return n.currentView.GetRelativesMatching(nodeIndex, relativeStartLevel, depth, regex, matchType, equal)
 }
 
+// GetPath returns the path from Root to nodeIndex in the current view, e.g. items[0].metadata.name
+func (n NodeList) GetPath(nodeIndex int) string {
+	return n.currentView.GetPath(nodeIndex)
+}
+
+// GetPointer returns the JSON Pointer to nodeIndex in the current view, e.g. /items/0/metadata/name
+func (n NodeList) GetPointer(nodeIndex int) string {
+	return n.currentView.GetPointer(nodeIndex)
+}
+
+// GetLine returns the line nodeIndex in the current view appears on in the original JSON or 0 if unknown
+func (n NodeList) GetLine(nodeIndex int) (int, error) {
+	return n.master.GetLine(n.currentView.GetPath(nodeIndex))
+}
+
+// GetResourceInfo returns the Kubernetes object nodeIndex in the current view is part of
+func (n NodeList) GetResourceInfo(nodeIndex int) ResourceInfo {
+	return n.currentView.GetResourceInfo(nodeIndex)
+}
+
+// GetResourceIndices returns the index of every object in the top level items array of the current view
+func (n NodeList) GetResourceIndices() []int {
+	return n.currentView.GetResourceIndices()
+}
+
+// GetChild returns the index of the node at keys below nodeIndex in the current view
+func (n NodeList) GetChild(nodeIndex int, keys ...string) (int, bool) {
+	return n.currentView.GetChild(nodeIndex, keys...)
+}
+
+// GetChildren returns the indices of the direct children of nodeIndex in the current view
+func (n NodeList) GetChildren(nodeIndex int) []int {
+	return n.currentView.GetChildren(nodeIndex)
+}
+
+// GetKey returns the key of nodeIndex in the current view
+func (n NodeList) GetKey(nodeIndex int) string {
+	return n.currentView.GetKey(nodeIndex)
+}
+
+// GetValue returns the unquoted value of nodeIndex in the current view
+func (n NodeList) GetValue(nodeIndex int) string {
+	return n.currentView.GetValue(nodeIndex)
+}
+
+// GetRawValue returns the value of nodeIndex in the current view as it is written in JSON
+func (n NodeList) GetRawValue(nodeIndex int) string {
+	return n.currentView.GetRawValue(nodeIndex)
+}
+
+// GetChildValue returns the unquoted value at keys below nodeIndex in the current view
+func (n NodeList) GetChildValue(nodeIndex int, keys ...string) string {
+	return n.currentView.GetChildValue(nodeIndex, keys...)
+}
+
+// GetLastChild returns the index of the last descendant of nodeIndex in the current view
+func (n NodeList) GetLastChild(nodeIndex int) int {
+	return n.currentView.GetLastChild(nodeIndex)
+}
+
+// GetEmbedded returns the format of the document nodeIndex in the current view was decoded from,
+// or NOTEMBEDDED if it is part of the original JSON
+func (n NodeList) GetEmbedded(nodeIndex int) EmbedEnum {
+	return n.currentView.GetEmbedded(nodeIndex)
+}
+
+// ExpandEmbedded decodes the JSON, YAML, base64 and PEM documents held in string values of the
+// current view into nodes below them, so searches can reach inside them. The view is kept
+// expanded when reset and the selected node stays selected. Returns the number of values expanded
+func (n *NodeList) ExpandEmbedded() (int, error) {
+	top, active := n.currentView.nodes[n.topNodeIndex].node, n.currentView.nodes[n.activeNodeIndex].node
+	if saved, ok := n.views[n.currentViewName]; ok {
+		expandedSaved, _, err := saved.ExpandEmbedded()
+		if err != nil {
+			return 0, err
+		}
+		n.views[n.currentViewName] = expandedSaved
+	}
+	view, count, err := n.currentView.ExpandEmbedded()
+	if err != nil {
+		return 0, err
+	}
+	n.currentView = view
+	n.topNodeIndex, n.activeNodeIndex = view.indexOf(top), view.indexOf(active)
+	return count, nil
+}
+
+// SetColour sets the colour nodeIndices in the current view are shown in. As the colour is
+// part of the node it is kept in every view the node is in
+func (n *NodeList) SetColour(nodeIndices []int, colour ColourEnum) {
+	n.currentView.SetColour(nodeIndices, colour)
+}
+
 // Filter stuff
 func (n *NodeList) Filter(nodeIndices []int) error {
 	newView, err := n.currentView.Filter(nodeIndices)
@@ -126,18 +235,64 @@ func (n *NodeList) FindNextHighlight() error {
 // SplitViews stuff
 // Split seperates MasterNodeList into different NodeListViews based on seperator
 // e.g. "items = kind" will find array items and split based on the value of kind in each element
-// If not in items array or does not have kind, will be put into "main" group
+// Several targets can be given, e.g. "items = metadata.namespace, kind", to create views for
+// each namespace along with views such as "kube-system/Deployment" below them. See ParseSeparator
+// for wildcard roots and regex grouping
+// If an item does not have kind, will be put into the "ungrouped" view
 // Can choose to wait for loading and split to complete with blocking
 func (n *NodeList) SplitViews(separator string) error {
-	root, target := parseSeparator(separator)
-	split, err := n.views["main"].Split(root, target)
+	root, targets, err := ParseSeparator(separator)
+	if err != nil {
+		return err
+	}
+	split, counts, err := n.views["main"].split(root, targets)
 	if err != nil {
 		return err
 	}
 	for name, nodes := range split {
 		n.views[name] = nodes
+		n.viewCounts[name] = counts[name]
+	}
+
+	return nil
+}
+
+// AddView adds a view called name containing each node of nodeIndices in the current view
+// of source, along with its parents and all of its children. Source can be a different
+// NodeList, e.g. to show resources that are only in another config
+func (n *NodeList) AddView(name string, source NodeList, nodeIndices []int) error {
+	var indices []int
+	for _, index := range nodeIndices {
+		for i := index; i <= source.currentView.getLastChild(index); i++ {
+			indices = append(indices, i)
+		}
+	}
+	view, err := source.currentView.Filter(indices)
+	if err != nil {
+		return err
 	}
+	n.views[name] = view
+	n.viewCounts[name] = len(nodeIndices)
+	return nil
+}
 
+// RemoveView removes the view called name along with any views below it, e.g. kube-system/Deployment
+// below kube-system. If the current view is removed, the view is changed to "main"
+func (n *NodeList) RemoveView(name string) error {
+	if name == "main" {
+		return fmt.Errorf("The main view cannot be removed")
+	} else if _, ok := n.views[name]; !ok {
+		return fmt.Errorf("View with name, '%s', does not exist", name)
+	}
+	for view := range n.views {
+		if view == name || strings.HasPrefix(view, name+"/") {
+			delete(n.views, view)
+			delete(n.viewCounts, view)
+		}
+	}
+	if _, ok := n.views[n.currentViewName]; !ok {
+		return n.SetView("main")
+	}
 	return nil
 }
 
@@ -151,6 +306,28 @@ func (n NodeList) ListViews() []string {
 	return keys
 }
 
+// ViewTreeItem is a line of the view tree and the name of the view it represents
+type ViewTreeItem struct {
+	Name string
+	Line string
+}
+
+// GetViewTree returns the views as a tree, with views like "kube-system/Deployment" placed under
+// "kube-system", along with the number of items in each view created by a split
+func (n NodeList) GetViewTree() []ViewTreeItem {
+	children := map[string][]string{}
+	for _, name := range n.ListViews() {
+		parent := ""
+		if index := strings.LastIndex(name, "/"); index > 0 {
+			if _, ok := n.views[name[:index]]; ok {
+				parent = name[:index]
+			}
+		}
+		children[parent] = append(children[parent], name)
+	}
+	return n.getViewTreeItems("", "", children)
+}
+
 // GetCurrentView returns the name of the current view
 func (n NodeList) GetCurrentView() string {
 	return n.currentViewName
@@ -176,17 +353,24 @@ func (n *NodeList) Save(filename string) error {
 	return utils.Save(filename, n.currentView.GetJSON(n.activeNodeIndex, 0, -1), true)
 }
 
-func parseSeparator(sep string) ([]string, []string) {
-	split := strings.Split(sep, "=")
-	if len(split) == 0 {
-		return []string{}, []string{}
-	} else if len(split) == 1 {
-		return []string{}, splitSeparator(split[0])
+func (n NodeList) getViewTreeItems(parent, prefix string, children map[string][]string) []ViewTreeItem {
+	var items []ViewTreeItem
+	for index, name := range children[parent] {
+		line := strings.TrimPrefix(name, parent+"/")
+		if count, ok := n.viewCounts[name]; ok {
+			line += fmt.Sprintf(" (%d)", count)
+		}
+		childPrefix := prefix
+		if parent != "" {
+			branch := prefix + "├──"
+			if index == len(children[parent])-1 {
+				branch = prefix + "└──"
+			}
+			line = branch + line
+			childPrefix = convertParentPrefix(branch)
+		}
+		items = append(items, ViewTreeItem{name, line})
+		items = append(items, n.getViewTreeItems(name, childPrefix, children)...)
 	}
-	return splitSeparator(split[0]), splitSeparator(split[1])
-}
-
-func splitSeparator(sep string) []string {
-	trimmedSep := strings.Trim(sep, " ")
-	return strings.Split(trimmedSep, ".")
+	return items
 }
@@ -193,8 +193,60 @@ func TestCanResetView(t *testing.T) {
 	}
 }
 
+func TestCanSplitNodesOnSeveralKeys(t *testing.T) {
+	nl, _ := nodelist.NewNodeList([]byte(splitNodesResources), true)
+	if err := nl.SplitViews("items = metadata.namespace, kind"); err != nil {
+		t.Fatalf("Expected no error but got %s", err.Error())
+	}
+	expected := []string{"default", "default/Service", "kube-system", "kube-system/Deployment", "kube-system/Service", "main", "ungrouped", "ungrouped/ClusterRole"}
+	actual := nl.ListViews()
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected '%v' but got '%v'", expected, actual)
+	}
+}
+
+func TestSplitOnSeveralKeysOnlyIncludesMatchingItems(t *testing.T) {
+	nl, _ := nodelist.NewNodeList([]byte(splitNodesResources), true)
+	nl.SplitViews("items = metadata.namespace, kind")
+	nl.SetView("kube-system/Service")
+	expected := "Root\n└──items\n   └──1\n      ├──kind\n      └──metadata"
+	actual := nl.GetNodes(5)
+	if actual != expected {
+		t.Errorf("Expected '%s' but got '%s'", expected, actual)
+	}
+}
+
+func TestGetViewTreeNestsViewsWithCounts(t *testing.T) {
+	nl, _ := nodelist.NewNodeList([]byte(splitNodesResources), true)
+	nl.SplitViews("items = metadata.namespace, kind")
+	expected := []nodelist.ViewTreeItem{
+		{Name: "default", Line: "default (1)"},
+		{Name: "default/Service", Line: "└──Service (1)"},
+		{Name: "kube-system", Line: "kube-system (3)"},
+		{Name: "kube-system/Deployment", Line: "├──Deployment (2)"},
+		{Name: "kube-system/Service", Line: "└──Service (1)"},
+		{Name: "main", Line: "main"},
+		{Name: "ungrouped", Line: "ungrouped (1)"},
+		{Name: "ungrouped/ClusterRole", Line: "└──ClusterRole (1)"},
+	}
+	actual := nl.GetViewTree()
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected '%v' but got '%v'", expected, actual)
+	}
+}
+
 // Test data
 
+var splitNodesResources = `{
+	"items": [
+		{"kind": "Deployment", "metadata": {"namespace": "kube-system"}},
+		{"kind": "Service", "metadata": {"namespace": "kube-system"}},
+		{"kind": "Deployment", "metadata": {"namespace": "kube-system"}},
+		{"kind": "Service", "metadata": {"namespace": "default"}},
+		{"kind": "ClusterRole", "metadata": {}}
+	]
+}`
+
 var splitNodesBase = `{
 	"path": {
 		"to": {
@@ -0,0 +1,130 @@
+package nodelist
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ResourceInfo identifies the Kubernetes object that a node is part of. Path is the element of
+// the top level items array, or of a top level array, that holds the object, e.g. items[0], and
+// is empty if the object is the whole document. Fields missing from the object are left empty
+type ResourceInfo struct {
+	Path      string
+	Kind      string
+	Namespace string
+	Name      string
+}
+
+// String returns the kind and namespace/name of the object, e.g. "Deployment ns/foo",
+// falling back to the path if the object has no kind or name
+func (ri ResourceInfo) String() string {
+	name := ri.Name
+	if ri.Namespace != "" {
+		name = ri.Namespace + "/" + name
+	}
+	if ri.Kind == "" || ri.Name == "" {
+		if ri.Path == "" {
+			return "Document"
+		}
+		return strings.TrimSpace(ri.Kind + " " + ri.Path)
+	}
+	return ri.Kind + " " + name
+}
+
+// GetResourceInfo returns the object that nodeIndex is part of
+func (v View) GetResourceInfo(nodeIndex int) ResourceInfo {
+	resourceIndex := v.getResourceIndex(nodeIndex)
+	return ResourceInfo{
+		Path:      v.GetPath(resourceIndex),
+		Kind:      v.GetChildValue(resourceIndex, "kind"),
+		Namespace: v.GetChildValue(resourceIndex, "metadata", "namespace"),
+		Name:      v.GetChildValue(resourceIndex, "metadata", "name"),
+	}
+}
+
+// GetResourceIndices returns the index of every element of the top level items array, or of a
+// top level array. If there are neither but Root has a kind then Root is the only resource
+func (v View) GetResourceIndices() []int {
+	if items, ok := v.GetChild(0, "items"); ok && v.nodes[items].node.value == "[" {
+		return append([]int{}, v.nodes[items].children...)
+	} else if v.nodes[0].node.value == "[" {
+		return append([]int{}, v.nodes[0].children...)
+	} else if _, ok := v.GetChild(0, "kind"); ok {
+		return []int{0}
+	}
+	return []int{}
+}
+
+// GetChild follows keys down from nodeIndex, returning false if there is no node at that path.
+// Array elements are matched with their index, e.g. "0"
+func (v View) GetChild(nodeIndex int, keys ...string) (int, bool) {
+	index := nodeIndex
+	for _, key := range keys {
+		found := false
+		for _, child := range v.nodes[index].children {
+			if childKey := v.nodes[child].node.key; childKey == key || childKey == "[]"+key {
+				index, found = child, true
+				break
+			}
+		}
+		if !found {
+			return 0, false
+		}
+	}
+	return index, true
+}
+
+// GetChildren returns the indices of the direct children of nodeIndex
+func (v View) GetChildren(nodeIndex int) []int {
+	return append([]int{}, v.nodes[nodeIndex].children...)
+}
+
+// GetKey returns the key of nodeIndex, with array elements given as their index
+func (v View) GetKey(nodeIndex int) string {
+	return strings.TrimPrefix(v.nodes[nodeIndex].node.key, "[]")
+}
+
+// GetValue returns the value of nodeIndex with strings unquoted. Objects and arrays
+// have a value of "{" and "[" respectively
+func (v View) GetValue(nodeIndex int) string {
+	value := v.nodes[nodeIndex].node.value
+	if unquoted, err := strconv.Unquote(value); err == nil {
+		return unquoted
+	}
+	return value
+}
+
+// GetRawValue returns the value of nodeIndex as it is written in JSON, e.g. with strings quoted
+func (v View) GetRawValue(nodeIndex int) string {
+	return v.nodes[nodeIndex].node.value
+}
+
+// GetChildValue returns the value at keys below nodeIndex, or "" if there is no node at that path
+func (v View) GetChildValue(nodeIndex int, keys ...string) string {
+	if index, ok := v.GetChild(nodeIndex, keys...); ok {
+		return v.GetValue(index)
+	}
+	return ""
+}
+
+// GetLastChild returns the index of the last descendant of nodeIndex, so nodeIndex
+// and all of its descendants are the range nodeIndex to GetLastChild(nodeIndex)
+func (v View) GetLastChild(nodeIndex int) int {
+	return v.getLastChild(nodeIndex)
+}
+
+// getResourceIndex returns the index of the element of the top level items array, or of a top
+// level array, that contains nodeIndex. If there isn't one then Root is returned
+func (v View) getResourceIndex(nodeIndex int) int {
+	for index := nodeIndex; index > 0; index = v.nodes[index].parent {
+		node := v.nodes[index].node
+		if !strings.HasPrefix(node.key, "[]") {
+			continue
+		}
+		parent := v.nodes[v.nodes[index].parent].node
+		if node.level == 1 || (node.level == 2 && parent.key == "items") {
+			return index
+		}
+	}
+	return 0
+}
@@ -0,0 +1,57 @@
+package nodelist_test
+
+import (
+	"kube-review/nodelist"
+	"regexp"
+	"testing"
+)
+
+const resourceJSON = `{"apiVersion": "v1", "kind": "List", "items": [
+	{"kind": "Deployment", "metadata": {"name": "foo", "namespace": "ns"}, "spec": {"replicas": 1}},
+	{"kind": "ClusterRole", "metadata": {"name": "admin"}, "rules": []},
+	{"data": {"key": "value"}}
+]}`
+
+func getResourceInfo(t *testing.T, jsonData, key string) nodelist.ResourceInfo {
+	nl, _ := nodelist.NewNodeList([]byte(jsonData), true)
+	matches := nl.GetNodesMatching(regexp.MustCompile("^"+key+"$"), nodelist.KEY, true)
+	if len(matches) != 1 {
+		t.Fatalf("Expected one node with key '%s' but got %d", key, len(matches))
+	}
+	return nl.GetResourceInfo(matches[0])
+}
+
+func TestGetResourceInfoReturnsEnclosingItem(t *testing.T) {
+	expected := nodelist.ResourceInfo{Path: "items[0]", Kind: "Deployment", Namespace: "ns", Name: "foo"}
+	if actual := getResourceInfo(t, resourceJSON, "replicas"); actual != expected {
+		t.Errorf("Expected %v but got %v", expected, actual)
+	}
+}
+
+func TestGetResourceInfoHandlesTopLevelArray(t *testing.T) {
+	expected := nodelist.ResourceInfo{Path: "[0]", Kind: "Pod", Name: "bar"}
+	if actual := getResourceInfo(t, `[{"kind": "Pod", "metadata": {"name": "bar"}, "spec": {}}]`, "spec"); actual != expected {
+		t.Errorf("Expected %v but got %v", expected, actual)
+	}
+}
+
+func TestGetResourceInfoUsesDocumentOutsideOfArrays(t *testing.T) {
+	expected := nodelist.ResourceInfo{Kind: "Service", Name: "svc"}
+	if actual := getResourceInfo(t, `{"kind": "Service", "metadata": {"name": "svc"}, "spec": {}}`, "spec"); actual != expected {
+		t.Errorf("Expected %v but got %v", expected, actual)
+	}
+}
+
+func TestResourceInfoString(t *testing.T) {
+	tests := map[string]nodelist.ResourceInfo{
+		"Deployment ns/foo": {Path: "items[0]", Kind: "Deployment", Namespace: "ns", Name: "foo"},
+		"ClusterRole admin": {Path: "items[1]", Kind: "ClusterRole", Name: "admin"},
+		"items[2]":          {Path: "items[2]"},
+		"Document":          {},
+	}
+	for expected, info := range tests {
+		if actual := info.String(); actual != expected {
+			t.Errorf("Expected '%s' but got '%s'", expected, actual)
+		}
+	}
+}
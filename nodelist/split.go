@@ -0,0 +1,208 @@
+package nodelist
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// UngroupedView holds the items that are missing a split target or whose value does not match its regex
+const UngroupedView = "ungrouped"
+
+// SplitTarget is the path, below each item being split, to the value that names the item's view.
+// If Regex is set the value must match it, with the first capture group, or whole match if there
+// are no groups, used as the name
+type SplitTarget struct {
+	Path  []string
+	Regex *regexp.Regexp
+}
+
+// ParseSeparator reads a separator such as `items.* = metadata.namespace, metadata.name ~ "^(.*)-[a-z0-9]+$"`
+// into the root path, where "*" matches any key, and the targets to split each item at root by
+func ParseSeparator(separator string) ([]string, []SplitTarget, error) {
+	root := []string{}
+	targetList := separator
+	if parts := splitOutsideQuotes(separator, '='); len(parts) > 1 {
+		root = splitPath(parts[0])
+		targetList = strings.Join(parts[1:], "=")
+	}
+
+	var targets []SplitTarget
+	for _, targetString := range splitOutsideQuotes(targetList, ',') {
+		target, err := parseTarget(targetString)
+		if err != nil {
+			return nil, nil, err
+		}
+		targets = append(targets, target)
+	}
+	return root, targets, nil
+}
+
+// Split separates each child of the nodes at root into views named after the value at each target
+// below it. Given several targets, names join the values with "/", e.g. kube-system/Deployment,
+// and a view is also made for each level above, e.g. kube-system. Children missing a target
+// are named "ungrouped" at that level
+func (v View) Split(root []string, targets []SplitTarget) (map[string]View, error) {
+	views, _, err := v.split(root, targets)
+	return views, err
+}
+
+// Name returns the view name the target gives value or false if value is empty or does not
+// match the target's regex
+func (t SplitTarget) Name(value string) (string, bool) {
+	if t.Regex == nil {
+		return value, value != ""
+	}
+	match := t.Regex.FindStringSubmatch(value)
+	if match == nil {
+		return "", false
+	} else if len(match) > 1 {
+		return match[1], match[1] != ""
+	}
+	return match[0], match[0] != ""
+}
+
+/////////////////////////////////////////////////////////////////////////////////
+// PRIVATE FUNCTIONS
+
+// split does the work of Split, also returning the number of items in each view
+func (v View) split(root []string, targets []SplitTarget) (map[string]View, map[string]int, error) {
+	rootIndices, err := v.getSplitRoots(root)
+	if err != nil {
+		return map[string]View{}, map[string]int{}, err
+	}
+
+	groups := map[string][]int{}
+	counts := map[string]int{}
+	grouped := false
+	for _, rootIndex := range rootIndices {
+		for _, index := range v.nodes[rootIndex].children {
+			var indices []int
+			for i := index; i <= v.getLastChild(index); i++ {
+				indices = append(indices, i)
+			}
+			names := v.getSplitNames(index, targets)
+			for _, name := range names {
+				groups[name] = append(groups[name], indices...)
+				counts[name]++
+			}
+			grouped = grouped || (len(names) > 0 && names[0] != UngroupedView)
+		}
+	}
+	if !grouped {
+		return map[string]View{}, map[string]int{}, fmt.Errorf("There are no nodes at target")
+	}
+
+	views := map[string]View{}
+	for name, indices := range groups {
+		if views[name], err = v.Filter(indices); err != nil {
+			return map[string]View{}, map[string]int{}, err
+		}
+	}
+	return views, counts, nil
+}
+
+// getSplitRoots returns the indices of every node at root, where "*" matches any key
+func (v View) getSplitRoots(root []string) ([]int, error) {
+	indices := []int{0}
+	for _, key := range root {
+		var next []int
+		for _, index := range indices {
+			for _, child := range v.nodes[index].children {
+				if key == "*" || v.GetKey(child) == key {
+					next = append(next, child)
+				}
+			}
+		}
+		if len(next) == 0 {
+			return nil, fmt.Errorf("Root path does not match json data at %s", key)
+		}
+		indices = next
+	}
+	return indices, nil
+}
+
+// getSplitNames returns the name of the view, and each view above it, that the node at
+// nodeIndex belongs in
+func (v View) getSplitNames(nodeIndex int, targets []SplitTarget) []string {
+	var names []string
+	name := ""
+	for _, target := range targets {
+		if name != "" {
+			name += "/"
+		}
+		if value, ok := v.getSplitValue(nodeIndex, target); ok {
+			name += value
+		} else {
+			name += UngroupedView
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// getSplitValue returns the name target gives the node at nodeIndex or false if it has no
+// value at target or the value does not match the target's regex
+func (v View) getSplitValue(nodeIndex int, target SplitTarget) (string, bool) {
+	index, ok := v.GetChild(nodeIndex, target.Path...)
+	if !ok || v.nodes[index].node.GetCloseBracket() != "" {
+		return "", false
+	}
+	return target.Name(v.GetValue(index))
+}
+
+func parseTarget(target string) (SplitTarget, error) {
+	parts := splitOutsideQuotes(target, '~')
+	path := splitPath(parts[0])
+	if !isValidPath(path) {
+		return SplitTarget{}, fmt.Errorf("Invalid split target '%s'", strings.TrimSpace(target))
+	}
+	if len(parts) == 1 {
+		return SplitTarget{path, nil}, nil
+	} else if len(parts) > 2 {
+		return SplitTarget{}, fmt.Errorf("Invalid split target '%s'. Only one regex can be given", strings.TrimSpace(target))
+	}
+
+	pattern := strings.TrimSpace(parts[1])
+	if len(pattern) > 1 && pattern[0] == '"' && pattern[len(pattern)-1] == '"' {
+		pattern = pattern[1 : len(pattern)-1]
+	}
+	regex, err := regexp.Compile(pattern)
+	if err != nil {
+		return SplitTarget{}, fmt.Errorf("Invalid regex '%s' - %s", pattern, err.Error())
+	}
+	return SplitTarget{path, regex}, nil
+}
+
+func splitPath(path string) []string {
+	trimmedPath := strings.TrimSpace(path)
+	if trimmedPath == "" {
+		return []string{}
+	}
+	return strings.Split(trimmedPath, ".")
+}
+
+func isValidPath(path []string) bool {
+	for _, key := range path {
+		if key == "" {
+			return false
+		}
+	}
+	return len(path) > 0
+}
+
+// splitOutsideQuotes splits s at each sep that is not within double quotes
+func splitOutsideQuotes(s string, sep rune) []string {
+	var parts []string
+	quoted := false
+	start := 0
+	for index, char := range s {
+		if char == '"' && (index == 0 || s[index-1] != '\\') {
+			quoted = !quoted
+		} else if char == sep && !quoted {
+			parts = append(parts, s[start:index])
+			start = index + 1
+		}
+	}
+	return append(parts, s[start:])
+}
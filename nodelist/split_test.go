@@ -0,0 +1,116 @@
+package nodelist_test
+
+import (
+	"kube-review/nodelist"
+	"reflect"
+	"testing"
+)
+
+func TestParseSeparatorReadsRootAndTargets(t *testing.T) {
+	root, targets, err := nodelist.ParseSeparator(`items.* = metadata.namespace, metadata.name ~ "^(.*)-[0-9]+$"`)
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err.Error())
+	}
+	if expected := []string{"items", "*"}; !reflect.DeepEqual(root, expected) {
+		t.Errorf("Expected root %v but got %v", expected, root)
+	}
+	if len(targets) != 2 || !reflect.DeepEqual(targets[0].Path, []string{"metadata", "namespace"}) || targets[0].Regex != nil {
+		t.Fatalf("Expected metadata.namespace without a regex but got %v", targets)
+	}
+	if expected := "^(.*)-[0-9]+$"; targets[1].Regex == nil || targets[1].Regex.String() != expected {
+		t.Errorf("Expected regex %s but got %v", expected, targets[1].Regex)
+	}
+}
+
+func TestParseSeparatorAllowsSeparatorsInQuotedRegex(t *testing.T) {
+	_, targets, err := nodelist.ParseSeparator(`items = kind ~ "a=b,c"`)
+	if err != nil || len(targets) != 1 || targets[0].Regex.String() != "a=b,c" {
+		t.Errorf("Expected a single target with regex 'a=b,c' but got %v, %v", targets, err)
+	}
+}
+
+func TestParseSeparatorReturnsErrorForInvalidTargets(t *testing.T) {
+	for _, separator := range []string{"", "items = ", "items = kind,", "items = metadata..name", `items = kind ~ "["`} {
+		if _, _, err := nodelist.ParseSeparator(separator); err == nil {
+			t.Errorf("Expected an error for '%s' but got none", separator)
+		}
+	}
+}
+
+func TestSplitWithWildcardRootSplitsNestedArrays(t *testing.T) {
+	nl, _ := nodelist.NewNodeList([]byte(splitNested), true)
+	if err := nl.SplitViews("items.*.spec.containers = image"); err != nil {
+		t.Fatalf("Expected no error but got %s", err.Error())
+	}
+	expected := []string{"main", "nginx", "redis"}
+	if actual := nl.ListViews(); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected %v but got %v", expected, actual)
+	}
+	nl.SetView("nginx")
+	expectedNodes := "Root\n└──items\n   ├──0\n   │  └──spec\n   │     └──containers\n   │        └──0\n   │           └──image\n" +
+		"   └──1\n      └──spec\n         └──containers\n            └──1\n               └──image"
+	if actual := nl.GetNodes(20); actual != expectedNodes {
+		t.Errorf("Expected '%s' but got '%s'", expectedNodes, actual)
+	}
+}
+
+func TestSplitGroupsByRegexCaptureGroup(t *testing.T) {
+	nl, _ := nodelist.NewNodeList([]byte(splitNested), true)
+	nl.SplitViews(`items = metadata.name ~ "^([a-z]+)-"`)
+	expected := []nodelist.ViewTreeItem{{Name: "main", Line: "main"}, {Name: "ungrouped", Line: "ungrouped (1)"}, {Name: "web", Line: "web (2)"}}
+	if actual := nl.GetViewTree(); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected %v but got %v", expected, actual)
+	}
+}
+
+func TestSplitPutsItemsMissingTargetInUngrouped(t *testing.T) {
+	nl, _ := nodelist.NewNodeList([]byte(splitNodesResources), true)
+	nl.SplitViews("items = metadata.namespace")
+	nl.SetView("ungrouped")
+	expected := "Root\n└──items\n   └──4\n      ├──kind\n      └──metadata"
+	if actual := nl.GetNodes(20); actual != expected {
+		t.Errorf("Expected '%s' but got '%s'", expected, actual)
+	}
+}
+
+func TestSplitDoesNotDuplicateRootNodes(t *testing.T) {
+	nl, _ := nodelist.NewNodeList([]byte(splitNodesResources), true)
+	nl.SplitViews("items = kind")
+	nl.SetView("Deployment")
+	expected := "Root\n└──items\n   ├──0\n   │  ├──kind\n   │  └──metadata\n   │     └──namespace\n" +
+		"   └──2\n      ├──kind\n      └──metadata\n         └──namespace"
+	if actual := nl.GetNodes(20); actual != expected {
+		t.Errorf("Expected '%s' but got '%s'", expected, actual)
+	}
+}
+
+func TestRemoveViewRemovesViewsBelowIt(t *testing.T) {
+	nl, _ := nodelist.NewNodeList([]byte(splitNodesResources), true)
+	nl.SplitViews("items = metadata.namespace, kind")
+	nl.SetView("kube-system/Service")
+	if err := nl.RemoveView("kube-system"); err != nil {
+		t.Fatalf("Expected no error but got %s", err.Error())
+	}
+	expected := []string{"default", "default/Service", "main", "ungrouped", "ungrouped/ClusterRole"}
+	if actual := nl.ListViews(); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected %v but got %v", expected, actual)
+	}
+	if actual := nl.GetCurrentView(); actual != "main" {
+		t.Errorf("Expected current view to be main but got %s", actual)
+	}
+}
+
+func TestRemoveViewCannotRemoveMain(t *testing.T) {
+	nl, _ := nodelist.NewNodeList([]byte(splitNodesResources), true)
+	if err := nl.RemoveView("main"); err == nil {
+		t.Errorf("Expected an error but got none")
+	}
+}
+
+var splitNested = `{
+	"items": [
+		{"metadata": {"name": "web-1"}, "spec": {"containers": [{"image": "nginx"}, {"image": "redis"}]}},
+		{"metadata": {"name": "web-2"}, "spec": {"containers": [{"image": "redis"}, {"image": "nginx"}]}},
+		{"metadata": {"name": "db"}, "spec": {"containers": []}}
+	]
+}`
@@ -51,7 +51,8 @@ func (v View) GetNodes(start, num int) string {
 		if v.nodes[index].prefix == "" {
 			v.updatePrefix(index)
 		}
-		nodes += v.nodes[index].prefix + v.nodes[index].node.GetNode() + "\n"
+		node := v.nodes[index].node
+		nodes += v.nodes[index].prefix + node.colour.apply(node.GetNode()) + "\n"
 	}
 	return strings.TrimRight(nodes, "\n")
 }
@@ -59,7 +60,12 @@ func (v View) GetNodes(start, num int) string {
 // GetJSON returns formated JSON for nodeIndex. The JSON output can be offset and
 // number of lines returned limited using the offset and num inputs
 func (v View) GetJSON(nodeIndex, offset, num int) string {
-	return v.getJSON(nodeIndex, nodeIndex, 0, offset, &num)
+	return v.getJSON(nodeIndex, nodeIndex, 0, offset, &num, false)
+}
+
+// GetColouredJSON is the same as GetJSON but with each node shown in its colour
+func (v View) GetColouredJSON(nodeIndex, offset, num int) string {
+	return v.getJSON(nodeIndex, nodeIndex, 0, offset, &num, true)
 }
 
 // GetNodesMatching searches entire view for matches of matchtype to regex. Set equal to false to invert result
@@ -95,7 +101,7 @@ func (v View) Filter(nodeIndices []int) (View, error) {
 		nodes = append(nodes, v.nodes[index].node)
 	}
 	if len(nodes) == 0 {
-		nodes = append(nodes, &Node{"Root", "", 0})
+		nodes = append(nodes, &Node{"Root", "", 0, NOCOLOUR, NOTEMBEDDED})
 	}
 	return NewView(nodes)
 }
@@ -110,6 +116,13 @@ func (v *View) Highlight(nodeIndices []int) {
 	}
 }
 
+// SetColour sets the colour of the nodes pointed to by nodeIndices
+func (v View) SetColour(nodeIndices []int, colour ColourEnum) {
+	for _, index := range nodeIndices {
+		v.nodes[index].node.colour = colour
+	}
+}
+
 // FindNextHighlight will return a new offset to show next highlight
 func (v View) FindNextHighlight(nodeIndex, startOffset int) (int, error) {
 	numTotalChildren := v.getLastChild(nodeIndex) - nodeIndex
@@ -131,6 +144,33 @@ func (v View) FindNextHighlight(nodeIndex, startOffset int) (int, error) {
 	return 0, fmt.Errorf("No highlighted nodes in nodeIndex: %d", nodeIndex)
 }
 
+// GetPath returns the keys from Root to nodeIndex, with array elements shown as
+// an index, e.g. items[0].metadata.name
+func (v View) GetPath(nodeIndex int) string {
+	var path string
+	for index := nodeIndex; index > 0; index = v.nodes[index].parent {
+		key := v.nodes[index].node.key
+		if path != "" && path[0] != '[' {
+			path = "." + path
+		}
+		if strings.HasPrefix(key, "[]") {
+			path = "[" + key[2:] + "]" + path
+		} else {
+			path = key + path
+		}
+	}
+	return path
+}
+
+// GetPointer returns the JSON Pointer (RFC 6901) from Root to nodeIndex, e.g. /items/0/metadata/name
+func (v View) GetPointer(nodeIndex int) string {
+	var pointer string
+	for index := nodeIndex; index > 0; index = v.nodes[index].parent {
+		pointer = "/" + pointerEscaper.Replace(v.GetKey(index)) + pointer
+	}
+	return pointer
+}
+
 // GetSplitNodes returns value of nodeIndex and all sibling nodeIndices for splitting view
 // parentLevel defines how many levels above node index the parent is
 func (v View) GetSplitNodes(nodeIndex int, parentLevel int) (string, []*Node) {
@@ -147,48 +187,24 @@ func (v View) GetSplitNodes(nodeIndex int, parentLevel int) (string, []*Node) {
 	return v.nodes[nodeIndex].node.value, nodes
 }
 
-// Split stuff
-func (v View) Split(root, target []string) (map[string]View, error) {
-	rootIndex, rootNodes, rootErr := v.getSplitRoot(root)
-	if rootErr != nil {
-		return map[string]View{}, rootErr
-	}
-
-	targetIndices := v.getTargetIndices(target, rootIndex)
-	if len(targetIndices) == 0 {
-		return map[string]View{}, fmt.Errorf("There are no nodes at target")
-	}
-
-	viewNodes := v.splitNodes(targetIndices, len(target))
-	for name, nodes := range viewNodes {
-		viewNodes[name] = append(rootNodes, nodes...)
-	}
-
-	views := map[string]View{}
-	for name, nodes := range viewNodes {
-		var err error
-		views[name], err = NewView(nodes)
-		if err != nil {
-			return map[string]View{}, err
-		}
-	}
-	return views, nil
-}
-
 /////////////////////////////////////////////////////////////////////////////////
 // PRIVATE FUNCTIONS
 
-func (v View) getJSON(activeIndex, nodeIndex, level, offset int, num *int) string {
+func (v View) getJSON(activeIndex, nodeIndex, level, offset int, num *int, coloured bool) string {
 	var finalJSON string
 	if *num != 0 {
-		JSON := v.nodes[nodeIndex].node.GetJSON(level > 0)
+		colour := NOCOLOUR
+		if coloured {
+			colour = v.nodes[nodeIndex].node.colour
+		}
+		JSON := colour.apply(v.nodes[nodeIndex].node.GetJSON(level > 0))
 		if JSON != "" && nodeIndex >= activeIndex+offset {
 			finalJSON = strings.Repeat(spacing, level) + JSON
 			*num--
 		}
 		var childrenJSON string
-		for _, childIndex := range v.nodes[nodeIndex].children {
-			childJSON := v.getJSON(activeIndex, childIndex, level+1, offset, num)
+		for _, childIndex := range v.getJSONChildren(nodeIndex) {
+			childJSON := v.getJSON(activeIndex, childIndex, level+1, offset, num, coloured)
 			if childJSON != "" {
 				childrenJSON += childJSON + ",\n"
 			}
@@ -198,7 +214,7 @@ func (v View) getJSON(activeIndex, nodeIndex, level, offset int, num *int) strin
 		}
 		finalJSON += strings.TrimRight(childrenJSON, ",\n")
 
-		closeBracket := v.nodes[nodeIndex].node.GetCloseBracket()
+		closeBracket := colour.apply(v.nodes[nodeIndex].node.GetCloseBracket())
 		if closeBracket != "" && *num != 0 && v.getLastChild(nodeIndex) >= activeIndex+offset {
 			finalJSON += "\n" + strings.Repeat(spacing, level) + closeBracket
 			*num--
@@ -207,6 +223,15 @@ func (v View) getJSON(activeIndex, nodeIndex, level, offset int, num *int) strin
 	return finalJSON
 }
 
+// getJSONChildren returns the children of nodeIndex, unless it is a string with an embedded
+// document below it, as the document is shown when its own node is selected
+func (v View) getJSONChildren(nodeIndex int) []int {
+	if v.nodes[nodeIndex].node.GetCloseBracket() == "" {
+		return []int{}
+	}
+	return v.nodes[nodeIndex].children
+}
+
 func (v View) getChildrenMatching(nodeIndex int, levels int, searchFunction searchFunctionType) []int {
 	var matchedIndices []int
 	if levels == 0 {
@@ -301,64 +326,6 @@ func (v View) appendAndSortParentIndices(nodeIndices []int) []int {
 	return finalIndices
 }
 
-func (v View) getSplitRoot(root []string) (int, []*Node, error) {
-	rootIndex := 0
-	rootNodes := []*Node{v.nodes[0].node}
-	for _, nodeKey := range root {
-		found := false
-		for _, index := range v.nodes[rootIndex].children {
-			if v.nodes[index].node.key == nodeKey {
-				rootIndex = index
-				rootNodes = append(rootNodes, v.nodes[rootIndex].node)
-				found = true
-				break
-			}
-		}
-		if !found {
-			return -1, []*Node{}, fmt.Errorf("Root path does not match json data at %s", nodeKey)
-		}
-	}
-	return rootIndex, rootNodes, nil
-}
-
-func (v View) getTargetIndices(target []string, rootIndex int) []int {
-	targetIndices := []int{}
-	for _, index := range v.nodes[rootIndex].children {
-		nodeIndex := index
-		for nodeKeyIndex, nodeKey := range target {
-			for _, child := range v.nodes[nodeIndex].children {
-				if v.nodes[child].node.key == nodeKey {
-					if nodeKeyIndex == len(target)-1 {
-						targetIndices = append(targetIndices, child)
-					}
-					nodeIndex = child
-				}
-			}
-		}
-	}
-	return targetIndices
-}
-
-func (v View) splitNodes(targetIndices []int, parentLevel int) map[string][]*Node {
-	viewNodes := map[string][]*Node{}
-	for _, index := range targetIndices {
-		name := strings.Trim(v.nodes[index].node.value, "\"")
-
-		parentIndex := index
-		for i := 0; i < parentLevel; i++ {
-			parentIndex = v.nodes[parentIndex].parent
-		}
-
-		lastChildIndex := v.getLastChild(parentIndex)
-		var nodes []*Node
-		for i := parentIndex; i < lastChildIndex+1; i++ {
-			nodes = append(nodes, v.nodes[i].node)
-		}
-		viewNodes[name] = append(viewNodes[name], nodes...)
-	}
-	return viewNodes
-}
-
 func getSearchFunction(matchType MatchType, r *regexp.Regexp, equal bool) searchFunctionType {
 	if matchType == KEY {
 		return func(node *Node) bool { return node.MatchKey(r) == equal }
@@ -368,6 +335,8 @@ func getSearchFunction(matchType MatchType, r *regexp.Regexp, equal bool) search
 	return func(node *Node) bool { return node.Match(r) == equal }
 }
 
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
 var prefixConvert = map[rune]rune{
 	'─': ' ',
 	'│': '│',
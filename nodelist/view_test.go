@@ -5,6 +5,7 @@ import (
 	"kube-review/nodelist"
 	"reflect"
 	"regexp"
+	"sort"
 	"testing"
 )
 
@@ -205,12 +206,37 @@ func TestNewHighlightRemovesPreviousHighlights(t *testing.T) {
 	}
 }
 
+func TestGetPathReturnsKeysFromRoot(t *testing.T) {
+	view, _ := nodelist.NewView(createNodes(fullNodesRaw))
+	expected := "GlossDiv.GlossList.GlossEntry.GlossDef.GlossSeeAlso[1]"
+	actual := view.GetPath(9)
+	if actual != expected {
+		t.Errorf("Expected '%s' but got '%s'", expected, actual)
+	}
+}
+
+func TestGetPathOfRootIsEmpty(t *testing.T) {
+	view, _ := nodelist.NewView(createNodes(splitNodesRaw))
+	if actual := view.GetPath(0); actual != "" {
+		t.Errorf("Expected empty path but got '%s'", actual)
+	}
+}
+
+func TestGetPathHandlesArrayElementsWithChildren(t *testing.T) {
+	view, _ := nodelist.NewView(createNodes(splitNodesRaw))
+	expected := "path.to.root[1].path.to.target"
+	actual := view.GetPath(11)
+	if actual != expected {
+		t.Errorf("Expected '%s' but got '%s'", expected, actual)
+	}
+}
+
 // Now figure out how to test this...
 // Test returning errors for failed parts
 
 func TestSplitReturnsErrorIfRootNotValid(t *testing.T) {
 	view, _ := nodelist.NewView(createNodes(splitNodesRaw))
-	_, actual := view.Split([]string{"path", "to", "non-root"}, []string{"path", "to", "target"})
+	_, actual := view.Split([]string{"path", "to", "non-root"}, []nodelist.SplitTarget{{Path: []string{"path", "to", "target"}}})
 	if actual == nil {
 		t.Error("Expected an error but got none")
 	}
@@ -218,7 +244,7 @@ func TestSplitReturnsErrorIfRootNotValid(t *testing.T) {
 
 func TestSplitReturnsErrorIfTargetNotValid(t *testing.T) {
 	view, _ := nodelist.NewView(createNodes(splitNodesRaw))
-	_, actual := view.Split([]string{"path", "to", "root"}, []string{"path", "to", "non-target"})
+	_, actual := view.Split([]string{"path", "to", "root"}, []nodelist.SplitTarget{{Path: []string{"path", "to", "non-target"}}})
 	if actual == nil {
 		t.Error("Expected an error but got none")
 	}
@@ -226,12 +252,13 @@ func TestSplitReturnsErrorIfTargetNotValid(t *testing.T) {
 
 func TestSplitReturnsCorrectNamesForSplits(t *testing.T) {
 	view, _ := nodelist.NewView(createNodes(splitNodesRaw))
-	split, _ := view.Split([]string{"path", "to", "root"}, []string{"path", "to", "target"})
+	split, _ := view.Split([]string{"path", "to", "root"}, []nodelist.SplitTarget{{Path: []string{"path", "to", "target"}}})
 	actual := []string{}
 	for key := range split {
 		actual = append(actual, key)
 	}
-	expected := []string{"Hello", "Goodbye"}
+	sort.Strings(actual)
+	expected := []string{"Goodbye", "Hello"}
 	if !reflect.DeepEqual(actual, expected) {
 		t.Errorf("Expected %v but got %v", expected, actual)
 	}
@@ -239,7 +266,7 @@ func TestSplitReturnsCorrectNamesForSplits(t *testing.T) {
 
 func TestSplitReturnsCorrectViews(t *testing.T) {
 	view, _ := nodelist.NewView(createNodes(splitNodesRaw))
-	split, _ := view.Split([]string{"path", "to", "root"}, []string{"path", "to", "target"})
+	split, _ := view.Split([]string{"path", "to", "root"}, []nodelist.SplitTarget{{Path: []string{"path", "to", "target"}}})
 	expected := "\"Test\""
 	actual := split["Hello"].GetJSON(12, 0, 1)
 	if actual != expected {
@@ -348,3 +375,26 @@ func compareJSONStrings(json1 string, json2 string) bool {
 		return false
 	}
 }
+
+func TestGetPointerEscapesKeys(t *testing.T) {
+	nl, _ := nodelist.NewNodeList([]byte(`{"items": [{"metadata": {"annotations": {"kubernetes.io/a~b": "x"}}}]}`), true)
+	expected := "/items/0/metadata/annotations/kubernetes.io~1a~0b"
+	actual := nl.GetPointer(5)
+	if actual != expected {
+		t.Errorf("Expected '%s' but got '%s'", expected, actual)
+	}
+}
+
+func TestColourIsOnlyShownInColouredOutput(t *testing.T) {
+	nl, _ := nodelist.NewNodeList([]byte(`{"a": 1}`), true)
+	nl.SetColour([]int{1}, nodelist.GREEN)
+	if actual := nl.GetNodes(2); actual != "Root\n└──\033[32ma\033[0m" {
+		t.Errorf("Expected a in green but got %q", actual)
+	}
+	if actual := nl.GetColouredJSON(-1); actual != "{\n    \033[32m\"a\": 1\033[0m\n}" {
+		t.Errorf("Expected a in green but got %q", actual)
+	}
+	if actual := nl.GetJSON(-1); actual != "{\n    \"a\": 1\n}" {
+		t.Errorf("Expected no colour but got %q", actual)
+	}
+}
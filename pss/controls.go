@@ -0,0 +1,285 @@
+package pss
+
+import (
+	"fmt"
+	"kube-review/k8s"
+	"strings"
+)
+
+var controls = []Control{
+	{"hostProcess", "HostProcess", BASELINE, "", checkHostProcess},
+	{"hostNamespaces", "Host Namespaces", BASELINE, "", checkHostNamespaces},
+	{"privileged", "Privileged Containers", BASELINE, "", checkPrivileged},
+	{"capabilities_baseline", "Capabilities", BASELINE, "", checkBaselineCapabilities},
+	{"hostPathVolumes", "HostPath Volumes", BASELINE, "", checkHostPathVolumes},
+	{"hostPorts", "Host Ports", BASELINE, "", checkHostPorts},
+	{"appArmorProfile", "AppArmor", BASELINE, "", checkAppArmor},
+	{"seLinuxOptions", "SELinux", BASELINE, "", checkSELinux},
+	{"procMount", "/proc Mount Type", BASELINE, "", checkProcMount},
+	{"seccompProfile_baseline", "Seccomp", BASELINE, "", checkBaselineSeccomp},
+	{"sysctls", "Sysctls", BASELINE, "", checkSysctls},
+	{"restrictedVolumes", "Volume Types", RESTRICTED, "", checkVolumeTypes},
+	{"allowPrivilegeEscalation", "Privilege Escalation", RESTRICTED, "", checkPrivilegeEscalation},
+	{"runAsNonRoot", "Running as Non-root", RESTRICTED, "", checkRunAsNonRoot},
+	{"runAsUser", "Running as Non-root user", RESTRICTED, "", checkRunAsUser},
+	{"seccompProfile_restricted", "Seccomp", RESTRICTED, "seccompProfile_baseline", checkRestrictedSeccomp},
+	{"capabilities_restricted", "Capabilities", RESTRICTED, "capabilities_baseline", checkRestrictedCapabilities},
+}
+
+var (
+	baselineCapabilities = []string{"AUDIT_WRITE", "CHOWN", "DAC_OVERRIDE", "FOWNER", "FSETID", "KILL", "MKNOD",
+		"NET_BIND_SERVICE", "SETFCAP", "SETGID", "SETPCAP", "SETUID", "SYS_CHROOT"}
+	seLinuxTypes = []string{"", "container_t", "container_init_t", "container_kvm_t", "container_engine_t"}
+	safeSysctls  = []string{"kernel.shm_rmid_forced", "net.ipv4.ip_local_port_range", "net.ipv4.ip_unprivileged_port_start",
+		"net.ipv4.tcp_syncookies", "net.ipv4.ping_group_range", "net.ipv4.ip_local_reserved_ports",
+		"net.ipv4.tcp_keepalive_time", "net.ipv4.tcp_fin_timeout", "net.ipv4.tcp_keepalive_intvl",
+		"net.ipv4.tcp_keepalive_probes"}
+	restrictedVolumeTypes = []string{"configMap", "csi", "downwardAPI", "emptyDir", "ephemeral",
+		"persistentVolumeClaim", "projected", "secret"}
+)
+
+// appArmorAnnotation is the prefix of the annotations that set each container's AppArmor profile
+const appArmorAnnotation = "container.apparmor.security.beta.kubernetes.io/"
+
+// pod is a pod spec being evaluated along with its containers
+type pod struct {
+	nodeList   k8s.NodeList
+	template   k8s.PodTemplate
+	containers []int
+}
+
+// finding is a node that breaks a control
+type finding struct {
+	index   int
+	message string
+}
+
+// contexts returns the pod spec followed by each container, which can all have a securityContext
+func (p pod) contexts() []int {
+	return append([]int{p.template.Spec}, p.containers...)
+}
+
+// matching returns a finding for each parent with a value at keys for which bad is true
+func (p pod) matching(parents []int, keys []string, bad func(string) bool) []finding {
+	var found []finding
+	for _, parent := range parents {
+		if index, ok := p.nodeList.GetChild(parent, keys...); ok {
+			if value := p.nodeList.GetValue(index); bad(value) {
+				found = append(found, finding{index, fmt.Sprintf("%s is %s", strings.Join(keys, "."), value)})
+			}
+		}
+	}
+	return found
+}
+
+// each returns the children of the array at keys below parent
+func (p pod) each(parent int, keys ...string) []int {
+	if index, ok := p.nodeList.GetChild(parent, keys...); ok {
+		return p.nodeList.GetChildren(index)
+	}
+	return []int{}
+}
+
+// nearest returns the deepest node that exists along keys below parent
+func (p pod) nearest(parent int, keys ...string) int {
+	for _, key := range keys {
+		index, ok := p.nodeList.GetChild(parent, key)
+		if !ok {
+			break
+		}
+		parent = index
+	}
+	return parent
+}
+
+func checkHostProcess(p pod) []finding {
+	return p.matching(p.contexts(), []string{"securityContext", "windowsOptions", "hostProcess"}, isTrue)
+}
+
+func checkHostNamespaces(p pod) []finding {
+	var found []finding
+	for _, key := range []string{"hostNetwork", "hostPID", "hostIPC"} {
+		found = append(found, p.matching([]int{p.template.Spec}, []string{key}, isTrue)...)
+	}
+	return found
+}
+
+func checkPrivileged(p pod) []finding {
+	return p.matching(p.containers, []string{"securityContext", "privileged"}, isTrue)
+}
+
+func checkBaselineCapabilities(p pod) []finding {
+	return p.addedCapabilities(baselineCapabilities)
+}
+
+// addedCapabilities returns the capabilities added to containers that are not in allowed
+func (p pod) addedCapabilities(allowed []string) []finding {
+	var found []finding
+	for _, container := range p.containers {
+		for _, capability := range p.each(container, "securityContext", "capabilities", "add") {
+			if name := p.nodeList.GetValue(capability); !contains(allowed, name) {
+				found = append(found, finding{capability, fmt.Sprintf("capability %s is added", name)})
+			}
+		}
+	}
+	return found
+}
+
+func checkHostPathVolumes(p pod) []finding {
+	var found []finding
+	for _, volume := range p.each(p.template.Spec, "volumes") {
+		if index, ok := p.nodeList.GetChild(volume, "hostPath"); ok {
+			found = append(found, finding{index, fmt.Sprintf("volume %s is a hostPath", p.nodeList.GetChildValue(volume, "name"))})
+		}
+	}
+	return found
+}
+
+func checkHostPorts(p pod) []finding {
+	var found []finding
+	for _, container := range p.containers {
+		found = append(found, p.matching(p.each(container, "ports"), []string{"hostPort"}, func(value string) bool {
+			return value != "0"
+		})...)
+	}
+	return found
+}
+
+func checkAppArmor(p pod) []finding {
+	var found []finding
+	if p.template.Metadata >= 0 {
+		for _, annotation := range p.each(p.template.Metadata, "annotations") {
+			value := p.nodeList.GetValue(annotation)
+			if strings.HasPrefix(p.nodeList.GetKey(annotation), appArmorAnnotation) &&
+				value != "runtime/default" && !strings.HasPrefix(value, "localhost/") {
+				found = append(found, finding{annotation, fmt.Sprintf("%s is %s", p.nodeList.GetKey(annotation), value)})
+			}
+		}
+	}
+	return append(found, p.matching(p.contexts(), []string{"securityContext", "appArmorProfile", "type"}, func(value string) bool {
+		return value != "RuntimeDefault" && value != "Localhost"
+	})...)
+}
+
+func checkSELinux(p pod) []finding {
+	found := p.matching(p.contexts(), []string{"securityContext", "seLinuxOptions", "type"}, func(value string) bool {
+		return !contains(seLinuxTypes, value)
+	})
+	for _, key := range []string{"user", "role"} {
+		found = append(found, p.matching(p.contexts(), []string{"securityContext", "seLinuxOptions", key}, func(value string) bool {
+			return value != ""
+		})...)
+	}
+	return found
+}
+
+func checkProcMount(p pod) []finding {
+	return p.matching(p.containers, []string{"securityContext", "procMount"}, func(value string) bool {
+		return value != "Default"
+	})
+}
+
+func checkBaselineSeccomp(p pod) []finding {
+	return p.matching(p.contexts(), []string{"securityContext", "seccompProfile", "type"}, func(value string) bool {
+		return value == "Unconfined"
+	})
+}
+
+func checkSysctls(p pod) []finding {
+	var found []finding
+	for _, sysctl := range p.each(p.template.Spec, "securityContext", "sysctls") {
+		if name := p.nodeList.GetChildValue(sysctl, "name"); !contains(safeSysctls, name) {
+			found = append(found, finding{sysctl, fmt.Sprintf("sysctl %s is not safe", name)})
+		}
+	}
+	return found
+}
+
+func checkVolumeTypes(p pod) []finding {
+	var found []finding
+	for _, volume := range p.each(p.template.Spec, "volumes") {
+		for _, child := range p.nodeList.GetChildren(volume) {
+			if key := p.nodeList.GetKey(child); key != "name" && !contains(restrictedVolumeTypes, key) {
+				found = append(found, finding{child, fmt.Sprintf("volume %s is of type %s",
+					p.nodeList.GetChildValue(volume, "name"), key)})
+			}
+		}
+	}
+	return found
+}
+
+func checkPrivilegeEscalation(p pod) []finding {
+	var found []finding
+	for _, container := range p.containers {
+		index, ok := p.nodeList.GetChild(container, "securityContext", "allowPrivilegeEscalation")
+		if !ok {
+			found = append(found, finding{p.nearest(container, "securityContext"), "securityContext.allowPrivilegeEscalation is not set to false"})
+		} else if value := p.nodeList.GetValue(index); value != "false" {
+			found = append(found, finding{index, "securityContext.allowPrivilegeEscalation is " + value})
+		}
+	}
+	return found
+}
+
+func checkRunAsNonRoot(p pod) []finding {
+	found := p.matching(p.contexts(), []string{"securityContext", "runAsNonRoot"}, func(value string) bool {
+		return value != "true"
+	})
+	if p.nodeList.GetChildValue(p.template.Spec, "securityContext", "runAsNonRoot") != "true" {
+		for _, container := range p.containers {
+			if _, ok := p.nodeList.GetChild(container, "securityContext", "runAsNonRoot"); !ok {
+				found = append(found, finding{p.nearest(container, "securityContext"),
+					"securityContext.runAsNonRoot is not set to true for the pod or container"})
+			}
+		}
+	}
+	return found
+}
+
+func checkRunAsUser(p pod) []finding {
+	return p.matching(p.contexts(), []string{"securityContext", "runAsUser"}, func(value string) bool {
+		return value == "0"
+	})
+}
+
+func checkRestrictedSeccomp(p pod) []finding {
+	invalid := func(value string) bool { return value != "RuntimeDefault" && value != "Localhost" }
+	found := p.matching(p.contexts(), []string{"securityContext", "seccompProfile", "type"}, invalid)
+	if _, ok := p.nodeList.GetChild(p.template.Spec, "securityContext", "seccompProfile", "type"); !ok {
+		for _, container := range p.containers {
+			if _, ok := p.nodeList.GetChild(container, "securityContext", "seccompProfile", "type"); !ok {
+				found = append(found, finding{p.nearest(container, "securityContext"),
+					"securityContext.seccompProfile.type is not set for the pod or container"})
+			}
+		}
+	}
+	return found
+}
+
+func checkRestrictedCapabilities(p pod) []finding {
+	var found []finding
+	for _, container := range p.containers {
+		dropsAll := false
+		for _, capability := range p.each(container, "securityContext", "capabilities", "drop") {
+			dropsAll = dropsAll || p.nodeList.GetValue(capability) == "ALL"
+		}
+		if !dropsAll {
+			found = append(found, finding{p.nearest(container, "securityContext", "capabilities", "drop"),
+				"securityContext.capabilities.drop does not include ALL"})
+		}
+	}
+	return append(found, p.addedCapabilities([]string{"NET_BIND_SERVICE"})...)
+}
+
+func isTrue(value string) bool {
+	return value == "true"
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
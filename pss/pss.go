@@ -0,0 +1,146 @@
+package pss
+
+import (
+	"fmt"
+	"kube-review/k8s"
+	"kube-review/nodelist"
+	"strings"
+)
+
+// ProfileEnum lists the Pod Security Standards profiles that can be evaluated
+type ProfileEnum int
+
+const (
+	// BASELINE a
+	BASELINE ProfileEnum = iota
+	// RESTRICTED a
+	RESTRICTED
+)
+
+var profileNames = [...]string{"baseline", "restricted"}
+
+func (pe ProfileEnum) String() string {
+	return profileNames[pe]
+}
+
+// ParseProfile returns the profile matching name, ignoring case
+func ParseProfile(name string) (ProfileEnum, error) {
+	for index, profile := range profileNames {
+		if strings.EqualFold(strings.TrimSpace(name), profile) {
+			return ProfileEnum(index), nil
+		}
+	}
+	return BASELINE, fmt.Errorf("Invalid profile '%s'. Must be baseline or restricted", name)
+}
+
+// Control is a single check of a profile, identified as in the Pod Security admission controller,
+// e.g. hostNamespaces. A restricted control may replace a baseline one of the same name
+type Control struct {
+	ID        string
+	Title     string
+	Profile   ProfileEnum
+	overrides string
+	check     func(p pod) []finding
+}
+
+// Violation is a field of a pod spec that breaks a control. Index is the offending node or, if
+// a required field is missing, the nearest node above where it should be
+type Violation struct {
+	Control  string
+	Title    string
+	Resource nodelist.ResourceInfo
+	Index    int
+	Path     string
+	Message  string
+}
+
+// Controls returns the controls that apply at profile, which for restricted includes baseline
+func Controls(profile ProfileEnum) []Control {
+	overridden := map[string]bool{}
+	for _, control := range controls {
+		if control.Profile <= profile && control.overrides != "" {
+			overridden[control.overrides] = true
+		}
+	}
+	var applied []Control
+	for _, control := range controls {
+		if control.Profile <= profile && !overridden[control.ID] {
+			applied = append(applied, control)
+		}
+	}
+	return applied
+}
+
+// ParseQuery reads a query of the form "profile" or "profile:control,control", which limits the
+// evaluation to the controls given
+func ParseQuery(query string) (ProfileEnum, []string, error) {
+	parts := strings.SplitN(query, ":", 2)
+	profile, err := ParseProfile(parts[0])
+	if err != nil {
+		return profile, nil, err
+	}
+	var ids []string
+	if len(parts) == 2 {
+		for _, id := range strings.Split(parts[1], ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				ids = append(ids, id)
+			}
+		}
+	}
+	_, err = selectControls(profile, ids)
+	return profile, ids, err
+}
+
+// Evaluate checks the pod spec of every Pod, and the pod template of every workload, in the items
+// array of nodeList's current view against the controls of profile. If ids are given only those
+// controls are checked
+func Evaluate(nodeList k8s.NodeList, profile ProfileEnum, ids []string) ([]Violation, error) {
+	selected, err := selectControls(profile, ids)
+	if err != nil {
+		return nil, err
+	}
+	var violations []Violation
+	for _, template := range k8s.NewIndex(nodeList).PodTemplates(nodeList) {
+		p := pod{nodeList, template, k8s.GetContainers(nodeList, template.Spec)}
+		for _, control := range selected {
+			for _, f := range control.check(p) {
+				violations = append(violations, Violation{control.ID, control.Title, template.ResourceInfo,
+					f.index, nodeList.GetPath(f.index), f.message})
+			}
+		}
+	}
+	return violations, nil
+}
+
+// EvaluateQuery evaluates a query read by ParseQuery
+func EvaluateQuery(nodeList k8s.NodeList, query string) ([]Violation, error) {
+	profile, ids, err := ParseQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	return Evaluate(nodeList, profile, ids)
+}
+
+/////////////////////////////////////////////////////////////////////////////////
+// PRIVATE FUNCTIONS
+
+func selectControls(profile ProfileEnum, ids []string) ([]Control, error) {
+	applied := Controls(profile)
+	if len(ids) == 0 {
+		return applied, nil
+	}
+	var selected []Control
+	for _, id := range ids {
+		found := false
+		for _, control := range applied {
+			if strings.EqualFold(control.ID, id) {
+				selected = append(selected, control)
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("Invalid control '%s' for the %s profile", id, profile)
+		}
+	}
+	return selected, nil
+}
@@ -0,0 +1,83 @@
+package pss_test
+
+import (
+	"io/ioutil"
+	"kube-review/nodelist"
+	"kube-review/pss"
+	"reflect"
+	"testing"
+)
+
+func getNodeList(t *testing.T, jsonData string) nodelist.NodeList {
+	nodeList, err := nodelist.NewNodeList([]byte(jsonData), true)
+	if err != nil {
+		t.Fatalf("Failed to parse test data - %s", err.Error())
+	}
+	return nodeList
+}
+
+func getControls(violations []pss.Violation) []string {
+	var controls []string
+	for _, violation := range violations {
+		controls = append(controls, violation.Control+" "+violation.Path)
+	}
+	return controls
+}
+
+func TestEvaluateFindsPodTemplatesOfEachKind(t *testing.T) {
+	rawJSON, err := ioutil.ReadFile("../testdata/querytests/fixtures/workloads.json")
+	if err != nil {
+		t.Fatalf("Failed to read fixture - %s", err.Error())
+	}
+	nodeList := getNodeList(t, string(rawJSON))
+	violations, err := pss.Evaluate(&nodeList, pss.BASELINE, nil)
+	if err != nil {
+		t.Fatalf("Expected no error but got '%s'", err.Error())
+	}
+	var actual []string
+	for _, violation := range violations {
+		actual = append(actual, violation.Resource.String())
+	}
+	expected := []string{"Deployment prod/web", "Deployment prod/web", "Deployment prod/web", "Deployment prod/web",
+		"CronJob ops/backup", "CronJob ops/backup"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected %v but got %v", expected, actual)
+	}
+}
+
+func TestRestrictedControlsReplaceBaselineOnes(t *testing.T) {
+	nodeList := getNodeList(t, `{"kind": "Pod", "metadata": {"name": "a"}, "spec": {
+		"securityContext": {"runAsNonRoot": true, "seccompProfile": {"type": "Unconfined"}},
+		"containers": [{"name": "a", "securityContext": {"allowPrivilegeEscalation": false,
+			"capabilities": {"drop": ["ALL"], "add": ["NET_BIND_SERVICE", "NET_RAW"]}}}]}}`)
+	violations, _ := pss.Evaluate(&nodeList, pss.RESTRICTED, nil)
+	actual := getControls(violations)
+	expected := []string{"seccompProfile_restricted spec.securityContext.seccompProfile.type",
+		"capabilities_restricted spec.containers[0].securityContext.capabilities.add[1]"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected %v but got %v", expected, actual)
+	}
+}
+
+func TestMissingFieldsPointAtNearestNode(t *testing.T) {
+	nodeList := getNodeList(t, `{"kind": "Pod", "metadata": {"name": "a"}, "spec": {
+		"containers": [{"name": "a"}, {"name": "b", "securityContext": {"runAsNonRoot": false}}]}}`)
+	violations, _ := pss.Evaluate(&nodeList, pss.RESTRICTED, []string{"runAsNonRoot"})
+	actual := getControls(violations)
+	expected := []string{"runAsNonRoot spec.containers[1].securityContext.runAsNonRoot", "runAsNonRoot spec.containers[0]"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected %v but got %v", expected, actual)
+	}
+}
+
+func TestParseQueryReadsProfileAndControls(t *testing.T) {
+	profile, ids, err := pss.ParseQuery("Restricted: runAsUser, privileged")
+	if err != nil || profile != pss.RESTRICTED || !reflect.DeepEqual(ids, []string{"runAsUser", "privileged"}) {
+		t.Errorf("Expected restricted [runAsUser privileged] but got %s %v (%v)", profile, ids, err)
+	}
+	for _, query := range []string{"privileged", "baseline:runAsUser", "baseline:unknown"} {
+		if _, _, err := pss.ParseQuery(query); err == nil {
+			t.Errorf("Expected an error for '%s' but got none", query)
+		}
+	}
+}
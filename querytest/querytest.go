@@ -0,0 +1,230 @@
+package querytest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"kube-review/nodelist"
+	"kube-review/pss"
+	"kube-review/search"
+	"kube-review/utils"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// TestFile holds the fixtures for a single query. Query may include arguments, e.g. "name:param=value"
+type TestFile struct {
+	Query   string     `json:"query"`
+	Match   []TestCase `json:"match"`
+	NoMatch []TestCase `json:"noMatch"`
+	dir     string
+}
+
+// TestCase is a manifest that the query is run against. The manifest is either given inline or
+// as a File relative to the test file. Manifests that are a single resource rather than a
+// list are wrapped in a List, so their paths start at items[0]. If Paths is set then the
+// query must match exactly those node paths
+type TestCase struct {
+	Name     string          `json:"name"`
+	Manifest json.RawMessage `json:"manifest,omitempty"`
+	File     string          `json:"file,omitempty"`
+	Paths    []string        `json:"paths,omitempty"`
+}
+
+// Result is the outcome of a single check
+type Result struct {
+	Query   string
+	Case    string
+	Passed  bool
+	Message string
+}
+
+func (r Result) String() string {
+	status := "PASS"
+	if !r.Passed {
+		status = "FAIL"
+	}
+	out := status + ": " + r.Query
+	if r.Case != "" {
+		out += " - " + r.Case
+	}
+	if r.Message != "" {
+		out += " (" + r.Message + ")"
+	}
+	return out
+}
+
+// Load reads a test file
+func Load(file string) (TestFile, error) {
+	var testFile TestFile
+	if err := utils.LoadJSON(file, &testFile, ""); err != nil {
+		return TestFile{}, fmt.Errorf("'%s' - %s", file, err.Error())
+	}
+	if testFile.Query == "" {
+		return TestFile{}, fmt.Errorf("'%s' - no query defined", file)
+	}
+	testFile.dir = filepath.Dir(file)
+	return testFile, nil
+}
+
+// LoadAll reads test files from each path, which can either be a file or a directory of json files
+func LoadAll(paths ...string) ([]TestFile, error) {
+	var testFiles []TestFile
+	for _, path := range paths {
+		files := []string{path}
+		if info, err := os.Stat(path); err != nil {
+			return nil, err
+		} else if info.IsDir() {
+			if files, err = filepath.Glob(filepath.Join(path, "*.json")); err != nil {
+				return nil, err
+			}
+		}
+		for _, file := range files {
+			testFile, err := Load(file)
+			if err != nil {
+				return nil, err
+			}
+			testFiles = append(testFiles, testFile)
+		}
+	}
+	return testFiles, nil
+}
+
+// Run checks the query against every fixture in the test file
+func (tf TestFile) Run(ql *search.QueryList) []Result {
+	var results []Result
+	for _, testCase := range tf.Match {
+		results = append(results, tf.runCase(ql, testCase, true))
+	}
+	for _, testCase := range tf.NoMatch {
+		results = append(results, tf.runCase(ql, testCase, false))
+	}
+	return results
+}
+
+// RunAll runs every test file, after checking all queries in ql can be compiled
+func RunAll(ql *search.QueryList, testFiles []TestFile) []Result {
+	results := CheckQueries(ql)
+	for _, testFile := range testFiles {
+		results = append(results, testFile.Run(ql)...)
+	}
+	return results
+}
+
+// CheckQueries makes sure every query in ql is a valid regex or expression. Parameters
+// without a default are given a placeholder value of the right type
+func CheckQueries(ql *search.QueryList) []Result {
+	var results []Result
+	for _, name := range ql.GetNames() {
+		data, _ := ql.Get(name)
+		args := map[string]string{}
+		for _, param := range data.Parameters {
+			if param.Default == "" {
+				args[param.Name] = placeholders[param.Type]
+			}
+		}
+		result := Result{name, "compiles", true, ""}
+		if err := compile(data, args); err != nil {
+			result.Passed, result.Message = false, err.Error()
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// Failed returns only the results that did not pass
+func Failed(results []Result) []Result {
+	var failed []Result
+	for _, result := range results {
+		if !result.Passed {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
+
+var placeholders = map[string]string{"string": "a", "regex": "a", "int": "0", "bool": "true", "date": "now"}
+
+func compile(data search.QueryData, args map[string]string) error {
+	query, err := data.Substitute(args)
+	if err != nil {
+		return err
+	}
+	if data.QueryType == search.EXPRESSION {
+		_, err = search.NewExpression(query)
+	} else if data.QueryType == search.PODSECURITY {
+		_, _, err = pss.ParseQuery(query)
+	} else {
+		_, err = regexp.Compile(query)
+	}
+	return err
+}
+
+func (tf TestFile) runCase(ql *search.QueryList, testCase TestCase, shouldMatch bool) Result {
+	result := Result{tf.Query, testCase.Name, false, ""}
+	manifest, err := tf.getManifest(testCase)
+	if err != nil {
+		result.Message = err.Error()
+		return result
+	}
+	nodeList, err := nodelist.NewNodeList(manifest, true)
+	if err != nil {
+		result.Message = "could not parse manifest - " + err.Error()
+		return result
+	}
+
+	matches, err := search.NewSearch(search.QUERY, ql).GetMatchedNodes(tf.Query, &nodeList)
+	if err != nil {
+		result.Message = err.Error()
+		return result
+	}
+	var paths []string
+	for _, index := range matches {
+		paths = append(paths, nodeList.GetPath(index))
+	}
+	sort.Strings(paths)
+
+	if !shouldMatch {
+		result.Passed = len(paths) == 0
+		if !result.Passed {
+			result.Message = "expected no matches but matched " + strings.Join(paths, ", ")
+		}
+	} else if len(paths) == 0 {
+		result.Message = "expected matches but got none"
+	} else if expected := sortedCopy(testCase.Paths); len(expected) > 0 && strings.Join(expected, ",") != strings.Join(paths, ",") {
+		result.Message = fmt.Sprintf("expected paths %v but matched %v", expected, paths)
+	} else {
+		result.Passed = true
+	}
+	return result
+}
+
+func (tf TestFile) getManifest(testCase TestCase) ([]byte, error) {
+	manifest := []byte(testCase.Manifest)
+	if testCase.File != "" {
+		var err error
+		if manifest, err = ioutil.ReadFile(filepath.Join(tf.dir, testCase.File)); err != nil {
+			return nil, err
+		}
+	}
+	if len(manifest) == 0 {
+		return nil, fmt.Errorf("no manifest or file given")
+	}
+
+	var resource map[string]interface{}
+	if err := json.Unmarshal(manifest, &resource); err == nil {
+		if _, ok := resource["items"]; !ok {
+			return json.Marshal(map[string]interface{}{"apiVersion": "v1", "kind": "List", "items": []interface{}{resource}})
+		}
+	}
+	return manifest, nil
+}
+
+func sortedCopy(list []string) []string {
+	sorted := append([]string{}, list...)
+	sort.Strings(sorted)
+	return sorted
+}
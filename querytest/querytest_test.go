@@ -0,0 +1,75 @@
+package querytest_test
+
+import (
+	"kube-review/querytest"
+	"kube-review/search"
+	"strings"
+	"testing"
+)
+
+func getDefaultQueryList() *search.QueryList {
+	ql := search.NewQueryList()
+	ql.LoadDefaults()
+	return &ql
+}
+
+// check runs the query tests found in paths against ql, along with checking every query
+// compiles, and reports each failure as a test error
+func check(t *testing.T, ql *search.QueryList, paths ...string) {
+	t.Helper()
+	testFiles, err := querytest.LoadAll(paths...)
+	if err != nil {
+		t.Fatalf("Failed to load query tests - %s", err.Error())
+	}
+	for _, result := range querytest.Failed(querytest.RunAll(ql, testFiles)) {
+		t.Errorf("%s", result)
+	}
+}
+
+func TestDefaultQueriesPassTheirTests(t *testing.T) {
+	check(t, getDefaultQueryList(), "../testdata/querytests")
+}
+
+func TestCheckQueriesFailsForInvalidRegex(t *testing.T) {
+	ql := search.NewQueryList()
+	ql.Add("broken", "[a-z", "Broken regex", search.REGEX)
+	actual := querytest.Failed(querytest.CheckQueries(&ql))
+	if len(actual) != 1 || actual[0].Query != "broken" {
+		t.Errorf("Expected 'broken' to fail but got %v", actual)
+	}
+}
+
+func TestCheckQueriesUsesPlaceholdersForParameters(t *testing.T) {
+	ql := search.NewQueryList()
+	ql.LoadPackDir("../testdata/querypacks")
+	if actual := querytest.Failed(querytest.CheckQueries(&ql)); len(actual) != 0 {
+		t.Errorf("Expected no failures but got %v", actual)
+	}
+}
+
+func TestRunReportsUnexpectedMatches(t *testing.T) {
+	testFile, err := querytest.Load("../testdata/querytests/secrets-in-configmap.json")
+	if err != nil {
+		t.Fatalf("Expected no error but got '%s'", err.Error())
+	}
+	testFile.NoMatch = append(testFile.NoMatch, testFile.Match...)
+	actual := querytest.Failed(testFile.Run(getDefaultQueryList()))
+	if len(actual) != 1 || !strings.Contains(actual[0].Message, "expected no matches") {
+		t.Errorf("Expected one unexpected match but got %v", actual)
+	}
+}
+
+func TestRunReportsIncorrectPaths(t *testing.T) {
+	testFile, _ := querytest.Load("../testdata/querytests/secrets-in-configmap.json")
+	testFile.Match[0].Paths = []string{"items[0].metadata.name"}
+	actual := querytest.Failed(testFile.Run(getDefaultQueryList()))
+	if len(actual) != 1 || !strings.Contains(actual[0].Message, "expected paths") {
+		t.Errorf("Expected incorrect paths to fail but got %v", actual)
+	}
+}
+
+func TestLoadReturnsErrorWithoutQuery(t *testing.T) {
+	if _, err := querytest.Load("../testdata/querylist-test.json"); err == nil {
+		t.Errorf("Expected an error but got nothing")
+	}
+}
@@ -0,0 +1,97 @@
+package rbac
+
+import "kube-review/utils"
+
+// Check is a known way that a permission can be used to escalate privileges
+type Check struct {
+	Name        string
+	Description string
+	matches     func(Permission) bool
+}
+
+var checks = []Check{
+	{"wildcard", "rules with a wildcard verb or resource", func(p Permission) bool {
+		return contains(p.Rule.Verbs, "*") || contains(p.Rule.Resources, "*")
+	}},
+	{"escalate-bind", "escalate or bind on roles, which allows granting permissions not already held", func(p Permission) bool {
+		return allowsAny(p.Rule, []string{"escalate", "bind"},
+			[]string{"roles.rbac.authorization.k8s.io", "clusterroles.rbac.authorization.k8s.io"})
+	}},
+	{"impersonate", "impersonating users, groups or service accounts", func(p Permission) bool {
+		return allowsAny(p.Rule, []string{"impersonate"}, []string{"users", "groups", "serviceaccounts"})
+	}},
+	{"pods-exec", "create on pods/exec, which runs commands in any container", func(p Permission) bool {
+		return p.Rule.Allows("create", "pods/exec")
+	}},
+	{"secrets-cluster-wide", "reading secrets in every namespace", func(p Permission) bool {
+		return p.Namespace == AllNamespaces && allowsAny(p.Rule, []string{"get", "list", "watch"}, []string{"secrets"})
+	}},
+	{"workload-create", "creating pods or workloads, which can mount the namespace's secrets and service accounts", func(p Permission) bool {
+		return allowsAny(p.Rule, []string{"create"}, []string{"pods", "deployments.apps", "daemonsets.apps",
+			"statefulsets.apps", "replicasets.apps", "jobs.batch", "cronjobs.batch", "replicationcontrollers"})
+	}},
+	{"nodes-proxy", "access to nodes/proxy, which reaches the kubelet API", func(p Permission) bool {
+		return p.Namespace == AllNamespaces && allowsAny(p.Rule, []string{"get", "create"}, []string{"nodes/proxy"})
+	}},
+	{"csr-approval", "approving certificate signing requests, which can issue client certificates", func(p Permission) bool {
+		return p.Namespace == AllNamespaces &&
+			p.Rule.Allows("update", "certificatesigningrequests.certificates.k8s.io/approval")
+	}},
+	{"anonymous", "anything granted to system:anonymous or system:unauthenticated", func(p Permission) bool {
+		return p.Subject == Subject{"User", "system:anonymous", ""} ||
+			p.Subject == Subject{"Group", "system:unauthenticated", ""}
+	}},
+}
+
+// Checks returns every built in check
+func Checks() []Check {
+	return checks
+}
+
+// GetCheck returns the check called name
+func GetCheck(name string) (Check, error) {
+	var names []string
+	for _, check := range checks {
+		names = append(names, check.Name)
+	}
+	index, err := utils.FindName("RBAC check", name, names)
+	if err != nil {
+		return Check{}, err
+	}
+	return checks[index], nil
+}
+
+// Detect returns the permissions that check flags in a namespace fully matching the namespace
+// regex. An empty regex matches any namespace and cluster wide permissions match every regex
+func (r RBAC) Detect(name, namespace string) ([]Permission, error) {
+	check, err := GetCheck(name)
+	if err != nil {
+		return nil, err
+	}
+	namespaceRegex, err := utils.CompileFullMatch(namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	var permissions []Permission
+	for _, permission := range r.permissions {
+		if namespaceRegex != nil && permission.Namespace != AllNamespaces && !namespaceRegex.MatchString(permission.Namespace) {
+			continue
+		}
+		if check.matches(permission) {
+			permissions = append(permissions, permission)
+		}
+	}
+	return permissions, nil
+}
+
+func allowsAny(rule Rule, verbs, resources []string) bool {
+	for _, verb := range verbs {
+		for _, resource := range resources {
+			if rule.Allows(verb, resource) {
+				return true
+			}
+		}
+	}
+	return false
+}
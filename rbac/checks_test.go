@@ -0,0 +1,39 @@
+package rbac_test
+
+import (
+	"kube-review/nodelist"
+	"kube-review/rbac"
+	"reflect"
+	"testing"
+)
+
+func TestDetectFiltersNamespacesButKeepsClusterWidePermissions(t *testing.T) {
+	permissions, err := getRBAC(t).Detect("workload-create", "kube-.*")
+	if err != nil {
+		t.Fatalf("Expected no error but got '%s'", err.Error())
+	}
+	actual := getSubjects(permissions)
+	expected := []string{"Group:system:masters *"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected %v but got %v", expected, actual)
+	}
+}
+
+func TestDetectPointsAtRuleAndSubjectNodes(t *testing.T) {
+	nodeList, _ := nodelist.NewNodeList([]byte(testJSON), true)
+	permissions, _ := rbac.New(&nodeList).Detect("pods-exec", "")
+	var actual []string
+	for _, permission := range permissions {
+		actual = append(actual, nodeList.GetPath(permission.RuleIndex)+" "+nodeList.GetPath(permission.SubjectIndex))
+	}
+	expected := []string{"items[0].rules[0] items[5].subjects[0]", "items[0].rules[0] items[8].subjects[0]"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected %v but got %v", expected, actual)
+	}
+}
+
+func TestDetectRejectsUnknownChecks(t *testing.T) {
+	if _, err := getRBAC(t).Detect("unknown", ""); err == nil {
+		t.Errorf("Expected error but got nil")
+	}
+}
@@ -0,0 +1,326 @@
+package rbac
+
+import (
+	"fmt"
+	"kube-review/k8s"
+	"kube-review/nodelist"
+	"sort"
+	"strings"
+)
+
+// AllNamespaces is the namespace of a permission granted by a ClusterRoleBinding
+const AllNamespaces = "*"
+
+// Subject is a user, group or service account that a binding grants a role to
+type Subject struct {
+	Kind      string
+	Name      string
+	Namespace string
+}
+
+var subjectKinds = []string{"User", "Group", "ServiceAccount"}
+
+// ParseSubject reads a subject written as User:NAME, Group:NAME or ServiceAccount:NAMESPACE/NAME
+func ParseSubject(subject string) (Subject, error) {
+	parts := strings.SplitN(subject, ":", 2)
+	if len(parts) == 2 && parts[1] != "" {
+		for _, kind := range subjectKinds {
+			if !strings.EqualFold(parts[0], kind) {
+				continue
+			}
+			if kind != "ServiceAccount" {
+				return Subject{kind, parts[1], ""}, nil
+			}
+			if names := strings.SplitN(parts[1], "/", 2); len(names) == 2 && names[0] != "" && names[1] != "" {
+				return Subject{kind, names[1], names[0]}, nil
+			}
+		}
+	}
+	return Subject{}, fmt.Errorf("Invalid subject '%s'. Must be User:NAME, Group:NAME or ServiceAccount:NAMESPACE/NAME", subject)
+}
+
+// String returns the subject in the form read by ParseSubject
+func (s Subject) String() string {
+	if s.Kind == "ServiceAccount" {
+		return s.Kind + ":" + s.Namespace + "/" + s.Name
+	}
+	return s.Kind + ":" + s.Name
+}
+
+// Permission is a rule granted to a subject by a binding. Namespace is where the rule applies,
+// or AllNamespaces if granted cluster wide. SubjectIndex is the subject's node in the binding and
+// RuleIndex the rule's node, which is in another ClusterRole if the rule was aggregated
+type Permission struct {
+	Subject      Subject
+	Rule         Rule
+	Namespace    string
+	Role         nodelist.ResourceInfo
+	Binding      nodelist.ResourceInfo
+	SubjectIndex int
+	RuleIndex    int
+}
+
+// Access is the verbs a subject can use on a resource, or non-resource URL, in a namespace. If
+// ResourceNames is set, access is only to the objects named
+type Access struct {
+	Namespace     string
+	Resource      string
+	ResourceNames []string
+	Verbs         []string
+}
+
+// RBAC holds every permission granted by the bindings in a NodeList
+type RBAC struct {
+	permissions []Permission
+	unresolved  []k8s.Resource
+}
+
+// New reads the Roles, ClusterRoles, RoleBindings and ClusterRoleBindings in the items array of
+// nodeList's current view. ClusterRoles with an aggregationRule also get the rules of every
+// ClusterRole matching its selectors
+func New(nodeList k8s.NodeList) RBAC {
+	roles := map[string]role{}
+	var clusterRoles, bindings []k8s.Resource
+	for _, resource := range k8s.NewIndex(nodeList).Resources() {
+		switch resource.Kind {
+		case "Role":
+			roles[getRoleKey(resource.Kind, resource.Namespace, resource.Name)] = newRole(nodeList, resource)
+		case "ClusterRole":
+			roles[getRoleKey(resource.Kind, "", resource.Name)] = newRole(nodeList, resource)
+			clusterRoles = append(clusterRoles, resource)
+		case "RoleBinding", "ClusterRoleBinding":
+			bindings = append(bindings, resource)
+		}
+	}
+	aggregate(nodeList, roles, clusterRoles)
+
+	var r RBAC
+	for _, binding := range bindings {
+		roleKind := nodeList.GetChildValue(binding.Start, "roleRef", "kind")
+		roleNamespace, namespace := "", binding.Namespace
+		if binding.Kind == "ClusterRoleBinding" {
+			namespace = AllNamespaces
+		} else if roleKind == "Role" {
+			roleNamespace = binding.Namespace
+		}
+		role, ok := roles[getRoleKey(roleKind, roleNamespace, nodeList.GetChildValue(binding.Start, "roleRef", "name"))]
+		if !ok {
+			r.unresolved = append(r.unresolved, binding)
+			continue
+		}
+		for _, index := range getSubjectIndices(nodeList, binding.Start) {
+			subject := newSubject(nodeList, index, binding.Namespace)
+			for i, rule := range role.rules {
+				r.permissions = append(r.permissions, Permission{subject, rule, namespace, role.ResourceInfo,
+					binding.ResourceInfo, index, role.ruleIndices[i]})
+			}
+		}
+	}
+	return r
+}
+
+// Permissions returns every permission in the order the bindings appear
+func (r RBAC) Permissions() []Permission {
+	return r.permissions
+}
+
+// Unresolved returns the bindings whose role is not in the config, so their permissions are unknown
+func (r RBAC) Unresolved() []k8s.Resource {
+	return r.unresolved
+}
+
+// Subjects returns every subject that is bound to a role, sorted by kind then name
+func (r RBAC) Subjects() []Subject {
+	seen := map[Subject]bool{}
+	var subjects []Subject
+	for _, permission := range r.permissions {
+		if !seen[permission.Subject] {
+			seen[permission.Subject] = true
+			subjects = append(subjects, permission.Subject)
+		}
+	}
+	sort.Slice(subjects, func(i, j int) bool { return subjects[i].String() < subjects[j].String() })
+	return subjects
+}
+
+// WhoCan returns the permissions that allow verb on resource in namespace, where resource is
+// given as for Rule.Allows. If namespace is "" permissions in any namespace are returned
+func (r RBAC) WhoCan(verb, resource, namespace string) []Permission {
+	var permissions []Permission
+	for _, permission := range r.permissions {
+		if !permission.Rule.Allows(verb, resource) {
+			continue
+		}
+		if strings.HasPrefix(resource, "/") && permission.Namespace != AllNamespaces {
+			// Non-resource URLs are only granted by ClusterRoleBindings
+			continue
+		}
+		if namespace == "" || permission.Namespace == AllNamespaces || permission.Namespace == namespace {
+			permissions = append(permissions, permission)
+		}
+	}
+	return permissions
+}
+
+// Effective returns what subject can do in each namespace, including what is granted to the
+// groups it is implicitly a member of, e.g. system:serviceaccounts for a service account
+func (r RBAC) Effective(subject Subject) []Access {
+	subjects := append([]Subject{subject}, subject.implicit()...)
+	accesses := map[string]*Access{}
+	var keys []string
+	for _, permission := range r.permissions {
+		if !containsSubject(subjects, permission.Subject) {
+			continue
+		}
+		for _, resource := range getAccessResources(permission) {
+			key := permission.Namespace + "|" + resource + "|" + strings.Join(permission.Rule.ResourceNames, ",")
+			if _, ok := accesses[key]; !ok {
+				accesses[key] = &Access{permission.Namespace, resource, permission.Rule.ResourceNames, nil}
+				keys = append(keys, key)
+			}
+			accesses[key].Verbs = union(accesses[key].Verbs, permission.Rule.Verbs)
+		}
+	}
+
+	var effective []Access
+	for _, key := range keys {
+		effective = append(effective, *accesses[key])
+	}
+	sort.SliceStable(effective, func(i, j int) bool {
+		if effective[i].Namespace != effective[j].Namespace {
+			return effective[i].Namespace < effective[j].Namespace
+		}
+		return effective[i].Resource < effective[j].Resource
+	})
+	return effective
+}
+
+/////////////////////////////////////////////////////////////////////////////////
+// PRIVATE FUNCTIONS
+
+type role struct {
+	k8s.Resource
+	rules       []Rule
+	ruleIndices []int
+}
+
+func newRole(nodeList k8s.NodeList, resource k8s.Resource) role {
+	rules, indices := newRules(nodeList, resource.Start)
+	return role{resource, rules, indices}
+}
+
+func getRoleKey(kind, namespace, name string) string {
+	return kind + "/" + namespace + "/" + name
+}
+
+// aggregate adds the rules of every ClusterRole matched by a ClusterRole's aggregationRule to it
+func aggregate(nodeList k8s.NodeList, roles map[string]role, clusterRoles []k8s.Resource) {
+	for _, clusterRole := range clusterRoles {
+		selectors, ok := nodeList.GetChild(clusterRole.Start, "aggregationRule", "clusterRoleSelectors")
+		if !ok {
+			continue
+		}
+		key := getRoleKey("ClusterRole", "", clusterRole.Name)
+		aggregated := roles[key]
+		seen := map[string]bool{}
+		for _, rule := range aggregated.rules {
+			seen[rule.key()] = true
+		}
+		for _, selector := range nodeList.GetChildren(selectors) {
+			for _, other := range clusterRoles {
+				if other.Name == clusterRole.Name || !k8s.MatchesSelector(nodeList, selector, other.Labels) {
+					continue
+				}
+				source := roles[getRoleKey("ClusterRole", "", other.Name)]
+				for i, rule := range source.rules {
+					if !seen[rule.key()] {
+						seen[rule.key()] = true
+						aggregated.rules = append(aggregated.rules, rule)
+						aggregated.ruleIndices = append(aggregated.ruleIndices, source.ruleIndices[i])
+					}
+				}
+			}
+		}
+		roles[key] = aggregated
+	}
+}
+
+func getSubjectIndices(nodeList k8s.NodeList, index int) []int {
+	if subjects, ok := nodeList.GetChild(index, "subjects"); ok {
+		return nodeList.GetChildren(subjects)
+	}
+	return []int{}
+}
+
+// newSubject reads the subject at index. Service accounts without a namespace are in the
+// namespace of their binding
+func newSubject(nodeList k8s.NodeList, index int, bindingNamespace string) Subject {
+	subject := Subject{nodeList.GetChildValue(index, "kind"), nodeList.GetChildValue(index, "name"), ""}
+	if subject.Kind == "ServiceAccount" {
+		subject.Namespace = nodeList.GetChildValue(index, "namespace")
+		if subject.Namespace == "" {
+			subject.Namespace = bindingNamespace
+		}
+	}
+	return subject
+}
+
+// implicit returns the subjects that the API server also authorises subject as
+func (s Subject) implicit() []Subject {
+	switch s.Kind {
+	case "ServiceAccount":
+		return []Subject{
+			{"User", "system:serviceaccount:" + s.Namespace + ":" + s.Name, ""},
+			{"Group", "system:serviceaccounts", ""},
+			{"Group", "system:serviceaccounts:" + s.Namespace, ""},
+			{"Group", "system:authenticated", ""},
+		}
+	case "User":
+		if s.Name == "system:anonymous" {
+			return []Subject{{"Group", "system:unauthenticated", ""}}
+		}
+		return []Subject{{"Group", "system:authenticated", ""}}
+	}
+	return []Subject{}
+}
+
+// getAccessResources lists each resource, or non-resource URL, that permission's rule covers
+func getAccessResources(permission Permission) []string {
+	var resources []string
+	for _, group := range permission.Rule.APIGroups {
+		for _, resource := range permission.Rule.Resources {
+			resources = append(resources, FormatResource(resource, group))
+		}
+	}
+	if permission.Namespace == AllNamespaces {
+		resources = append(resources, permission.Rule.NonResourceURLs...)
+	}
+	return resources
+}
+
+func containsSubject(subjects []Subject, subject Subject) bool {
+	for _, s := range subjects {
+		if s == subject {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func union(left, right []string) []string {
+	for _, value := range right {
+		if !contains(left, value) {
+			left = append(left, value)
+		}
+	}
+	return left
+}
@@ -0,0 +1,142 @@
+package rbac_test
+
+import (
+	"kube-review/nodelist"
+	"kube-review/rbac"
+	"reflect"
+	"testing"
+)
+
+const testJSON = `{"apiVersion": "v1", "kind": "List", "items": [
+	{"kind": "ClusterRole", "metadata": {"name": "cluster-admin"},
+		"rules": [{"apiGroups": ["*"], "resources": ["*"], "verbs": ["*"]}, {"nonResourceURLs": ["*"], "verbs": ["*"]}]},
+	{"kind": "ClusterRole", "metadata": {"name": "monitoring"},
+		"aggregationRule": {"clusterRoleSelectors": [{"matchLabels": {"aggregate-to-monitoring": "true"}}]}},
+	{"kind": "ClusterRole", "metadata": {"name": "pod-reader", "labels": {"aggregate-to-monitoring": "true"}},
+		"rules": [{"apiGroups": [""], "resources": ["pods", "pods/log"], "verbs": ["get", "list"]}]},
+	{"kind": "ClusterRole", "metadata": {"name": "metrics-reader", "labels": {"aggregate-to-monitoring": "false"}},
+		"rules": [{"nonResourceURLs": ["/metrics"], "verbs": ["get"]}]},
+	{"kind": "Role", "metadata": {"name": "secret-reader", "namespace": "kube-system"},
+		"rules": [{"apiGroups": [""], "resources": ["secrets"], "verbs": ["get"], "resourceNames": ["token"]},
+			{"apiGroups": [""], "resources": ["secrets"], "verbs": ["list"]}]},
+	{"kind": "ClusterRoleBinding", "metadata": {"name": "admins"},
+		"roleRef": {"kind": "ClusterRole", "name": "cluster-admin"},
+		"subjects": [{"kind": "Group", "name": "system:masters"}]},
+	{"kind": "ClusterRoleBinding", "metadata": {"name": "monitoring"},
+		"roleRef": {"kind": "ClusterRole", "name": "monitoring"},
+		"subjects": [{"kind": "Group", "name": "system:serviceaccounts:monitoring"}]},
+	{"kind": "RoleBinding", "metadata": {"name": "read-secrets", "namespace": "kube-system"},
+		"roleRef": {"kind": "Role", "name": "secret-reader"},
+		"subjects": [{"kind": "User", "name": "alice"}, {"kind": "ServiceAccount", "name": "ci"}]},
+	{"kind": "RoleBinding", "metadata": {"name": "admin", "namespace": "dev"},
+		"roleRef": {"kind": "ClusterRole", "name": "cluster-admin"},
+		"subjects": [{"kind": "User", "name": "bob"}]},
+	{"kind": "RoleBinding", "metadata": {"name": "missing", "namespace": "dev"},
+		"roleRef": {"kind": "Role", "name": "secret-reader"},
+		"subjects": [{"kind": "User", "name": "carol"}]}
+]}`
+
+func getRBAC(t *testing.T) rbac.RBAC {
+	nodeList, err := nodelist.NewNodeList([]byte(testJSON), true)
+	if err != nil {
+		t.Fatalf("Failed to parse test data - %s", err.Error())
+	}
+	return rbac.New(&nodeList)
+}
+
+func getSubjects(permissions []rbac.Permission) []string {
+	var subjects []string
+	for _, permission := range permissions {
+		subjects = append(subjects, permission.Subject.String()+" "+permission.Namespace)
+	}
+	return subjects
+}
+
+func TestWhoCanIncludesClusterWideAndNamespacedBindings(t *testing.T) {
+	actual := getSubjects(getRBAC(t).WhoCan("get", "secrets", "kube-system"))
+	expected := []string{"Group:system:masters *", "User:alice kube-system", "ServiceAccount:kube-system/ci kube-system"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected %v but got %v", expected, actual)
+	}
+}
+
+func TestWhoCanWithoutNamespaceMatchesAnyNamespace(t *testing.T) {
+	actual := getSubjects(getRBAC(t).WhoCan("delete", "deployments.apps", ""))
+	expected := []string{"Group:system:masters *", "User:bob dev"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected %v but got %v", expected, actual)
+	}
+}
+
+func TestWhoCanUsesAggregatedClusterRoles(t *testing.T) {
+	actual := getSubjects(getRBAC(t).WhoCan("get", "pods/log", "default"))
+	expected := []string{"Group:system:masters *", "Group:system:serviceaccounts:monitoring *"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected %v but got %v", expected, actual)
+	}
+}
+
+func TestWhoCanOnlyGrantsNonResourceURLsClusterWide(t *testing.T) {
+	actual := getSubjects(getRBAC(t).WhoCan("get", "/metrics", ""))
+	expected := []string{"Group:system:masters *"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected %v but got %v", expected, actual)
+	}
+}
+
+func TestBindingsToMissingRolesAreUnresolved(t *testing.T) {
+	unresolved := getRBAC(t).Unresolved()
+	if len(unresolved) != 1 || unresolved[0].String() != "RoleBinding dev/missing" {
+		t.Errorf("Expected only RoleBinding dev/missing to be unresolved but got %v", unresolved)
+	}
+}
+
+func TestEffectiveMergesVerbsPerResource(t *testing.T) {
+	actual := getRBAC(t).Effective(rbac.Subject{Kind: "User", Name: "alice"})
+	expected := []rbac.Access{
+		{Namespace: "kube-system", Resource: "secrets", ResourceNames: []string{"token"}, Verbs: []string{"get"}},
+		{Namespace: "kube-system", Resource: "secrets", Verbs: []string{"list"}},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected %v but got %v", expected, actual)
+	}
+}
+
+func TestEffectiveIncludesImplicitGroups(t *testing.T) {
+	actual := getRBAC(t).Effective(rbac.Subject{Kind: "ServiceAccount", Name: "prometheus", Namespace: "monitoring"})
+	expected := []rbac.Access{
+		{Namespace: "*", Resource: "pods", Verbs: []string{"get", "list"}},
+		{Namespace: "*", Resource: "pods/log", Verbs: []string{"get", "list"}},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected %v but got %v", expected, actual)
+	}
+}
+
+func TestParseSubjectReadsEachKind(t *testing.T) {
+	for input, expected := range map[string]rbac.Subject{
+		"User:alice":                     {Kind: "User", Name: "alice"},
+		"group:system:masters":           {Kind: "Group", Name: "system:masters"},
+		"ServiceAccount:kube-system/foo": {Kind: "ServiceAccount", Name: "foo", Namespace: "kube-system"},
+	} {
+		actual, err := rbac.ParseSubject(input)
+		if err != nil || actual != expected {
+			t.Errorf("Expected %v for '%s' but got %v (%v)", expected, input, actual, err)
+		}
+	}
+	for _, input := range []string{"alice", "Robot:r2", "ServiceAccount:foo"} {
+		if _, err := rbac.ParseSubject(input); err == nil {
+			t.Errorf("Expected an error for '%s' but got none", input)
+		}
+	}
+}
+
+func TestRuleMatchesWildcardSubresources(t *testing.T) {
+	rule := rbac.Rule{Verbs: []string{"update"}, APIGroups: []string{"apps"}, Resources: []string{"*/scale"}}
+	if !rule.Allows("update", "deployments.apps/scale") {
+		t.Errorf("Expected */scale to allow deployments.apps/scale")
+	}
+	if rule.Allows("update", "deployments.apps") {
+		t.Errorf("Expected */scale not to allow deployments.apps")
+	}
+}
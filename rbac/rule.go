@@ -0,0 +1,131 @@
+package rbac
+
+import (
+	"kube-review/k8s"
+	"sort"
+	"strings"
+)
+
+// Rule is one of the policy rules of a Role or ClusterRole
+type Rule struct {
+	Verbs           []string
+	APIGroups       []string
+	Resources       []string
+	ResourceNames   []string
+	NonResourceURLs []string
+}
+
+// Allows returns true if the rule grants verb on resource, which is given as kubectl would,
+// e.g. secrets, deployments.apps or pods/exec, or as a non-resource URL such as /metrics
+func (r Rule) Allows(verb, resource string) bool {
+	if !matchesAny(r.Verbs, verb) {
+		return false
+	}
+	if strings.HasPrefix(resource, "/") {
+		return matchesURL(r.NonResourceURLs, resource)
+	}
+	name, group := ParseResource(resource)
+	return matchesAny(r.APIGroups, group) && matchesResource(r.Resources, name)
+}
+
+// ParseResource splits a resource such as deployments.apps/scale into its name, including any
+// subresource, and API group. Resources without a group are in the core group ""
+func ParseResource(resource string) (string, string) {
+	subresource := ""
+	if index := strings.Index(resource, "/"); index >= 0 {
+		resource, subresource = resource[:index], resource[index:]
+	}
+	group := ""
+	if index := strings.Index(resource, "."); index >= 0 {
+		resource, group = resource[:index], resource[index+1:]
+	}
+	return resource + subresource, group
+}
+
+// FormatResource is the reverse of ParseResource
+func FormatResource(name, group string) string {
+	if group == "" {
+		return name
+	}
+	resource, subresource := name, ""
+	if index := strings.Index(name, "/"); index >= 0 {
+		resource, subresource = name[:index], name[index:]
+	}
+	return resource + "." + group + subresource
+}
+
+/////////////////////////////////////////////////////////////////////////////////
+// PRIVATE FUNCTIONS
+
+// newRules returns the rules of the role at index along with the index of each rule's node
+func newRules(nodeList k8s.NodeList, index int) ([]Rule, []int) {
+	var rules []Rule
+	var indices []int
+	if parent, ok := nodeList.GetChild(index, "rules"); ok {
+		for _, child := range nodeList.GetChildren(parent) {
+			indices = append(indices, child)
+			rules = append(rules, Rule{
+				Verbs:           getStrings(nodeList, child, "verbs"),
+				APIGroups:       getStrings(nodeList, child, "apiGroups"),
+				Resources:       getStrings(nodeList, child, "resources"),
+				ResourceNames:   getStrings(nodeList, child, "resourceNames"),
+				NonResourceURLs: getStrings(nodeList, child, "nonResourceURLs"),
+			})
+		}
+	}
+	return rules, indices
+}
+
+// key identifies the rule so duplicates can be dropped when aggregating
+func (r Rule) key() string {
+	var parts []string
+	for _, values := range [][]string{r.Verbs, r.APIGroups, r.Resources, r.ResourceNames, r.NonResourceURLs} {
+		sorted := append([]string{}, values...)
+		sort.Strings(sorted)
+		parts = append(parts, strings.Join(sorted, ","))
+	}
+	return strings.Join(parts, "|")
+}
+
+func matchesAny(values []string, value string) bool {
+	for _, v := range values {
+		if v == "*" || v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesResource also allows "*/subresource" to match the subresource of any resource
+func matchesResource(resources []string, resource string) bool {
+	for _, r := range resources {
+		if r == "*" || r == resource {
+			return true
+		}
+		if strings.HasPrefix(r, "*/") && strings.Contains(resource, "/") &&
+			r[1:] == resource[strings.Index(resource, "/"):] {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesURL allows a trailing "*" to match any URL with that prefix
+func matchesURL(urls []string, url string) bool {
+	for _, u := range urls {
+		if u == url || (strings.HasSuffix(u, "*") && strings.HasPrefix(url, strings.TrimSuffix(u, "*"))) {
+			return true
+		}
+	}
+	return false
+}
+
+func getStrings(nodeList k8s.NodeList, index int, keys ...string) []string {
+	var values []string
+	if parent, ok := nodeList.GetChild(index, keys...); ok {
+		for _, child := range nodeList.GetChildren(parent) {
+			values = append(values, nodeList.GetValue(child))
+		}
+	}
+	return values
+}
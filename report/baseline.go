@@ -0,0 +1,190 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"kube-review/nodelist"
+	"kube-review/search"
+	"kube-review/utils"
+	"strings"
+)
+
+// StatusEnum labels a result by comparing it with the results of a previous run
+type StatusEnum int
+
+const (
+	// UNTRACKED a
+	UNTRACKED StatusEnum = iota
+	// NEW a
+	NEW
+	// UNCHANGED a
+	UNCHANGED
+	// FIXED a
+	FIXED
+)
+
+var statusNames = [...]string{"", "New", "Unchanged", "Fixed"}
+
+func (se StatusEnum) String() string {
+	return statusNames[se]
+}
+
+// MarshalJSON stores the status by name
+func (se StatusEnum) MarshalJSON() ([]byte, error) {
+	return json.Marshal(se.String())
+}
+
+// UnmarshalJSON reads the status from its name
+func (se *StatusEnum) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return err
+	}
+	for index, status := range statusNames {
+		if strings.EqualFold(name, status) {
+			*se = StatusEnum(index)
+			return nil
+		}
+	}
+	return fmt.Errorf("Invalid status '%s'", name)
+}
+
+// Result is a single field matched by a query, which is what is compared between runs. Query
+// is the full name of the query, so the result is the same however the query was named.
+// Resource is the path of the object in the config, e.g. items[3], and Path is the field
+// within that object, so results still match if objects are listed in a different order
+type Result struct {
+	Query     string              `json:"query"`
+	Severity  search.SeverityEnum `json:"severity"`
+	Kind      string              `json:"kind,omitempty"`
+	Namespace string              `json:"namespace,omitempty"`
+	Name      string              `json:"name,omitempty"`
+	Resource  string              `json:"resource"`
+	Path      string              `json:"path"`
+	Line      int                 `json:"line,omitempty"`
+	Status    StatusEnum          `json:"status,omitempty"`
+}
+
+// Key identifies the result across runs by its query, the identity of its object and its path
+func (r Result) Key() string {
+	identity := r.Resource
+	if r.Name != "" {
+		identity = r.Kind + "/" + r.Namespace + "/" + r.Name
+	}
+	return r.Query + "|" + identity + "|" + r.Path
+}
+
+// Object describes the object the result is in, e.g. Deployment ns/foo
+func (r Result) Object() string {
+	return nodelist.ResourceInfo{Path: r.Resource, Kind: r.Kind, Namespace: r.Namespace, Name: r.Name}.String()
+}
+
+// GetResults returns a Result for every field matched by findings
+func GetResults(findings []Finding) []Result {
+	var results []Result
+	for _, finding := range findings {
+		for _, resource := range finding.Resources {
+			for _, match := range resource.Matches {
+				path := strings.TrimPrefix(strings.TrimPrefix(match.Path, resource.Path), ".")
+				results = append(results, Result{finding.resultQuery(), finding.Query.Severity, resource.Kind,
+					resource.Namespace, resource.Name, resource.Path, path, match.Line, UNTRACKED})
+			}
+		}
+	}
+	return results
+}
+
+type resultsFile struct {
+	Results []Result `json:"results"`
+}
+
+// ResultsJSON returns findings as a JSON results file, which can be passed as the baseline of a
+// later run. If baseline is not nil, results are labelled and those fixed since are included
+func ResultsJSON(findings []Finding, baseline []Result) (string, error) {
+	results := GetResults(findings)
+	if baseline != nil {
+		results = Label(baseline, findings)
+	}
+	if results == nil {
+		results = []Result{}
+	}
+	out, err := json.MarshalIndent(resultsFile{results}, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("Could not create results - %s", err.Error())
+	}
+	return string(out) + "\n", nil
+}
+
+// LoadResults reads a results file written by ResultsJSON. Results that were labelled as
+// fixed are left out as they were not found in that run
+func LoadResults(filename string) ([]Result, error) {
+	var file resultsFile
+	if err := utils.LoadJSON(filename, &file, ""); err != nil {
+		return nil, fmt.Errorf("Failed to load results from '%s' - %s", filename, err.Error())
+	}
+	results := []Result{}
+	for _, result := range file.Results {
+		if result.Status != FIXED {
+			result.Status = UNTRACKED
+			results = append(results, result)
+		}
+	}
+	return results, nil
+}
+
+// Label labels the results of findings as new or unchanged, depending on whether they are in
+// previous, and adds those in previous that are no longer found as fixed. Results of queries
+// that were not run this time are left out rather than reported as fixed
+func Label(previous []Result, findings []Finding) []Result {
+	previousKeys := map[string]bool{}
+	for _, result := range previous {
+		previousKeys[result.Key()] = true
+	}
+	run := map[string]bool{}
+	for _, finding := range findings {
+		run[finding.resultQuery()] = true
+	}
+	currentKeys := map[string]bool{}
+	labelled := []Result{}
+	for _, result := range GetResults(findings) {
+		currentKeys[result.Key()] = true
+		result.Status = NEW
+		if previousKeys[result.Key()] {
+			result.Status = UNCHANGED
+		}
+		labelled = append(labelled, result)
+	}
+	for _, result := range previous {
+		if run[result.Query] && !currentKeys[result.Key()] {
+			result.Status = FIXED
+			labelled = append(labelled, result)
+		}
+	}
+	return labelled
+}
+
+// WithStatus returns the results that have status
+func WithStatus(results []Result, status StatusEnum) []Result {
+	var matching []Result
+	for _, result := range results {
+		if result.Status == status {
+			matching = append(matching, result)
+		}
+	}
+	return matching
+}
+
+// resultQuery identifies the query and arguments of the finding in its results
+func (f Finding) resultQuery() string {
+	return search.FormatQueryArgs(f.FullName, f.Args)
+}
+
+// NewAtOrAbove returns true if any new result has a severity of at least threshold
+func NewAtOrAbove(results []Result, threshold search.SeverityEnum) bool {
+	for _, result := range WithStatus(results, NEW) {
+		if result.Severity >= threshold {
+			return true
+		}
+	}
+	return false
+}
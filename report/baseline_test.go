@@ -0,0 +1,108 @@
+package report_test
+
+import (
+	"io/ioutil"
+	"kube-review/report"
+	"kube-review/search"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGetResultsHasFieldPerMatchRelativeToResource(t *testing.T) {
+	results := report.GetResults([]report.Finding{getConfigMapFinding(t, "Secrets-in-ConfigMap")})
+	expected := report.Result{Query: "default/Secrets-in-ConfigMap", Severity: search.HIGH, Kind: "ConfigMap",
+		Namespace: "default", Name: "tls-keys", Resource: "items[0]", Path: "data.tls.key", Line: 13}
+	for _, result := range results {
+		if result == expected {
+			return
+		}
+	}
+	t.Errorf("Expected %v in %v", expected, results)
+}
+
+func TestLabelMarksNewUnchangedAndFixed(t *testing.T) {
+	finding := getConfigMapFinding(t, "Secrets-in-ConfigMap")
+	current := report.GetResults([]report.Finding{finding})
+	fixed := report.Result{Query: "default/Secrets-in-ConfigMap", Kind: "ConfigMap", Name: "gone", Path: "data.key"}
+	previous := append([]report.Result{fixed}, current[1:]...)
+
+	labelled := report.Label(previous, []report.Finding{finding})
+	if actual := report.WithStatus(labelled, report.NEW); len(actual) != 1 || actual[0].Key() != current[0].Key() {
+		t.Errorf("Expected %v to be new but got %v", current[0], actual)
+	}
+	if actual := report.WithStatus(labelled, report.UNCHANGED); len(actual) != len(current)-1 {
+		t.Errorf("Expected %d unchanged results but got %d", len(current)-1, len(actual))
+	}
+	if actual := report.WithStatus(labelled, report.FIXED); len(actual) != 1 || actual[0].Name != "gone" {
+		t.Errorf("Expected the removed ConfigMap to be fixed but got %v", actual)
+	}
+}
+
+func TestLabelMatchesResourcesByIdentityNotPosition(t *testing.T) {
+	finding := getConfigMapFinding(t, "Secrets-in-ConfigMap")
+	previous := report.GetResults([]report.Finding{finding})
+	for i := range previous {
+		previous[i].Resource = "items[7]"
+		previous[i].Line = 100
+	}
+	labelled := report.Label(previous, []report.Finding{finding})
+	if actual := report.WithStatus(labelled, report.UNCHANGED); len(actual) != len(previous) {
+		t.Errorf("Expected every result to be unchanged but got %v", labelled)
+	}
+}
+
+func TestLabelIgnoresQueriesThatWereNotRun(t *testing.T) {
+	previous := []report.Result{{Query: "default/Other-Query", Kind: "ConfigMap", Name: "x", Path: "data"}}
+	labelled := report.Label(previous, []report.Finding{getConfigMapFinding(t, "Secrets-in-ConfigMap")})
+	if actual := report.WithStatus(labelled, report.FIXED); len(actual) != 0 {
+		t.Errorf("Expected no fixed results but got %v", actual)
+	}
+}
+
+func TestResultsJSONCanBeLoadedAsBaseline(t *testing.T) {
+	finding := getConfigMapFinding(t, "Secrets-in-ConfigMap")
+	fixed := report.Result{Query: "default/Secrets-in-ConfigMap", Kind: "ConfigMap", Name: "gone", Path: "data.key"}
+	content, err := report.ResultsJSON([]report.Finding{finding}, []report.Result{fixed})
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err.Error())
+	}
+	if !strings.Contains(content, `"status": "Fixed"`) || !strings.Contains(content, `"status": "New"`) {
+		t.Errorf("Expected labelled results but got %s", content)
+	}
+
+	file := filepath.Join(t.TempDir(), "results.json")
+	ioutil.WriteFile(file, []byte(content), 0644)
+	loaded, err := report.LoadResults(file)
+	if err != nil {
+		t.Fatalf("Expected no error but got %s", err.Error())
+	}
+	if expected := report.GetResults([]report.Finding{finding}); len(loaded) != len(expected) || loaded[0] != expected[0] {
+		t.Errorf("Expected %v without the fixed result or statuses but got %v", expected, loaded)
+	}
+}
+
+func TestNewAtOrAboveOnlyCountsNewResults(t *testing.T) {
+	results := []report.Result{
+		{Severity: search.CRITICAL, Status: report.UNCHANGED},
+		{Severity: search.CRITICAL, Status: report.FIXED},
+		{Severity: search.MEDIUM, Status: report.NEW},
+	}
+	if !report.NewAtOrAbove(results, search.MEDIUM) || report.NewAtOrAbove(results, search.HIGH) {
+		t.Errorf("Expected only the new Medium result to count")
+	}
+}
+
+func TestRenderShowsChangesSinceBaseline(t *testing.T) {
+	doc := getDocument(t)
+	doc.SetBaseline([]report.Result{{Query: "default/Secrets-in-ConfigMap", Severity: search.HIGH, Kind: "ConfigMap", Name: "gone", Path: "data.key"}})
+	actual, _ := report.Render(doc, "markdown", "")
+	for _, expected := range []string{"## Changes Since Baseline", " new, 1 fixed and 0 unchanged", "| Fixed | High | default/Secrets-in-ConfigMap | ConfigMap gone | `data.key` |"} {
+		if !strings.Contains(actual, expected) {
+			t.Errorf("Expected '%s' in %s", expected, actual)
+		}
+	}
+	if actual, _ := report.Render(getDocument(t), "markdown", ""); strings.Contains(actual, "Changes Since Baseline") {
+		t.Errorf("Expected no changes section without a baseline")
+	}
+}
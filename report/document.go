@@ -0,0 +1,124 @@
+package report
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strings"
+	texttemplate "text/template"
+	"time"
+)
+
+var (
+	//go:embed templates/report.html
+	defaultHTMLTemplate string
+	//go:embed templates/report.md
+	defaultMarkdownTemplate string
+)
+
+// Document is the data passed to report templates. Changes is nil unless a baseline was set
+type Document struct {
+	Title     string
+	Source    string
+	Generated time.Time
+	Findings  []Finding
+	Changes   []Result
+}
+
+// NewDocument stuff
+func NewDocument(title, source string, findings []Finding) Document {
+	return Document{title, source, time.Now(), findings, nil}
+}
+
+// SetBaseline labels the results of the findings by comparing them with previous, the
+// results of an earlier run, to show remediation progress
+func (d *Document) SetBaseline(previous []Result) {
+	d.Changes = Label(previous, d.Findings)
+	sort.SliceStable(d.Changes, func(i, j int) bool { return d.Changes[i].Status < d.Changes[j].Status })
+}
+
+// Tracked returns true if a baseline was set
+func (d Document) Tracked() bool {
+	return d.Changes != nil
+}
+
+// NewResults returns the results not found in the baseline
+func (d Document) NewResults() []Result {
+	return WithStatus(d.Changes, NEW)
+}
+
+// FixedResults returns the results in the baseline that are no longer found
+func (d Document) FixedResults() []Result {
+	return WithStatus(d.Changes, FIXED)
+}
+
+// UnchangedResults returns the results found in both the baseline and this run
+func (d Document) UnchangedResults() []Result {
+	return WithStatus(d.Changes, UNCHANGED)
+}
+
+// Matched returns the findings that matched anything, most severe first
+func (d Document) Matched() []Finding {
+	var matched []Finding
+	for _, finding := range d.Findings {
+		if finding.Matched() {
+			matched = append(matched, finding)
+		}
+	}
+	sort.SliceStable(matched, func(i, j int) bool { return matched[i].Query.Severity > matched[j].Query.Severity })
+	return matched
+}
+
+// Anchor returns an id for the finding that can be linked to from the summary
+func (f Finding) Anchor() string {
+	return strings.Trim(anchorRegex.ReplaceAllString(strings.ToLower(f.ID()), "-"), "-")
+}
+
+var anchorRegex = regexp.MustCompile(`[^a-z0-9]+`)
+
+var templateFuncs = map[string]interface{}{
+	"join": strings.Join,
+	"cell": func(s string) string { return strings.NewReplacer("|", "\\|", "\n", " ").Replace(s) },
+	"date": func(t time.Time) string { return t.Format("2006-01-02 15:04 MST") },
+}
+
+// Render creates an html or markdown report of doc. If templateFile is not "" it is
+// used instead of the built in template for format
+func Render(doc Document, format, templateFile string) (string, error) {
+	var content string
+	switch format {
+	case "html":
+		content = defaultHTMLTemplate
+	case "markdown":
+		content = defaultMarkdownTemplate
+	default:
+		return "", fmt.Errorf("Invalid report format '%s'. Must be either html or markdown", format)
+	}
+	if templateFile != "" {
+		raw, err := ioutil.ReadFile(templateFile)
+		if err != nil {
+			return "", err
+		}
+		content = string(raw)
+	}
+
+	var out bytes.Buffer
+	if format == "html" {
+		tmpl, err := htmltemplate.New("report").Funcs(templateFuncs).Parse(content)
+		if err != nil {
+			return "", err
+		}
+		err = tmpl.Execute(&out, doc)
+		return out.String(), err
+	}
+	tmpl, err := texttemplate.New("report").Funcs(templateFuncs).Parse(content)
+	if err != nil {
+		return "", err
+	}
+	err = tmpl.Execute(&out, doc)
+	return out.String(), err
+}
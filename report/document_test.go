@@ -0,0 +1,71 @@
+package report_test
+
+import (
+	"io/ioutil"
+	"kube-review/report"
+	"kube-review/search"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func getDocument(t *testing.T) report.Document {
+	matched := getConfigMapFinding(t, "Secrets-in-ConfigMap")
+	info := getConfigMapFinding(t, "ConfigMap-Data-Matching")
+	unmatched := getConfigMapFinding(t, "ConfigMap-Data-Matching:pattern=zzzz")
+	return report.NewDocument("Test Report", "configmaps.json", []report.Finding{info, matched, unmatched})
+}
+
+func TestMatchedReturnsMostSevereFirst(t *testing.T) {
+	actual := getDocument(t).Matched()
+	if len(actual) != 2 || actual[0].Query.Severity != search.HIGH || actual[1].Query.Severity != search.INFO {
+		t.Errorf("Expected High then Info findings but got %v", actual)
+	}
+}
+
+func TestAnchorIsLowerCaseWithoutPunctuation(t *testing.T) {
+	finding := report.Finding{Name: "Some-Query", Args: map[string]string{"pattern": "a|b"}}
+	if actual := finding.Anchor(); actual != "some-query-pattern-a-b" {
+		t.Errorf("Expected 'some-query-pattern-a-b' but got '%s'", actual)
+	}
+}
+
+func TestRenderMarkdownHasSummaryAndSections(t *testing.T) {
+	actual, err := report.Render(getDocument(t), "markdown", "")
+	if err != nil {
+		t.Fatalf("Expected no error but got '%s'", err.Error())
+	}
+	for _, expected := range []string{"# Test Report", "| High | [Secrets-in-ConfigMap](#secrets-in-configmap) | Secrets Management | 1 |",
+		"2 of 3 queries matched", "## Secrets-in-ConfigMap", "`items[0].data.tls.key` (line 13)", "```json\n{"} {
+		if !strings.Contains(actual, expected) {
+			t.Errorf("Expected '%s' in %s", expected, actual)
+		}
+	}
+}
+
+func TestRenderHTMLEscapesContent(t *testing.T) {
+	doc := getDocument(t)
+	doc.Findings[1].Query.Description = "<script>alert(1)</script>"
+	actual, err := report.Render(doc, "html", "")
+	if err != nil {
+		t.Fatalf("Expected no error but got '%s'", err.Error())
+	}
+	if strings.Contains(actual, "<script>") || !strings.Contains(actual, `<div class="finding" id="secrets-in-configmap">`) {
+		t.Errorf("Expected escaped HTML with a section per finding but got %s", actual)
+	}
+}
+
+func TestRenderUsesTemplateFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "custom.tmpl")
+	ioutil.WriteFile(file, []byte("{{range .Matched}}{{.Name}};{{end}}"), 0600)
+	actual, err := report.Render(getDocument(t), "markdown", file)
+	if err != nil || actual != "Secrets-in-ConfigMap;ConfigMap-Data-Matching;" {
+		t.Errorf("Expected custom template output but got '%s', %v", actual, err)
+	}
+}
+
+func TestRenderReturnsErrorForInvalidFormat(t *testing.T) {
+	if _, err := report.Render(getDocument(t), "pdf", ""); err == nil {
+		t.Errorf("Expected an error but got nothing")
+	}
+}
@@ -0,0 +1,76 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+type junitSuites struct {
+	XMLName xml.Name     `xml:"testsuites"`
+	Suites  []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Details string `xml:",cdata"`
+}
+
+// JUnit creates a JUnit XML report with a test case for every finding, which fails if the
+// query matched anything. The failure lists each resource matched
+func JUnit(findings []Finding) (string, error) {
+	suite := junitSuite{Name: "kube-review", Tests: len(findings)}
+	for _, finding := range findings {
+		testCase := junitCase{Name: finding.ID(), ClassName: "kube-review"}
+		if finding.Query.Category != "" {
+			testCase.ClassName += "." + finding.Query.Category
+		}
+		if finding.Matched() {
+			suite.Failures++
+			testCase.Failure = newJUnitFailure(finding)
+		}
+		suite.Cases = append(suite.Cases, testCase)
+	}
+	out, err := xml.MarshalIndent(junitSuites{Suites: []junitSuite{suite}}, "", "    ")
+	if err != nil {
+		return "", err
+	}
+	return xml.Header + string(out) + "\n", nil
+}
+
+func newJUnitFailure(finding Finding) *junitFailure {
+	var details []string
+	for _, resource := range finding.Resources {
+		details = append(details, resource.String())
+		for _, match := range resource.Matches {
+			if match.Line > 0 {
+				details = append(details, fmt.Sprintf("    %s (line %d)", match.Path, match.Line))
+			} else {
+				details = append(details, "    "+match.Path)
+			}
+		}
+	}
+	details = append(details, "", finding.Query.Description)
+	if finding.Query.Remediation != "" {
+		details = append(details, "Remediation: "+finding.Query.Remediation)
+	}
+	return &junitFailure{
+		Message: fmt.Sprintf("%s matched %d resource(s)", finding.Name, len(finding.Resources)),
+		Type:    finding.Query.Severity.String(),
+		Details: strings.Join(details, "\n"),
+	}
+}
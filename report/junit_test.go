@@ -0,0 +1,28 @@
+package report_test
+
+import (
+	"kube-review/report"
+	"strings"
+	"testing"
+)
+
+func TestJUnitFailsOnlyMatchedFindings(t *testing.T) {
+	matched := getConfigMapFinding(t, "Secrets-in-ConfigMap")
+	unmatched := getConfigMapFinding(t, "ConfigMap-Data-Matching:pattern=zzzz")
+	actual, err := report.JUnit([]report.Finding{matched, unmatched})
+	if err != nil {
+		t.Fatalf("Expected no error but got '%s'", err.Error())
+	}
+	if !strings.Contains(actual, `tests="2" failures="1"`) || strings.Count(actual, "<failure ") != 1 {
+		t.Errorf("Expected 2 tests with 1 failure but got %s", actual)
+	}
+}
+
+func TestJUnitFailureIncludesSeverityPathAndLine(t *testing.T) {
+	actual, _ := report.JUnit([]report.Finding{getConfigMapFinding(t, "Secrets-in-ConfigMap")})
+	for _, expected := range []string{`type="High"`, "items[0].data.tls.key (line 13)", "matched 1 resource(s)"} {
+		if !strings.Contains(actual, expected) {
+			t.Errorf("Expected '%s' in %s", expected, actual)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package report
+
+import (
+	"fmt"
+	"kube-review/nodelist"
+	"kube-review/search"
+)
+
+// Finding is the outcome of running a single query, along with the query's metadata.
+// Resources are the objects the query matched along with the offending fields in each.
+// Name is as it was given, while FullName includes the query's pack, e.g. default/name
+type Finding struct {
+	Name      string
+	FullName  string
+	Query     search.QueryData
+	Args      map[string]string
+	JSON      string
+	Resources []search.Resource
+}
+
+// NewFinding runs input, a query name with optional arguments, against nodeList and records
+// what it matched. nodeList is passed by value so the caller's current view is left as it is
+func NewFinding(ql *search.QueryList, input string, nodeList nodelist.NodeList) (Finding, error) {
+	name, args, err := search.ParseQueryArgs(input)
+	if err != nil {
+		return Finding{}, err
+	}
+	data, ok := ql.Get(name)
+	if !ok {
+		return Finding{}, fmt.Errorf("'%s' is not a valid query", name)
+	}
+
+	s := search.NewSearch(search.QUERY, ql)
+	s.SetModes(search.QUERY, search.FILTER)
+	resources, err := s.Execute(input, &nodeList)
+	if err != nil {
+		return Finding{}, fmt.Errorf("Query failed - %s", err.Error())
+	}
+	return Finding{name, ql.Resolve(name), data, args, nodeList.GetJSON(-1), resources}, nil
+}
+
+// ID identifies the query and any arguments it was run with
+func (f Finding) ID() string {
+	return search.FormatQueryArgs(f.Name, f.Args)
+}
+
+// Matched returns true if the query matched any nodes
+func (f Finding) Matched() bool {
+	return len(f.Resources) > 0
+}
+
+// GetArgs returns the value used for every parameter of the query, falling back to defaults
+func (f Finding) GetArgs() map[string]string {
+	args := map[string]string{}
+	for _, param := range f.Query.Parameters {
+		if value, ok := f.Args[param.Name]; ok {
+			args[param.Name] = value
+		} else {
+			args[param.Name] = param.Default
+		}
+	}
+	return args
+}
+
+// AtOrAbove returns true if any finding that matched has a severity of at least threshold
+func AtOrAbove(findings []Finding, threshold search.SeverityEnum) bool {
+	for _, finding := range findings {
+		if finding.Matched() && finding.Query.Severity >= threshold {
+			return true
+		}
+	}
+	return false
+}
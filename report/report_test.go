@@ -0,0 +1,123 @@
+package report_test
+
+import (
+	"io/ioutil"
+	"kube-review/nodelist"
+	"kube-review/report"
+	"kube-review/search"
+	"testing"
+)
+
+func getQueryList() *search.QueryList {
+	ql := search.NewQueryList()
+	ql.Load("../testdata/querylist-test.json", "../search/queryschema.json")
+	ql.Add("no-match", "zzzzzzzzzz", "Never matches", search.REGEX)
+	ql.LoadPack("../testdata/querypacks/extra.json")
+	return &ql
+}
+
+func getNodeList(t *testing.T) *nodelist.NodeList {
+	rawJSON, err := ioutil.ReadFile("../testdata/test.json")
+	if err != nil {
+		t.Fatalf("Failed to read test data - %s", err.Error())
+	}
+	nodeList, err := nodelist.NewNodeList(rawJSON, true)
+	if err != nil {
+		t.Fatalf("Failed to parse test data - %s", err.Error())
+	}
+	return &nodeList
+}
+
+func TestNewFindingRecordsMatchesAndMetadata(t *testing.T) {
+	finding, err := report.NewFinding(getQueryList(), "test1more", *getNodeList(t))
+	if err != nil {
+		t.Fatalf("Expected no error but got '%s'", err.Error())
+	}
+	if !finding.Matched() || finding.Query.Severity != search.LOW {
+		t.Errorf("Expected a Low finding with matches but got %v", finding)
+	}
+}
+
+func TestNewFindingLeavesViewUnchanged(t *testing.T) {
+	nodeList := getNodeList(t)
+	expected := nodeList.GetJSON(-1)
+	report.NewFinding(getQueryList(), "test1more", *nodeList)
+	if actual := nodeList.GetJSON(-1); actual != expected {
+		t.Errorf("Expected the view to be unchanged")
+	}
+}
+
+func TestNewFindingWithoutMatches(t *testing.T) {
+	finding, err := report.NewFinding(getQueryList(), "no-match", *getNodeList(t))
+	if err != nil || finding.Matched() {
+		t.Errorf("Expected no matches and no error but got %v, %v", finding.JSON, err)
+	}
+}
+
+func TestNewFindingReturnsErrorForUnknownQuery(t *testing.T) {
+	if _, err := report.NewFinding(getQueryList(), "unknown", *getNodeList(t)); err == nil {
+		t.Errorf("Expected an error but got nothing")
+	}
+}
+
+func TestGetArgsFallsBackToDefaults(t *testing.T) {
+	finding := report.Finding{Query: search.QueryData{Parameters: []search.QueryParameter{
+		{Name: "given", Type: "string"}, {Name: "default", Type: "string", Default: "value"}}},
+		Args: map[string]string{"given": "arg"}}
+	actual := finding.GetArgs()
+	if actual["given"] != "arg" || actual["default"] != "value" {
+		t.Errorf("Expected given=arg and default=value but got %v", actual)
+	}
+}
+
+func getConfigMapFinding(t *testing.T, input string) report.Finding {
+	rawJSON, err := ioutil.ReadFile("../testdata/querytests/fixtures/configmaps.json")
+	if err != nil {
+		t.Fatalf("Failed to read test data - %s", err.Error())
+	}
+	nodeList, _ := nodelist.NewNodeList(rawJSON, true)
+	ql := search.NewQueryList()
+	ql.LoadDefaults()
+	finding, err := report.NewFinding(&ql, input, nodeList)
+	if err != nil {
+		t.Fatalf("Expected no error but got '%s'", err.Error())
+	}
+	return finding
+}
+
+func TestNewFindingRecordsPathAndLineOfMatches(t *testing.T) {
+	finding := getConfigMapFinding(t, "Secrets-in-ConfigMap")
+	expected := search.Match{Path: "items[0].data.tls.key", Line: 13}
+	for _, match := range finding.Resources[0].Matches {
+		if match == expected {
+			return
+		}
+	}
+	t.Errorf("Expected %v in %v", expected, finding.Resources)
+}
+
+func TestNewFindingGroupsMatchesByResource(t *testing.T) {
+	finding := getConfigMapFinding(t, "ConfigMap-Data-Matching:pattern=hunter2|PRIVATE")
+	actual := finding.Resources
+	if len(actual) != 2 || actual[0].String() != "ConfigMap default/tls-keys: 3 offending fields" ||
+		actual[1].String() != "ConfigMap kube-system/app-config: 3 offending fields" {
+		t.Errorf("Expected matches grouped into both ConfigMaps but got %v", actual)
+	}
+}
+
+func TestIDIncludesArguments(t *testing.T) {
+	finding := report.Finding{Name: "query", Args: map[string]string{"b": "2", "a": "1"}}
+	if actual := finding.ID(); actual != "query:a=1,b=2" {
+		t.Errorf("Expected 'query:a=1,b=2' but got '%s'", actual)
+	}
+}
+
+func TestAtOrAboveOnlyCountsMatchedFindings(t *testing.T) {
+	high := getConfigMapFinding(t, "Secrets-in-ConfigMap")
+	unmatched := getConfigMapFinding(t, "ConfigMap-Data-Matching:pattern=zzzz")
+	unmatched.Query.Severity = search.CRITICAL
+	findings := []report.Finding{high, unmatched}
+	if !report.AtOrAbove(findings, search.HIGH) || report.AtOrAbove(findings, search.CRITICAL) {
+		t.Errorf("Expected only High to be reached")
+	}
+}
@@ -0,0 +1,144 @@
+package report
+
+import (
+	"encoding/json"
+	"kube-review/search"
+)
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string      `json:"name"`
+	Rules []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string                 `json:"id"`
+	ShortDescription     sarifMessage           `json:"shortDescription"`
+	Help                 *sarifMessage          `json:"help,omitempty"`
+	DefaultConfiguration sarifConfiguration     `json:"defaultConfiguration"`
+	Properties           map[string]interface{} `json:"properties,omitempty"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+var (
+	sarifLevels             = [...]string{"note", "note", "warning", "error", "error"}
+	sarifSecuritySeverities = [...]string{"0.0", "3.0", "5.5", "8.0", "9.5"}
+)
+
+// SARIF creates a SARIF 2.1 log with a rule for every finding and a result for every resource
+// it matched. source is the file the config was loaded from and is left out if empty
+func SARIF(findings []Finding, source string) (string, error) {
+	driver := sarifDriver{Name: "kube-review", Rules: []sarifRule{}}
+	results := []sarifResult{}
+	for index, finding := range findings {
+		driver.Rules = append(driver.Rules, newSarifRule(finding))
+		for _, resource := range finding.Resources {
+			results = append(results, newSarifResult(finding, index, resource, source))
+		}
+	}
+	out, err := json.MarshalIndent(sarifLog{sarifSchema, sarifVersion, []sarifRun{{sarifTool{driver}, results}}}, "", "    ")
+	if err != nil {
+		return "", err
+	}
+	return string(out) + "\n", nil
+}
+
+func newSarifRule(finding Finding) sarifRule {
+	data := finding.Query
+	rule := sarifRule{
+		ID:                   finding.ID(),
+		ShortDescription:     sarifMessage{data.Description},
+		DefaultConfiguration: sarifConfiguration{sarifLevels[data.Severity]},
+		Properties:           map[string]interface{}{"security-severity": sarifSecuritySeverities[data.Severity]},
+	}
+	if data.Remediation != "" {
+		rule.Help = &sarifMessage{data.Remediation}
+	}
+	if data.Category != "" {
+		rule.Properties["category"] = data.Category
+	}
+	if len(data.Tags) > 0 {
+		rule.Properties["tags"] = data.Tags
+	}
+	if len(data.References) > 0 {
+		rule.Properties["references"] = data.References
+	}
+	return rule
+}
+
+func newSarifResult(finding Finding, ruleIndex int, resource search.Resource, source string) sarifResult {
+	result := sarifResult{
+		RuleID:    finding.ID(),
+		RuleIndex: ruleIndex,
+		Level:     sarifLevels[finding.Query.Severity],
+		Message:   sarifMessage{resource.String()},
+	}
+	for _, match := range resource.Matches {
+		location := sarifLocation{LogicalLocations: []sarifLogicalLocation{{match.Path, "member"}}}
+		if source != "" {
+			location.PhysicalLocation = &sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{source}}
+			if match.Line > 0 {
+				location.PhysicalLocation.Region = &sarifRegion{match.Line}
+			}
+		}
+		result.Locations = append(result.Locations, location)
+	}
+	return result
+}
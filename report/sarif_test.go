@@ -0,0 +1,79 @@
+package report_test
+
+import (
+	"encoding/json"
+	"kube-review/report"
+	"testing"
+)
+
+type sarifOutput struct {
+	Version string `json:"version"`
+	Runs    []struct {
+		Tool struct {
+			Driver struct {
+				Rules []struct {
+					ID string `json:"id"`
+				} `json:"rules"`
+			} `json:"driver"`
+		} `json:"tool"`
+		Results []struct {
+			RuleID    string `json:"ruleId"`
+			Level     string `json:"level"`
+			Locations []struct {
+				PhysicalLocation *struct {
+					ArtifactLocation struct {
+						URI string `json:"uri"`
+					} `json:"artifactLocation"`
+					Region *struct {
+						StartLine int `json:"startLine"`
+					} `json:"region"`
+				} `json:"physicalLocation"`
+				LogicalLocations []struct {
+					FullyQualifiedName string `json:"fullyQualifiedName"`
+				} `json:"logicalLocations"`
+			} `json:"locations"`
+		} `json:"results"`
+	} `json:"runs"`
+}
+
+func getSARIF(t *testing.T, source string, findings ...report.Finding) sarifOutput {
+	content, err := report.SARIF(findings, source)
+	if err != nil {
+		t.Fatalf("Expected no error but got '%s'", err.Error())
+	}
+	var out sarifOutput
+	if err := json.Unmarshal([]byte(content), &out); err != nil {
+		t.Fatalf("Expected valid JSON but got '%s'", err.Error())
+	}
+	return out
+}
+
+func TestSARIFHasRuleForEveryFindingAndResultPerResource(t *testing.T) {
+	matched := getConfigMapFinding(t, "ConfigMap-Data-Matching:pattern=hunter2|PRIVATE")
+	unmatched := getConfigMapFinding(t, "Secrets-in-ConfigMap")
+	unmatched.Resources = nil
+	actual := getSARIF(t, "config.json", matched, unmatched)
+	if actual.Version != "2.1.0" || len(actual.Runs[0].Tool.Driver.Rules) != 2 || len(actual.Runs[0].Results) != 2 {
+		t.Errorf("Expected 2 rules and 2 results but got %v", actual)
+	}
+	if actual.Runs[0].Results[0].RuleID != "ConfigMap-Data-Matching:pattern=hunter2|PRIVATE" {
+		t.Errorf("Expected ruleId to include arguments but got '%s'", actual.Runs[0].Results[0].RuleID)
+	}
+}
+
+func TestSARIFIncludesPathAndLine(t *testing.T) {
+	actual := getSARIF(t, "config.json", getConfigMapFinding(t, "Secrets-in-ConfigMap"))
+	result := actual.Runs[0].Results[0]
+	location := result.Locations[0]
+	if result.Level != "error" || location.LogicalLocations[0].FullyQualifiedName != "items[0].data.tls.key" ||
+		location.PhysicalLocation.ArtifactLocation.URI != "config.json" || location.PhysicalLocation.Region.StartLine != 13 {
+		t.Errorf("Expected error at items[0].data.tls.key on line 13 of config.json but got %v", result)
+	}
+}
+
+func TestSARIFLeavesOutPhysicalLocationWithoutSource(t *testing.T) {
+	actual := getSARIF(t, "", getConfigMapFinding(t, "Secrets-in-ConfigMap"))
+	if location := actual.Runs[0].Results[0].Locations[0]; location.PhysicalLocation != nil {
+		t.Errorf("Expected no physical location but got %v", location.PhysicalLocation)
+	}
+}
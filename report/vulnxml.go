@@ -0,0 +1,117 @@
+package report
+
+import (
+	"encoding/xml"
+	"sort"
+)
+
+type vulnXML struct {
+	XMLName         xml.Name        `xml:"vulnerabilities"`
+	Generator       string          `xml:"generator,attr"`
+	Vulnerabilities []vulnerability `xml:"vulnerability"`
+}
+
+type vulnerability struct {
+	ID          string          `xml:"id,attr"`
+	Title       string          `xml:"title"`
+	Severity    string          `xml:"severity"`
+	Category    string          `xml:"category,omitempty"`
+	Description string          `xml:"description"`
+	Remediation string          `xml:"remediation,omitempty"`
+	References  *vulnReferences `xml:"references"`
+	Tags        *vulnTags       `xml:"tags"`
+	Parameters  *vulnParameters `xml:"parameters"`
+	Resources   vulnResources   `xml:"resources"`
+	Evidence    vulnEvidence    `xml:"evidence"`
+}
+
+type vulnResources struct {
+	Resource []vulnResource `xml:"resource"`
+}
+
+type vulnResource struct {
+	Kind      string      `xml:"kind,attr,omitempty"`
+	Namespace string      `xml:"namespace,attr,omitempty"`
+	Name      string      `xml:"name,attr,omitempty"`
+	Path      string      `xml:"path,attr,omitempty"`
+	Summary   string      `xml:"summary"`
+	Fields    []vulnField `xml:"field"`
+}
+
+type vulnField struct {
+	Line int    `xml:"line,attr,omitempty"`
+	Path string `xml:",chardata"`
+}
+
+// The lists are wrapped in pointers as encoding/xml writes the parent element of an empty "a>b" list
+type vulnReferences struct {
+	Reference []string `xml:"reference"`
+}
+
+type vulnTags struct {
+	Tag []string `xml:"tag"`
+}
+
+type vulnParameters struct {
+	Parameter []vulnParameter `xml:"parameter"`
+}
+
+type vulnParameter struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:",chardata"`
+}
+
+type vulnEvidence struct {
+	Format string `xml:"format,attr"`
+	Data   string `xml:",cdata"`
+}
+
+// VulnXML creates a VulnXML document with an issue for every finding that matched
+func VulnXML(findings []Finding) (string, error) {
+	doc := vulnXML{Generator: "kube-review"}
+	for _, finding := range findings {
+		if finding.Matched() {
+			doc.Vulnerabilities = append(doc.Vulnerabilities, newVulnerability(finding))
+		}
+	}
+	out, err := xml.MarshalIndent(doc, "", "    ")
+	if err != nil {
+		return "", err
+	}
+	return xml.Header + string(out) + "\n", nil
+}
+
+func newVulnerability(finding Finding) vulnerability {
+	data := finding.Query
+	var params []vulnParameter
+	for name, value := range finding.GetArgs() {
+		params = append(params, vulnParameter{name, value})
+	}
+	sort.Slice(params, func(i, j int) bool { return params[i].Name < params[j].Name })
+	vuln := vulnerability{
+		ID:          finding.Name,
+		Title:       finding.Name,
+		Severity:    data.Severity.String(),
+		Category:    data.Category,
+		Description: data.Description,
+		Remediation: data.Remediation,
+		Evidence:    vulnEvidence{"json", finding.JSON},
+	}
+	for _, resource := range finding.Resources {
+		affected := vulnResource{resource.Kind, resource.Namespace, resource.Name, resource.Path, resource.String(), nil}
+		for _, match := range resource.Matches {
+			affected.Fields = append(affected.Fields, vulnField{match.Line, match.Path})
+		}
+		vuln.Resources.Resource = append(vuln.Resources.Resource, affected)
+	}
+	if len(data.References) > 0 {
+		vuln.References = &vulnReferences{data.References}
+	}
+	if len(data.Tags) > 0 {
+		vuln.Tags = &vulnTags{data.Tags}
+	}
+	if len(params) > 0 {
+		vuln.Parameters = &vulnParameters{params}
+	}
+	return vuln
+}
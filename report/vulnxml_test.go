@@ -0,0 +1,60 @@
+package report_test
+
+import (
+	"kube-review/report"
+	"strings"
+	"testing"
+)
+
+func TestVulnXMLOnlyIncludesMatchedFindings(t *testing.T) {
+	ql, nodeList := getQueryList(), getNodeList(t)
+	matched, _ := report.NewFinding(ql, "test1more", *nodeList)
+	unmatched, _ := report.NewFinding(ql, "no-match", *nodeList)
+	actual, err := report.VulnXML([]report.Finding{matched, unmatched})
+	if err != nil {
+		t.Fatalf("Expected no error but got '%s'", err.Error())
+	}
+	if strings.Count(actual, "<vulnerability ") != 1 || !strings.Contains(actual, `id="test1more"`) {
+		t.Errorf("Expected only test1more but got %s", actual)
+	}
+}
+
+func TestVulnXMLIncludesQueryMetadata(t *testing.T) {
+	finding, _ := report.NewFinding(getQueryList(), "test1more", *getNodeList(t))
+	actual, _ := report.VulnXML([]report.Finding{finding})
+	for _, expected := range []string{"<severity>Low</severity>", "<category>Test</category>",
+		"<reference>CIS 1.1.1</reference>", "<tag>regex</tag>", "<remediation>Nothing to fix</remediation>",
+		"<description>First test (REGEX)</description>", `<evidence format="json"><![CDATA[`} {
+		if !strings.Contains(actual, expected) {
+			t.Errorf("Expected '%s' in %s", expected, actual)
+		}
+	}
+}
+
+func TestVulnXMLIncludesParameterValues(t *testing.T) {
+	finding, _ := report.NewFinding(getQueryList(), "param-kind:kind=NORALEX", *getNodeList(t))
+	actual, _ := report.VulnXML([]report.Finding{finding})
+	if !strings.Contains(actual, `<parameter name="kind">NORALEX</parameter>`) {
+		t.Errorf("Expected kind parameter in %s", actual)
+	}
+}
+
+func TestVulnXMLOmitsEmptyLists(t *testing.T) {
+	finding, _ := report.NewFinding(getQueryList(), "test2less", *getNodeList(t))
+	actual, _ := report.VulnXML([]report.Finding{finding})
+	for _, unexpected := range []string{"<references>", "<tags>", "<parameters>"} {
+		if strings.Contains(actual, unexpected) {
+			t.Errorf("Expected no '%s' in %s", unexpected, actual)
+		}
+	}
+}
+
+func TestVulnXMLListsAffectedResources(t *testing.T) {
+	actual, _ := report.VulnXML([]report.Finding{getConfigMapFinding(t, "Secrets-in-ConfigMap")})
+	for _, expected := range []string{`<resource kind="ConfigMap" namespace="default" name="tls-keys" path="items[0]">`,
+		"<summary>ConfigMap default/tls-keys: 3 offending fields</summary>", `<field line="13">items[0].data.tls.key</field>`} {
+		if !strings.Contains(actual, expected) {
+			t.Errorf("Expected '%s' in %s", expected, actual)
+		}
+	}
+}
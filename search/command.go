@@ -1,7 +1,15 @@
 package search
 
 import (
+	"fmt"
+	"kube-review/certs"
+	"kube-review/images"
+	"kube-review/k8s"
+	"kube-review/netpol"
 	"kube-review/nodelist"
+	"kube-review/rbac"
+	"kube-review/secrets"
+	"kube-review/utils"
 	"regexp"
 	"sort"
 	"strconv"
@@ -26,20 +34,192 @@ func NewCommand(function CmdFunc, input map[string]string, output string, operat
 }
 
 // RunFunction stuff
-func (c Command) RunFunction(input []int, nodeList sNodeList) (string, []int) {
-	if r, matchType, equal, err := c.processBaseInputs(); err == nil {
-		if c.function == CMDFINDNODES {
-			return c.output, nodeList.GetNodesMatching(r, matchType, equal)
-		} else if c.function == CMDFINDRELATIVE {
-			var list []int
-			relativeStartLevel, depth := c.processRelativeInputs()
-			for _, index := range input {
-				list = append(list, nodeList.GetRelativesMatching(index, relativeStartLevel, depth, r, matchType, equal)...)
+func (c Command) RunFunction(input []int, nodeList sNodeList) (string, []int, error) {
+	r, matchType, equal, err := c.processBaseInputs()
+	if err != nil {
+		return "", []int{}, err
+	}
+	var output []int
+	switch c.function {
+	case CMDFINDNODES:
+		output = nodeList.GetNodesMatching(r, matchType, equal)
+	case CMDFINDRELATIVE:
+		var list []int
+		relativeStartLevel, depth := c.processRelativeInputs()
+		for _, index := range input {
+			list = append(list, nodeList.GetRelativesMatching(index, relativeStartLevel, depth, r, matchType, equal)...)
+		}
+		output = orderedUnion(list, []int{})
+	case CMDFINDRESOURCES:
+		output, err = c.findResources(nodeList)
+	case CMDWHOCAN:
+		output = c.whoCan(nodeList)
+	case CMDFINDRBAC:
+		output, err = c.findRBAC(nodeList)
+	case CMDFINDCONTAINERS:
+		output, err = c.findContainers(nodeList)
+	case CMDFINDEFFECTIVE:
+		output = c.findEffective(input, nodeList, r, equal)
+	case CMDCHECKNETWORKPOLICY:
+		output, err = c.checkNetworkPolicy(nodeList)
+	case CMDFINDRELATED:
+		output, err = c.findRelated(input, nodeList)
+	case CMDFINDSECRETS:
+		output, err = c.findSecrets(nodeList)
+	case CMDCHECKIMAGES:
+		output, err = c.checkImages(nodeList)
+	case CMDCHECKCERTIFICATES:
+		output, err = c.checkCertificates(nodeList)
+	default:
+		return "", []int{}, nil
+	}
+	if err != nil {
+		return "", []int{}, fmt.Errorf("%s - %s", c.function.String(), err.Error())
+	}
+	return c.output, output, nil
+}
+
+// findResources returns the top node of each resource matching the kind, namespace and name inputs
+func (c Command) findResources(nodeList sNodeList) ([]int, error) {
+	resources, err := k8s.NewIndex(nodeList).Find(c.input["kind"], c.input["namespace"], c.input["name"])
+	if err != nil {
+		return nil, err
+	}
+	var indices []int
+	for _, resource := range resources {
+		indices = append(indices, resource.Start)
+	}
+	return indices, nil
+}
+
+// whoCan returns the subject nodes of the bindings that grant the verb on resource in namespace
+func (c Command) whoCan(nodeList sNodeList) []int {
+	var indices []int
+	for _, permission := range rbac.New(nodeList).WhoCan(c.input["verb"], c.input["resource"], c.input["namespace"]) {
+		indices = append(indices, permission.SubjectIndex)
+	}
+	return orderedUnion(indices, []int{})
+}
+
+// findRBAC returns the rule and subject nodes of each permission flagged by the check input
+func (c Command) findRBAC(nodeList sNodeList) ([]int, error) {
+	permissions, err := rbac.New(nodeList).Detect(c.input["check"], c.input["namespace"])
+	if err != nil {
+		return nil, err
+	}
+	var indices []int
+	for _, permission := range permissions {
+		indices = append(indices, permission.RuleIndex, permission.SubjectIndex)
+	}
+	return orderedUnion(indices, []int{}), nil
+}
+
+// checkNetworkPolicy returns the namespace, pod spec or rule nodes flagged by the check input
+func (c Command) checkNetworkPolicy(nodeList sNodeList) ([]int, error) {
+	findings, err := netpol.New(nodeList).Detect(c.input["check"], c.input["namespace"])
+	if err != nil {
+		return nil, err
+	}
+	var indices []int
+	for _, finding := range findings {
+		indices = append(indices, finding.Index)
+	}
+	return orderedUnion(indices, []int{}), nil
+}
+
+// checkImages returns the image, imagePullPolicy or registry config nodes flagged by the check input
+func (c Command) checkImages(nodeList sNodeList) ([]int, error) {
+	findings, err := images.New(nodeList).Detect(c.input["check"], c.input["registries"])
+	if err != nil {
+		return nil, err
+	}
+	var indices []int
+	for _, finding := range findings {
+		indices = append(indices, finding.Index)
+	}
+	return orderedUnion(indices, []int{}), nil
+}
+
+// checkCertificates returns the string values holding a certificate flagged by the check input,
+// with expiry measured from the date input
+func (c Command) checkCertificates(nodeList sNodeList) ([]int, error) {
+	at, err := utils.ParseDate(c.input["date"])
+	if err != nil {
+		return nil, err
+	}
+	days := 0
+	if c.input["days"] != "" {
+		if days, err = strconv.Atoi(c.input["days"]); err != nil {
+			return nil, fmt.Errorf("Invalid days '%s'", c.input["days"])
+		}
+	}
+	findings, err := certs.Detect(certs.Find(nodeList), c.input["check"], at, days)
+	if err != nil {
+		return nil, err
+	}
+	var indices []int
+	for _, finding := range findings {
+		indices = append(indices, finding.Index)
+	}
+	return orderedUnion(indices, []int{}), nil
+}
+
+// findSecrets returns the string values with a credential found by the rules matching the rule
+// input of at least the confidence input
+func (c Command) findSecrets(nodeList sNodeList) ([]int, error) {
+	confidence, err := secrets.ParseConfidence(c.input["confidence"])
+	if err != nil {
+		return nil, err
+	}
+	findings, err := secrets.Find(nodeList, c.input["rule"], confidence)
+	if err != nil {
+		return nil, err
+	}
+	var indices []int
+	for _, finding := range findings {
+		indices = append(indices, finding.Index)
+	}
+	return orderedUnion(indices, []int{}), nil
+}
+
+// findContainers returns every container of the workloads whose kind matches the kind input
+func (c Command) findContainers(nodeList sNodeList) ([]int, error) {
+	kind, err := utils.CompileFullMatch(c.input["kind"])
+	if err != nil {
+		return nil, err
+	}
+	var indices []int
+	for _, container := range k8s.NewIndex(nodeList).Containers(nodeList) {
+		if kind == nil || kind.MatchString(container.Kind) {
+			indices = append(indices, container.Index)
+		}
+	}
+	return indices, nil
+}
+
+// findEffective returns the effective node at the path input of each container in input whose
+// value matches r, or if not equal those that do not match along with containers without it
+func (c Command) findEffective(input []int, nodeList sNodeList, r *regexp.Regexp, equal bool) []int {
+	containers := map[int]k8s.Container{}
+	for _, container := range k8s.NewIndex(nodeList).Containers(nodeList) {
+		containers[container.Index] = container
+	}
+	path := strings.Split(c.input["path"], ".")
+	var indices []int
+	for _, index := range input {
+		container, ok := containers[index]
+		if !ok {
+			continue
+		}
+		if effective, found := container.GetEffective(nodeList, path...); found {
+			if r.MatchString(nodeList.GetValue(effective)) == equal {
+				indices = append(indices, effective)
 			}
-			return c.output, orderedUnion(list, []int{})
+		} else if !equal {
+			indices = append(indices, index)
 		}
 	}
-	return "", []int{}
+	return orderedUnion(indices, []int{})
 }
 
 // RunOperation stuff
@@ -156,3 +336,32 @@ func orderedUnion(left, right []int) []int {
 	sort.Ints(result)
 	return result
 }
+
+// findRelated returns the top node of each resource linked to a resource in input by an edge
+// whose type matches the edge input, following edges backwards if reverse is set
+func (c Command) findRelated(input []int, nodeList sNodeList) ([]int, error) {
+	edge, err := utils.CompileFullMatch(c.input["edge"])
+	if err != nil {
+		return nil, err
+	}
+	reverse := strings.EqualFold(c.input["reverse"], "true")
+	index := k8s.NewIndex(nodeList)
+	starts := map[int]bool{}
+	for _, nodeIndex := range input {
+		if resource, ok := index.GetContaining(nodeIndex); ok {
+			starts[resource.Start] = true
+		}
+	}
+	var indices []int
+	for _, e := range index.Graph(nodeList).Edges() {
+		if edge != nil && !edge.MatchString(e.Type.String()) {
+			continue
+		}
+		if !reverse && starts[e.From.Start] {
+			indices = append(indices, e.To.Start)
+		} else if reverse && starts[e.To.Start] {
+			indices = append(indices, e.From.Start)
+		}
+	}
+	return orderedUnion(indices, []int{}), nil
+}
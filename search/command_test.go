@@ -207,8 +207,21 @@ func TestFindRelativeReturnsAnOrderedUnionOfIndices(t *testing.T) {
 	input := map[string]string{"regex": "test", "matchType": "Key"}
 	command := search.NewCommand(search.CMDFINDRELATIVE, input, "", "", "")
 	expected := []int{1, 2, 3, 4, 5, 6}
-	_, actual := command.RunFunction([]int{1, 2}, &mock)
+	_, actual, _ := command.RunFunction([]int{1, 2}, &mock)
 	if !reflect.DeepEqual(actual, expected) {
 		t.Errorf("Expected '%v' but got '%v'", expected, actual)
 	}
 }
+
+func TestRunFunctionReturnsErrorForInvalidInputs(t *testing.T) {
+	for _, input := range []map[string]string{
+		{"check": "expired", "date": "15/10/2026", "days": "0"},
+		{"check": "expiring", "date": "now", "days": "soon"},
+		{"check": "unknown", "date": "now", "days": "0"},
+	} {
+		command := search.NewCommand(search.CMDCHECKCERTIFICATES, input, "", "", "")
+		if _, _, err := command.RunFunction([]int{}, &mocks.NodeListMock{}); err == nil {
+			t.Errorf("Expected an error for %v but instead got nothing", input)
+		}
+	}
+}
@@ -63,41 +63,46 @@ func NewExpression(input string) (Expression, error) {
 }
 
 // Execute stuff
-func (e Expression) Execute(nodeList sNodeList) []int {
+func (e Expression) Execute(nodeList sNodeList) ([]int, error) {
 	return e.executeCommands(nodeList)
 }
 
-func (e *Expression) executeCommands(nodeList sNodeList) []int {
+func (e *Expression) executeCommands(nodeList sNodeList) ([]int, error) {
 	var currentIndices []int
 	for index := e.cmdIndex; index < len(e.commands); index++ {
 		command := e.commands[index]
 		input := e.variables[command.GetInputName()]
-		outName, output := command.RunFunction(input, nodeList)
+		outName, output, err := command.RunFunction(input, nodeList)
+		if err != nil {
+			return nil, err
+		}
 		if outName != "" {
 			e.variables[outName] = output
 		}
 		if command.HasOpenBracket() {
 			e.cmdIndex = index + 1
-			output = e.executeCommands(nodeList)
+			if output, err = e.executeCommands(nodeList); err != nil {
+				return nil, err
+			}
 			index = e.cmdIndex
 		}
 		currentIndices = command.RunOperation(currentIndices, output)
 		if command.HasCloseBracket() {
 			e.cmdIndex = index
-			return currentIndices
+			return currentIndices, nil
 		}
 	}
-	return currentIndices
+	return currentIndices, nil
 }
 
 func getFunctionType(input string) (CmdFunc, []string) {
 	bracketIndex := strings.LastIndex(input, "(")
 	if bracketIndex > 0 && bracketIndex > strings.LastIndex(input, ")") && unicode.IsLetter(rune(input[bracketIndex-1])) {
 		arguments := strings.Split(input[bracketIndex+1:], ",")
-		if match, _ := regexp.Match("(?i)"+CMDFINDNODES.String()+"$", []byte(input[:bracketIndex])); match {
-			return CMDFINDNODES, arguments
-		} else if match, _ := regexp.Match("(?i)"+CMDFINDRELATIVE.String()+"$", []byte(input[:bracketIndex])); match {
-			return CMDFINDRELATIVE, arguments
+		for _, function := range cmdFuncs {
+			if match, _ := regexp.Match("(?i)"+function.String()+"$", []byte(input[:bracketIndex])); match {
+				return function, arguments
+			}
 		}
 	}
 	return CMDNULL, []string{}
@@ -122,11 +127,10 @@ func getBaseFuntionHints(input string) []string {
 		return (c <= 97 || c >= 122) && (c <= 65 || c >= 90)
 	})
 	var output []string
-	if match, _ := regexp.Match("(?i)"+strippedInput, []byte(CMDFINDNODES.String())); match {
-		output = append(output, getFunctionHint(CMDFINDNODES, -1))
-	}
-	if match, _ := regexp.Match("(?i)"+strippedInput, []byte(CMDFINDRELATIVE.String())); match {
-		output = append(output, getFunctionHint(CMDFINDRELATIVE, -1))
+	for _, function := range cmdFuncs {
+		if match, _ := regexp.Match("(?i)"+strippedInput, []byte(function.String())); match {
+			output = append(output, getFunctionHint(function, -1))
+		}
 	}
 	return output
 }
@@ -2,6 +2,7 @@ package search_test
 
 import (
 	"kube-review/mocks"
+	"kube-review/nodelist"
 	"kube-review/search"
 	"reflect"
 	"testing"
@@ -34,7 +35,7 @@ func TestComplexExpressionReturnsCorrectOutput(t *testing.T) {
 	expression, _ := search.NewExpression("FindNodes(\"test\", output=nodes) + FindRelative(nodes, \"test\")")
 	mock := mocks.NodeListMock{}
 	mock.Returns = [][]int{[]int{1, 5}, []int{1, 2}, []int{3, 6}}
-	actual := expression.Execute(&mock)
+	actual, _ := expression.Execute(&mock)
 	expected := []int{1, 2, 3, 5, 6}
 	if !reflect.DeepEqual(actual, expected) {
 		t.Errorf("Expected '%v' but got '%v'", expected, actual)
@@ -45,7 +46,7 @@ func TestBracketExpressionReturnsCorrectOutput(t *testing.T) {
 	expression, _ := search.NewExpression("FindNodes(\"test\") - (FindNodes(\"test\") + FindNodes(\"test\"))")
 	mock := mocks.NodeListMock{}
 	mock.Returns = [][]int{[]int{1, 2, 3, 4, 5}, []int{1, 2}, []int{3, 6}}
-	actual := expression.Execute(&mock)
+	actual, _ := expression.Execute(&mock)
 	expected := []int{4, 5}
 	if !reflect.DeepEqual(actual, expected) {
 		t.Errorf("Expected '%v' but got '%v'", expected, actual)
@@ -54,7 +55,10 @@ func TestBracketExpressionReturnsCorrectOutput(t *testing.T) {
 
 func TestHintsReturnFunctionSignatures(t *testing.T) {
 	actual := search.GetExpressionHints("")
-	expected := []string{"FindNodes(regex, matchType, equal, output)", "FindRelative(nodes, regex, relativeStart, depth, matchType, equal, output)"}
+	expected := []string{"FindNodes(regex, matchType, equal, output)", "FindRelative(nodes, regex, relativeStart, depth, matchType, equal, output)",
+		"FindResources(kind, namespace, name, output)", "WhoCan(verb, resource, namespace, output)",
+		"FindRBAC(check, namespace, output)", "FindContainers(kind, output)",
+		"FindEffective(nodes, path, regex, equal, output)", "CheckNetworkPolicy(check, namespace, output)", "FindRelated(nodes, edge, reverse, output)", "FindSecrets(rule, confidence, output)", "CheckImages(check, registries, output)", "CheckCertificates(check, date, days, output)"}
 	if !reflect.DeepEqual(actual, expected) {
 		t.Errorf("Expected '%v' but got '%v'", expected, actual)
 	}
@@ -166,7 +170,10 @@ func TestInsertHintsInsertCorrectFunctionUpToBracket(t *testing.T) {
 
 func TestGetHintsPreviousFunctions(t *testing.T) {
 	actual := search.GetExpressionHints("FindNodes(\"test\") + ")
-	expected := []string{"FindNodes(regex, matchType, equal, output)", "FindRelative(nodes, regex, relativeStart, depth, matchType, equal, output)"}
+	expected := []string{"FindNodes(regex, matchType, equal, output)", "FindRelative(nodes, regex, relativeStart, depth, matchType, equal, output)",
+		"FindResources(kind, namespace, name, output)", "WhoCan(verb, resource, namespace, output)",
+		"FindRBAC(check, namespace, output)", "FindContainers(kind, output)",
+		"FindEffective(nodes, path, regex, equal, output)", "CheckNetworkPolicy(check, namespace, output)", "FindRelated(nodes, edge, reverse, output)", "FindSecrets(rule, confidence, output)", "CheckImages(check, registries, output)", "CheckCertificates(check, date, days, output)"}
 	if !reflect.DeepEqual(actual, expected) {
 		t.Errorf("Expected '%v' but got '%v'", expected, actual)
 	}
@@ -179,3 +186,100 @@ func TestCanInsertSecondFunction(t *testing.T) {
 		t.Errorf("Expected '%s' but got '%s'", expected, actual)
 	}
 }
+
+func TestFindResourcesCanBeCombinedWithFindRelative(t *testing.T) {
+	jsonData := `{"items": [
+		{"kind": "ConfigMap", "metadata": {"name": "a", "namespace": "default"}, "data": {"key": "secret"}},
+		{"kind": "Secret", "metadata": {"name": "b", "namespace": "default"}, "data": {"key": "secret"}},
+		{"kind": "ConfigMap", "metadata": {"name": "c", "namespace": "other"}, "data": {"key": "secret"}}
+	]}`
+	nodeList, _ := nodelist.NewNodeList([]byte(jsonData), true)
+	expression, err := search.NewExpression("FindResources(\"ConfigMap\", namespace=\"default\", output=cm) -> FindRelative(cm, \"secret\", 0, 2, VALUE)")
+	if err != nil {
+		t.Fatalf("Expected no error but got '%s'", err.Error())
+	}
+	var actual []string
+	indices, _ := expression.Execute(&nodeList)
+	for _, index := range indices {
+		actual = append(actual, nodeList.GetPath(index))
+	}
+	expected := []string{"items[0].data.key"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected %v but got %v", expected, actual)
+	}
+}
+
+func TestWhoCanReturnsSubjectsOfGrantingBindings(t *testing.T) {
+	jsonData := `{"items": [
+		{"kind": "Role", "metadata": {"name": "reader", "namespace": "prod"},
+			"rules": [{"apiGroups": [""], "resources": ["secrets"], "verbs": ["get"]}]},
+		{"kind": "RoleBinding", "metadata": {"name": "readers", "namespace": "prod"},
+			"roleRef": {"kind": "Role", "name": "reader"}, "subjects": [{"kind": "User", "name": "alice"}]}
+	]}`
+	nodeList, _ := nodelist.NewNodeList([]byte(jsonData), true)
+	expression, err := search.NewExpression("WhoCan(\"get\", \"secrets\", namespace=\"prod\")")
+	if err != nil {
+		t.Fatalf("Expected no error but got '%s'", err.Error())
+	}
+	var actual []string
+	indices, _ := expression.Execute(&nodeList)
+	for _, index := range indices {
+		actual = append(actual, nodeList.GetPath(index))
+	}
+	expected := []string{"items[1].subjects[0]"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected %v but got %v", expected, actual)
+	}
+}
+
+func TestFindEffectiveChecksContainersOfEveryKind(t *testing.T) {
+	jsonData := `{"items": [
+		{"kind": "Pod", "metadata": {"name": "a"}, "spec": {"containers": [{"name": "a", "securityContext": {"runAsNonRoot": true}}]}},
+		{"kind": "Deployment", "metadata": {"name": "b"}, "spec": {"template": {"spec": {
+			"securityContext": {"runAsNonRoot": false}, "initContainers": [{"name": "init"}], "containers": [{"name": "b"}]}}}},
+		{"kind": "CronJob", "metadata": {"name": "c"}, "spec": {"jobTemplate": {"spec": {"template": {"spec": {
+			"containers": [{"name": "c"}]}}}}}}
+	]}`
+	nodeList, _ := nodelist.NewNodeList([]byte(jsonData), true)
+	expression, err := search.NewExpression("FindContainers(\"\", c) -> FindEffective(c, \"securityContext.runAsNonRoot\", \"true\", false)")
+	if err != nil {
+		t.Fatalf("Expected no error but got '%s'", err.Error())
+	}
+	var actual []string
+	indices, _ := expression.Execute(&nodeList)
+	for _, index := range indices {
+		actual = append(actual, nodeList.GetPath(index))
+	}
+	expected := []string{"items[1].spec.template.spec.securityContext.runAsNonRoot",
+		"items[2].spec.jobTemplate.spec.template.spec.containers[0]"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected %v but got %v", expected, actual)
+	}
+}
+
+func TestFindRelatedFollowsEdgesBothWays(t *testing.T) {
+	jsonData := `{"items": [
+		{"kind": "Deployment", "metadata": {"name": "web", "namespace": "prod"}, "spec": {
+			"template": {"metadata": {"labels": {"app": "web"}}, "spec": {"containers": [{"name": "web"}]}}}},
+		{"kind": "Pod", "metadata": {"name": "debug", "namespace": "prod", "labels": {"app": "web"}}, "spec": {"containers": []}},
+		{"kind": "Service", "metadata": {"name": "web", "namespace": "prod"}, "spec": {"selector": {"app": "web"}}}
+	]}`
+	nodeList, _ := nodelist.NewNodeList([]byte(jsonData), true)
+	for query, expected := range map[string][]string{
+		"FindResources(\"Service\", \"\", \"\", s) -> FindRelated(s, \"selects\", false)": []string{"items[0]", "items[1]"},
+		"FindNodes(\"web\", value, true, w) -> FindRelated(w, \"\", true)":                []string{"items[2]"},
+	} {
+		expression, err := search.NewExpression(query)
+		if err != nil {
+			t.Fatalf("Expected no error but got '%s'", err.Error())
+		}
+		var actual []string
+		indices, _ := expression.Execute(&nodeList)
+		for _, index := range indices {
+			actual = append(actual, nodeList.GetPath(index))
+		}
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("Expected %v for %s but got %v", expected, query, actual)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package search
+
+import (
+	"kube-review/utils"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// HistoryEntry is a previously executed search along with the modes it was run in
+type HistoryEntry struct {
+	Input        string       `json:"input"`
+	QueryMode    QueryEnum    `json:"queryMode"`
+	FunctionMode FunctionEnum `json:"functionMode"`
+}
+
+func (he HistoryEntry) String() string {
+	return he.QueryMode.String() + "-" + he.FunctionMode.String() + ": " + he.Input
+}
+
+// History keeps a list of executed searches that can be recalled and is
+// persisted to filename so it survives across sessions
+type History struct {
+	entries  []HistoryEntry
+	position int
+	filename string
+	maxSize  int
+}
+
+// NewHistory creates an empty history. If filename is "" the history will not
+// be saved. maxSize limits the number of entries kept, dropping the oldest first
+func NewHistory(filename string, maxSize int) History {
+	return History{[]HistoryEntry{}, 0, filename, maxSize}
+}
+
+// Load reads the history from file. A missing file is not treated as an error
+func (h *History) Load() error {
+	if h.filename == "" {
+		return nil
+	}
+	var entries []HistoryEntry
+	if err := utils.LoadJSON(h.filename, &entries, ""); err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	h.entries = entries
+	h.trim()
+	h.ResetPosition()
+	return nil
+}
+
+// Save writes the history to file, creating its directory if required
+func (h History) Save() error {
+	if h.filename == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(h.filename), 0700); err != nil {
+		return err
+	}
+	return utils.SaveJSON(h.filename, h.entries, true)
+}
+
+// Add appends entry to the history and saves it. Empty inputs and repeats of
+// the most recent entry are ignored. The recall position is reset
+func (h *History) Add(entry HistoryEntry) error {
+	defer h.ResetPosition()
+	if strings.Trim(entry.Input, " ") == "" {
+		return nil
+	}
+	if len(h.entries) > 0 && h.entries[len(h.entries)-1] == entry {
+		return nil
+	}
+	h.entries = append(h.entries, entry)
+	h.trim()
+	return h.Save()
+}
+
+// Previous steps back through the history, returning false if there are no older entries
+func (h *History) Previous() (HistoryEntry, bool) {
+	if h.position == 0 {
+		return HistoryEntry{}, false
+	}
+	h.position--
+	return h.entries[h.position], true
+}
+
+// Next steps forward through the history. Once past the most recent entry it
+// returns an empty entry and false, which should be treated as a fresh input
+func (h *History) Next() (HistoryEntry, bool) {
+	if h.position >= len(h.entries)-1 {
+		h.ResetPosition()
+		return HistoryEntry{}, false
+	}
+	h.position++
+	return h.entries[h.position], true
+}
+
+// IsRecalling returns true if Previous has been used since the last reset
+func (h History) IsRecalling() bool {
+	return h.position < len(h.entries)
+}
+
+// ResetPosition moves the recall position back past the most recent entry
+func (h *History) ResetPosition() {
+	h.position = len(h.entries)
+}
+
+// Find returns entries containing pattern (case insensitive), most recent first.
+// Duplicate entries are only returned once and num limits the number returned (-1 for no limit)
+func (h History) Find(pattern string, num int) []HistoryEntry {
+	var matches []HistoryEntry
+	seen := map[HistoryEntry]struct{}{}
+	lowerPattern := strings.ToLower(pattern)
+	for index := len(h.entries) - 1; index >= 0 && num != 0; index-- {
+		entry := h.entries[index]
+		if _, ok := seen[entry]; ok {
+			continue
+		}
+		if strings.Contains(strings.ToLower(entry.Input), lowerPattern) {
+			seen[entry] = struct{}{}
+			matches = append(matches, entry)
+			num--
+		}
+	}
+	return matches
+}
+
+// Size returns the number of entries in the history
+func (h History) Size() int {
+	return len(h.entries)
+}
+
+func (h *History) trim() {
+	if h.maxSize > 0 && len(h.entries) > h.maxSize {
+		h.entries = h.entries[len(h.entries)-h.maxSize:]
+	}
+}
@@ -0,0 +1,120 @@
+package search_test
+
+import (
+	"io/ioutil"
+	"kube-review/search"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func getHistory(inputs ...string) search.History {
+	h := search.NewHistory("", 0)
+	for _, input := range inputs {
+		h.Add(search.HistoryEntry{Input: input, QueryMode: search.REGEX, FunctionMode: search.FIND})
+	}
+	return h
+}
+
+func TestPreviousReturnsMostRecentEntryFirst(t *testing.T) {
+	h := getHistory("first", "second")
+	actual, ok := h.Previous()
+	if !ok || actual.Input != "second" {
+		t.Errorf("Expected 'second' but got '%s'", actual.Input)
+	}
+}
+
+func TestPreviousReturnsFalseWhenNoOlderEntries(t *testing.T) {
+	h := getHistory("first")
+	h.Previous()
+	if _, ok := h.Previous(); ok {
+		t.Errorf("Expected no more entries but got one")
+	}
+}
+
+func TestNextReturnsEmptyEntryAfterMostRecent(t *testing.T) {
+	h := getHistory("first", "second")
+	h.Previous()
+	h.Previous()
+	if actual, _ := h.Next(); actual.Input != "second" {
+		t.Errorf("Expected 'second' but got '%s'", actual.Input)
+	}
+	if actual, ok := h.Next(); ok || actual.Input != "" {
+		t.Errorf("Expected empty entry but got '%s'", actual.Input)
+	}
+	if h.IsRecalling() {
+		t.Errorf("Expected recall to have been reset")
+	}
+}
+
+func TestAddIgnoresEmptyAndRepeatedEntries(t *testing.T) {
+	h := getHistory("first", "first", " ", "second", "first")
+	if h.Size() != 3 {
+		t.Errorf("Expected 3 entries but got %d", h.Size())
+	}
+}
+
+func TestAddDropsOldestEntriesPastMaxSize(t *testing.T) {
+	h := search.NewHistory("", 2)
+	for _, input := range []string{"first", "second", "third"} {
+		h.Add(search.HistoryEntry{Input: input})
+	}
+	h.Previous()
+	h.Previous()
+	if _, ok := h.Previous(); ok || h.Size() != 2 {
+		t.Errorf("Expected 2 entries but got %d", h.Size())
+	}
+}
+
+func TestFindReturnsUniqueMatchesMostRecentFirst(t *testing.T) {
+	h := getHistory("FindNodes(\"a\")", "other", "FindNodes(\"b\")", "FindNodes(\"a\")")
+	actual := h.Find("findnodes", -1)
+	expected := []search.HistoryEntry{
+		search.HistoryEntry{Input: "FindNodes(\"a\")"},
+		search.HistoryEntry{Input: "FindNodes(\"b\")"},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected %v but got %v", expected, actual)
+	}
+}
+
+func TestFindLimitsNumberOfMatches(t *testing.T) {
+	h := getHistory("one", "two", "three")
+	if actual := h.Find("", 2); len(actual) != 2 {
+		t.Errorf("Expected 2 matches but got %d", len(actual))
+	}
+}
+
+func TestHistoryIsPersistedWithModes(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "kube-review")
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "config", "history.json")
+	h := search.NewHistory(filename, 0)
+	expected := search.HistoryEntry{Input: "FindNodes(\"a\")", QueryMode: search.EXPRESSION, FunctionMode: search.FILTER}
+	if err := h.Add(expected); err != nil {
+		t.Fatalf("Expected no error but got '%s'", err.Error())
+	}
+
+	loaded := search.NewHistory(filename, 0)
+	loaded.Load()
+	if actual, _ := loaded.Previous(); actual != expected {
+		t.Errorf("Expected %v but got %v", expected, actual)
+	}
+}
+
+func TestLoadIgnoresMissingFile(t *testing.T) {
+	h := search.NewHistory("nofile", 0)
+	if err := h.Load(); err != nil {
+		t.Errorf("Expected no error but got '%s'", err.Error())
+	}
+}
+
+func TestNewHistoryEntryUsesCurrentModes(t *testing.T) {
+	s := search.NewSearch(search.EXPRESSION, getQueryList())
+	s.ToggleSearchMode()
+	expected := search.HistoryEntry{Input: "test", QueryMode: search.EXPRESSION, FunctionMode: search.FILTER}
+	if actual := s.NewHistoryEntry("test"); actual != expected {
+		t.Errorf("Expected %v but got %v", expected, actual)
+	}
+}
@@ -0,0 +1,147 @@
+package search
+
+import (
+	"fmt"
+	"kube-review/utils"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// QueryParameter declares an argument that is substituted into a query wherever
+// {{name}} appears. Type is one of string, regex, int, bool or date. String values are
+// escaped so they match literally, whereas regex values are inserted as they are. Dates
+// are YYYY-MM-DD, RFC3339 or now
+type QueryParameter struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Default     string `json:"default,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+func (qp QueryParameter) String() string {
+	out := qp.Name + " (" + qp.Type + ")"
+	if qp.Default != "" {
+		out += " = " + qp.Default
+	}
+	if qp.Description != "" {
+		out += " - " + qp.Description
+	}
+	return out
+}
+
+// expressionReserved are characters that would end an argument early when parsing an expression
+const expressionReserved = "\"),"
+
+var argNameRegex = regexp.MustCompile(`^\s*[A-Za-z_][A-Za-z0-9_]*\s*=`)
+
+// ParseQueryArgs splits a query input of the form "name:param=value,param=value" into the
+// query name and its arguments. Values may contain commas as long as they are not
+// followed by another "param="
+func ParseQueryArgs(input string) (string, map[string]string, error) {
+	args := map[string]string{}
+	split := strings.SplitN(input, ":", 2)
+	name := strings.Trim(split[0], " ")
+	if len(split) == 1 || strings.Trim(split[1], " ") == "" {
+		return name, args, nil
+	}
+
+	var pairs []string
+	for _, part := range strings.Split(split[1], ",") {
+		if argNameRegex.MatchString(part) || len(pairs) == 0 {
+			pairs = append(pairs, part)
+		} else {
+			pairs[len(pairs)-1] += "," + part
+		}
+	}
+	for _, pair := range pairs {
+		keyValue := strings.SplitN(pair, "=", 2)
+		if len(keyValue) != 2 {
+			return name, args, fmt.Errorf("Query argument '%s' should be of the form param=value", pair)
+		}
+		args[strings.Trim(keyValue[0], " ")] = keyValue[1]
+	}
+	return name, args, nil
+}
+
+// FormatQueryArgs is the reverse of ParseQueryArgs
+func FormatQueryArgs(name string, args map[string]string) string {
+	if len(args) == 0 {
+		return name
+	}
+	var keys []string
+	for key := range args {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var pairs []string
+	for _, key := range keys {
+		pairs = append(pairs, key+"="+args[key])
+	}
+	return name + ":" + strings.Join(pairs, ",")
+}
+
+// Substitute returns the query with each parameter replaced by its value in args,
+// or its default if not provided. Arguments are validated against the parameter type
+func (qd QueryData) Substitute(args map[string]string) (string, error) {
+	query := qd.Query
+	for name := range args {
+		if _, ok := qd.getParameter(name); !ok {
+			return "", fmt.Errorf("'%s' is not a parameter of this query", name)
+		}
+	}
+	for _, param := range qd.Parameters {
+		value, ok := args[param.Name]
+		if !ok {
+			if param.Default == "" {
+				return "", fmt.Errorf("No value provided for parameter '%s'", param.Name)
+			}
+			value = param.Default
+		}
+		converted, err := param.convert(value)
+		if err != nil {
+			return "", err
+		}
+		if qd.QueryType == EXPRESSION && strings.ContainsAny(converted, expressionReserved) {
+			return "", fmt.Errorf("Value for '%s' cannot contain any of '%s' in an expression", param.Name, expressionReserved)
+		}
+		query = strings.ReplaceAll(query, "{{"+param.Name+"}}", converted)
+	}
+	return query, nil
+}
+
+func (qd QueryData) getParameter(name string) (QueryParameter, bool) {
+	for _, param := range qd.Parameters {
+		if param.Name == name {
+			return param, true
+		}
+	}
+	return QueryParameter{}, false
+}
+
+func (qp QueryParameter) convert(value string) (string, error) {
+	switch qp.Type {
+	case "string":
+		return regexp.QuoteMeta(value), nil
+	case "regex":
+		if _, err := regexp.Compile(value); err != nil {
+			return "", fmt.Errorf("Value for '%s' is not a valid regex - %s", qp.Name, err.Error())
+		}
+	case "int":
+		if _, err := strconv.Atoi(value); err != nil {
+			return "", fmt.Errorf("Value for '%s' must be an int", qp.Name)
+		}
+	case "date":
+		if _, err := utils.ParseDate(value); err != nil {
+			return "", fmt.Errorf("Value for '%s' must be now, YYYY-MM-DD or RFC3339", qp.Name)
+		}
+	case "bool":
+		if !strings.EqualFold(value, "true") && !strings.EqualFold(value, "false") {
+			return "", fmt.Errorf("Value for '%s' must be true or false", qp.Name)
+		}
+	default:
+		return "", fmt.Errorf("Invalid parameter type: '%s'", qp.Type)
+	}
+	return value, nil
+}
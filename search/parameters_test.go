@@ -0,0 +1,126 @@
+package search_test
+
+import (
+	"kube-review/search"
+	"reflect"
+	"testing"
+)
+
+var testParameters = search.QueryData{
+	Query:     "FindNodes(\"{{pattern}}\", output=a) -> FindRelative(a, \"{{kind}}\", {{level}}, 2)",
+	QueryType: search.EXPRESSION,
+	Parameters: []search.QueryParameter{
+		search.QueryParameter{Name: "pattern", Type: "regex", Default: "[0-9]+"},
+		search.QueryParameter{Name: "kind", Type: "string"},
+		search.QueryParameter{Name: "level", Type: "int", Default: "1"},
+	},
+}
+
+func TestParseQueryArgsReturnsNameOnlyWithoutArguments(t *testing.T) {
+	name, args, err := search.ParseQueryArgs("test1more")
+	if err != nil || name != "test1more" || len(args) != 0 {
+		t.Errorf("Expected 'test1more' and no args but got '%s' and %v", name, args)
+	}
+}
+
+func TestParseQueryArgsAllowsCommasInValues(t *testing.T) {
+	_, actual, _ := search.ParseQueryArgs("test:pattern=[a-z]{1,3},kind=Pod")
+	expected := map[string]string{"pattern": "[a-z]{1,3}", "kind": "Pod"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected %v but got %v", expected, actual)
+	}
+}
+
+func TestParseQueryArgsReturnsErrorForMissingValue(t *testing.T) {
+	if _, _, err := search.ParseQueryArgs("test:pattern"); err == nil {
+		t.Errorf("Expected an error but got nothing")
+	}
+}
+
+func TestFormatQueryArgsIsReverseOfParse(t *testing.T) {
+	expected := "test:kind=Pod,pattern=[a-z]{1,3}"
+	name, args, _ := search.ParseQueryArgs(expected)
+	if actual := search.FormatQueryArgs(name, args); actual != expected {
+		t.Errorf("Expected '%s' but got '%s'", expected, actual)
+	}
+}
+
+func TestSubstituteUsesArgumentsAndDefaults(t *testing.T) {
+	actual, err := testParameters.Substitute(map[string]string{"kind": "Config.Map", "level": "2"})
+	expected := "FindNodes(\"[0-9]+\", output=a) -> FindRelative(a, \"Config\\.Map\", 2, 2)"
+	if err != nil || actual != expected {
+		t.Errorf("Expected '%s' but got '%s' (%v)", expected, actual, err)
+	}
+}
+
+func TestSubstituteReturnsErrorForMissingArgumentWithoutDefault(t *testing.T) {
+	if _, err := testParameters.Substitute(map[string]string{}); err == nil {
+		t.Errorf("Expected an error but got nothing")
+	}
+}
+
+func TestSubstituteReturnsErrorForUnknownArgument(t *testing.T) {
+	if _, err := testParameters.Substitute(map[string]string{"kind": "Pod", "other": "a"}); err == nil {
+		t.Errorf("Expected an error but got nothing")
+	}
+}
+
+func TestSubstituteValidatesArgumentTypes(t *testing.T) {
+	invalid := []map[string]string{
+		map[string]string{"kind": "Pod", "level": "one"},
+		map[string]string{"kind": "Pod", "pattern": "*"},
+		map[string]string{"kind": "\"Pod\""},
+		map[string]string{"kind": "Pod", "pattern": "(a|b)"},
+	}
+	for _, args := range invalid {
+		if _, err := testParameters.Substitute(args); err == nil {
+			t.Errorf("Expected an error for %v but got nothing", args)
+		}
+	}
+}
+
+func TestSearchGetQuerySubstitutesArguments(t *testing.T) {
+	ql := getPackQueryList()
+	s := search.NewSearch(search.QUERY, &ql)
+	actual, _, err := s.GetQuery("param-kind:kind=Pod")
+	expected := "FindNodes(\"^Pod$\", value, output=kinds) -> FindRelative(kinds, \"name\", 1, 2, KEY)"
+	if err != nil || actual != expected {
+		t.Errorf("Expected '%s' but got '%s' (%v)", expected, actual, err)
+	}
+}
+
+func TestGetMissingParametersOnlyReturnsParametersWithoutArguments(t *testing.T) {
+	ql := getPackQueryList()
+	s := search.NewSearch(search.QUERY, &ql)
+	if actual := s.GetMissingParameters("param-kind"); len(actual) != 1 {
+		t.Errorf("Expected 1 parameter but got %v", actual)
+	}
+	if actual := s.GetMissingParameters("param-kind:kind=Pod"); len(actual) != 0 {
+		t.Errorf("Expected no parameters but got %v", actual)
+	}
+}
+
+func TestQueryHintsShowParametersOnceArgumentsStarted(t *testing.T) {
+	ql := getPackQueryList()
+	actual := ql.GetHints("param-kind:")
+	expected := []string{"kind (string) - kind of resource"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected %v but got %v", expected, actual)
+	}
+}
+
+func TestSubstituteValidatesDates(t *testing.T) {
+	data := search.QueryData{
+		Query:      "CheckCertificates(\"expired\", \"{{date}}\", 0)",
+		QueryType:  search.EXPRESSION,
+		Parameters: []search.QueryParameter{search.QueryParameter{Name: "date", Type: "date", Default: "now"}},
+	}
+	for _, date := range []string{"now", "2026-01-01", "2026-01-01T12:00:00Z"} {
+		if _, err := data.Substitute(map[string]string{"date": date}); err != nil {
+			t.Errorf("Expected '%s' to be a valid date but got %s", date, err.Error())
+		}
+	}
+	if _, err := data.Substitute(map[string]string{"date": "01/01/2026"}); err == nil {
+		t.Errorf("Expected an error but got nothing")
+	}
+}
@@ -2,6 +2,12 @@ package search
 
 import (
 	"fmt"
+	"kube-review/certs"
+	"kube-review/images"
+	"kube-review/netpol"
+	"kube-review/rbac"
+	"kube-review/secrets"
+	"kube-review/utils"
 	"regexp"
 	"strconv"
 	"strings"
@@ -93,22 +99,15 @@ func (p *parser) checkBrackets(bracket string) int {
 }
 
 func (p *parser) checkFunction() error {
-	if strings.EqualFold(p.getNextSlice(13), CMDFINDRELATIVE.String()+"(") {
-		p.stripLeft(13)
-		p.currentCommand.function = CMDFINDRELATIVE
-		if err := p.parseArguments(p.getNextSlice(-1), findRelArgs); err != nil {
-			return err
-		}
-	} else if strings.EqualFold(p.getNextSlice(10), CMDFINDNODES.String()+"(") {
-		p.stripLeft(10)
-		p.currentCommand.function = CMDFINDNODES
-		if err := p.parseArguments(p.getNextSlice(-1), findArgs); err != nil {
-			return err
+	for _, function := range cmdFuncs {
+		name := function.String() + "("
+		if strings.EqualFold(p.getNextSlice(len(name)), name) {
+			p.stripLeft(len(name))
+			p.currentCommand.function = function
+			return p.parseArguments(p.getNextSlice(-1), function.template())
 		}
-	} else {
-		return fmt.Errorf("Invalid function name")
 	}
-	return nil
+	return fmt.Errorf("Invalid function name")
 }
 
 func (p *parser) parseArguments(args string, template []argTemplate) error {
@@ -119,6 +118,7 @@ func (p *parser) parseArguments(args string, template []argTemplate) error {
 		for index, arg := range strings.Split(arguments[0], ",") {
 			var name string
 			var finalArg string
+			var argType string
 			arg := strings.Trim(arg, " ")
 			if arg != "" {
 				// Ensures the check for "=" is outside a regex
@@ -127,7 +127,6 @@ func (p *parser) parseArguments(args string, template []argTemplate) error {
 					split := strings.Split(arg, "=")
 					name = strings.Trim(split[0], " ")
 					finalArg = strings.Trim(split[1], " ")
-					var argType string
 					for _, argTemp := range template {
 						if strings.EqualFold(argTemp.name, name) {
 							argType = argTemp.argType
@@ -141,6 +140,7 @@ func (p *parser) parseArguments(args string, template []argTemplate) error {
 						return err
 					}
 					name = template[index].name
+					argType = template[index].argType
 					finalArg = arg
 				} else {
 					return fmt.Errorf("Not allowed a normal argument after a keyword argument")
@@ -151,7 +151,7 @@ func (p *parser) parseArguments(args string, template []argTemplate) error {
 			if name == "output" {
 				p.currentCommand.output = finalArg
 			} else {
-				if name == "regex" {
+				if _, ok := quotedArgs[argType]; ok {
 					finalArg = strings.Trim(finalArg, "\"")
 				}
 				argMap[name] = finalArg
@@ -165,17 +165,39 @@ func (p *parser) parseArguments(args string, template []argTemplate) error {
 	return fmt.Errorf("No close bracket for function")
 }
 
+// quotedArg is an argument type given as a quoted string. Validate checks the unquoted value
+// and is nil if any value is allowed
+type quotedArg struct {
+	name     string
+	validate func(value string) error
+}
+
+var quotedArgs = map[string]quotedArg{
+	"regex":       {"Regex", func(value string) error { _, err := regexp.Compile(value); return err }},
+	"string":      {"String", nil},
+	"rbacCheck":   {"RBAC check", func(value string) error { _, err := rbac.GetCheck(value); return err }},
+	"netpolCheck": {"NetworkPolicy check", func(value string) error { _, err := netpol.GetCheck(value); return err }},
+	"imageCheck":  {"Image check", func(value string) error { _, err := images.GetCheck(value); return err }},
+	"certCheck":   {"Certificate check", func(value string) error { _, err := certs.GetCheck(value); return err }},
+	"date":        {"Date", func(value string) error { _, err := utils.ParseDate(value); return err }},
+	"confidence":  {"Confidence", func(value string) error { _, err := secrets.ParseConfidence(value); return err }},
+}
+
+func isQuoted(argument string) bool {
+	return len(argument) >= 2 && argument[0] == '"' && argument[len(argument)-1] == '"'
+}
+
 func (p *parser) validateArgument(argument, argType string) error {
-	switch argType {
-	case "regex":
-		if (argument)[0] == '"' && (argument)[len(argument)-1] == '"' {
-			argument := strings.Trim(argument, "\"")
-			if _, err := regexp.Compile(argument); err != nil {
-				return err
-			}
-		} else {
-			return fmt.Errorf("Regex has not been quoted")
+	if quoted, ok := quotedArgs[argType]; ok {
+		if !isQuoted(argument) {
+			return fmt.Errorf("%s has not been quoted", quoted.name)
 		}
+		if quoted.validate != nil {
+			return quoted.validate(strings.Trim(argument, "\""))
+		}
+		return nil
+	}
+	switch argType {
 	case "MatchType":
 		if !strings.EqualFold(argument, "Any") && !strings.EqualFold(argument, "Key") && !strings.EqualFold(argument, "Value") {
 			return fmt.Errorf("MatchType invalid")
@@ -175,3 +175,23 @@ func TestComplexCommandCorrectlyParsed(t *testing.T) {
 		}
 	}
 }
+
+func TestParseFindResourcesWithKeywordArguments(t *testing.T) {
+	actual, err := search.Parse("FindResources(kind=\"Deployment\", namespace=\"kube-.*\", output=deps)")
+	expected := []search.Command{search.NewCommand(search.CMDFINDRESOURCES, map[string]string{"kind": "Deployment", "namespace": "kube-.*"}, "deps", "", "")}
+	if err != nil || !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected %v but got %v, %v", expected, actual, err)
+	}
+}
+
+func TestParseFindResourcesRequiresQuotedRegex(t *testing.T) {
+	if _, actual := search.Parse("FindResources(kind=Deployment)"); actual == nil {
+		t.Error("Expected error but got nothing")
+	}
+}
+
+func TestParseWhoCanRequiresQuotedStrings(t *testing.T) {
+	if _, actual := search.Parse("WhoCan(get, \"secrets\")"); actual == nil {
+		t.Errorf("Expected error but got nil")
+	}
+}
@@ -1,28 +1,63 @@
 package search
 
 import (
+	// embed is required for the default query list and schema
+	_ "embed"
 	"fmt"
+	"io/ioutil"
 	"kube-review/utils"
+	"os"
+	"path/filepath"
 	"regexp"
 	"sort"
 	"strings"
 )
 
-// QueryList allow viewing, editing and saving of the list of Common Misconfigurations
+//go:embed querylist.json
+var defaultQueryList string
+
+//go:embed queryschema.json
+var querySchema string
+
+// DefaultPack is the namespace of the query list built into the binary
+const DefaultPack = "default"
+
+// QueryList allow viewing, editing and saving of the list of Common Misconfigurations.
+// Queries can be loaded from multiple packs, in which case they are namespaced as "pack/name"
+// but can still be referred to by name alone if it is unique
 type QueryList struct {
-	list map[string]QueryData
+	list      map[string]QueryData
+	sources   map[string]string
+	packs     map[string]*queryPack
+	userPack  string
+	conflicts []string
 }
 
 // QueryData contains all data for a particular misconfiguration
 type QueryData struct {
-	Query       string    `json:"query"`
-	Description string    `json:"description"`
-	QueryType   QueryEnum `json:"queryType"`
+	Query       string       `json:"query"`
+	Description string       `json:"description"`
+	QueryType   QueryEnum    `json:"queryType"`
+	Severity    SeverityEnum `json:"severity"`
+	Category    string       `json:"category,omitempty"`
+	Tags        []string     `json:"tags,omitempty"`
+	References  []string     `json:"references,omitempty"`
+	Remediation string       `json:"remediation,omitempty"`
+	// Parameters are substituted into Query before it is run, see QueryParameter
+	Parameters []QueryParameter `json:"parameters,omitempty"`
+	// Version is the revision of the query and is increased each time the query changes
+	Version int `json:"version"`
+}
+
+// queryPack is a set of queries loaded from a single file. Embedded packs have no file
+type queryPack struct {
+	file     string
+	modified bool
 }
 
 // NewQueryList stuff
 func NewQueryList() QueryList {
-	return QueryList{make(map[string]QueryData)}
+	return QueryList{make(map[string]QueryData), map[string]string{}, map[string]*queryPack{}, "", []string{}}
 }
 
 // GetNames stuff
@@ -35,26 +70,69 @@ func (q QueryList) GetNames() []string {
 	return names
 }
 
+// Exists returns true if there is a query called name
+func (q QueryList) Exists(name string) bool {
+	_, ok := q.list[q.resolve(name)]
+	return ok
+}
+
+// Get returns all data for the query called name
+func (q QueryList) Get(name string) (QueryData, bool) {
+	data, ok := q.list[q.resolve(name)]
+	return data, ok
+}
+
 // GetDescription stuff
 func (q QueryList) GetDescription(name string) string {
-	return q.list[name].Description
+	return q.list[q.resolve(name)].Description
 }
 
 // GetQuery stuff
 func (q QueryList) GetQuery(name string) (string, QueryEnum) {
-	return q.list[name].Query, q.list[name].QueryType
+	data := q.list[q.resolve(name)]
+	return data.Query, data.QueryType
+}
+
+// GetQueryWithArgs returns the query for input, of the form "name:param=value,...",
+// with its parameters substituted
+func (q QueryList) GetQueryWithArgs(input string) (string, QueryEnum, error) {
+	name, args, err := ParseQueryArgs(input)
+	if err != nil {
+		return "", QUERY, err
+	}
+	data, ok := q.Get(name)
+	if !ok {
+		return "", QUERY, fmt.Errorf("'%s' is not a valid query", name)
+	}
+	query, err := data.Substitute(args)
+	if err != nil {
+		return "", QUERY, fmt.Errorf("'%s' - %s", name, err.Error())
+	}
+	return query, data.QueryType, nil
+}
+
+// GetParameters returns the parameters declared by the query called name
+func (q QueryList) GetParameters(name string) []QueryParameter {
+	return q.list[q.resolve(name)].Parameters
 }
 
 // GetHints stuff
+// Once a query has been chosen and arguments are being provided, its parameters are returned
 func (q QueryList) GetHints(input string) []string {
 	var hints []string
+	if name, _, err := ParseQueryArgs(input); err == nil && strings.Contains(input, ":") {
+		for _, param := range q.GetParameters(name) {
+			hints = append(hints, param.String())
+		}
+		return hints
+	}
 	r, _ := regexp.Compile(input)
 	for _, query := range q.GetNames() {
 		if input == query {
 			return []string{}
 		}
 		if r.MatchString(query) {
-			hints = append(hints, query+" - "+redBold+q.GetDescription(query)+reset)
+			hints = append(hints, query+" - "+redBold+q.GetDescription(query)+reset+q.list[query].getMetadataHint())
 		}
 	}
 	return hints
@@ -62,25 +140,108 @@ func (q QueryList) GetHints(input string) []string {
 
 // InsertHint stuff
 func (q QueryList) InsertHint(input string, index int) string {
-	hints := q.GetHints(input)
-	if index < len(hints) {
-		return strings.Split(hints[index], " - ")[0]
+	if strings.Contains(input, ":") {
+		return input
+	}
+	var names []string
+	r, _ := regexp.Compile(input)
+	for _, query := range q.GetNames() {
+		if input == query {
+			return input
+		}
+		if r.MatchString(query) {
+			names = append(names, query)
+		}
+	}
+	if index < len(names) {
+		return names[index]
 	}
 	return input
 }
 
 // Add stuff
+// New queries are added to the user pack (see SetUserPack) unless name includes a pack. An
+// existing query is overwritten with Update, so its metadata is kept
 func (q *QueryList) Add(name string, query string, description string, queryType QueryEnum) error {
-	if queryType != REGEX && queryType != EXPRESSION {
-		return fmt.Errorf("Invalid query type. Must be either Regex or Intelligent")
+	if q.Exists(name) {
+		return q.Update(name, name, query, description, queryType)
 	}
-	q.list[name] = QueryData{query, description, queryType}
-	return nil
+	if err := validateQuery(name, queryType); err != nil {
+		return err
+	}
+	return q.set(name, QueryData{Query: query, Description: description, QueryType: queryType, Version: 1})
+}
+
+// Update replaces the query called oldName, renaming it if name is different
+func (q *QueryList) Update(oldName, name, query, description string, queryType QueryEnum) error {
+	oldName = q.resolve(oldName)
+	if !q.Exists(oldName) {
+		return fmt.Errorf("Query '%s' does not exist", oldName)
+	}
+	pack := q.sources[oldName]
+	if err := q.checkWritable(pack); err != nil {
+		return err
+	}
+	if err := validateQuery(name, queryType); err != nil {
+		return err
+	}
+	if _, ok := q.splitName(name); !ok {
+		name = qualify(pack, name)
+	}
+	if oldName != name && q.Exists(name) {
+		return fmt.Errorf("Query '%s' already exists", name)
+	}
+	data := q.list[oldName]
+	if oldName != name {
+		q.Remove(oldName)
+	}
+	if data.Query != query || data.QueryType != queryType {
+		data.Version++
+	}
+	data.Query, data.Description, data.QueryType = query, description, queryType
+	return q.set(name, data)
 }
 
 // Remove stuff
-func (q *QueryList) Remove(name string) {
+// Queries in the built in pack cannot be removed
+func (q *QueryList) Remove(name string) error {
+	name = q.resolve(name)
+	if _, ok := q.list[name]; ok {
+		if err := q.checkWritable(q.sources[name]); err != nil {
+			return err
+		}
+		q.markModified(q.sources[name])
+	}
 	delete(q.list, name)
+	delete(q.sources, name)
+	return nil
+}
+
+// Commit writes any packs that have been changed back to the files they were loaded from
+func (q QueryList) Commit() error {
+	committed := false
+	var packs []string
+	for pack := range q.packs {
+		packs = append(packs, pack)
+	}
+	sort.Strings(packs)
+	for _, pack := range packs {
+		qp := q.packs[pack]
+		if qp.modified {
+			if err := q.checkWritable(pack); err != nil {
+				return err
+			}
+			if err := q.savePack(pack, qp.file); err != nil {
+				return err
+			}
+			qp.modified = false
+		}
+		committed = committed || qp.file != ""
+	}
+	if !committed {
+		return fmt.Errorf("Query list was not loaded from a file")
+	}
+	return nil
 }
 
 // Save stuff
@@ -89,10 +250,235 @@ func (q QueryList) Save(file string) error {
 }
 
 // Load stuff
+// Queries loaded this way are not namespaced
 func (q *QueryList) Load(file string, schemaFile string) error {
 	schema, err := utils.Load(schemaFile)
 	if schemaFile != "" && err != nil {
 		return err
 	}
-	return utils.LoadJSON(file, &q.list, schema)
+	contents, err := utils.Load(file)
+	if err != nil {
+		return err
+	}
+	return q.loadPack("", file, contents, schema)
+}
+
+// LoadDefaults loads the query list built into the binary
+func (q *QueryList) LoadDefaults() error {
+	return q.loadPack(DefaultPack, "", defaultQueryList, querySchema)
+}
+
+// LoadPack loads a query pack from file. The pack is named after the file, without
+// its extension, and is validated against the built in schema
+func (q *QueryList) LoadPack(file string) error {
+	contents, err := utils.Load(file)
+	if err != nil {
+		return err
+	}
+	if err := q.loadPack(getPackName(file), file, contents, querySchema); err != nil {
+		return fmt.Errorf("'%s' - %s", file, err.Error())
+	}
+	return nil
+}
+
+// LoadPackDir loads every json file in dir as a query pack. A missing directory is not an error
+func (q *QueryList) LoadPackDir(dir string) error {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	for _, file := range files {
+		if !file.IsDir() && filepath.Ext(file.Name()) == ".json" {
+			if err := q.LoadPack(filepath.Join(dir, file.Name())); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// SetUserPack defines the pack that new queries are added to and written to file on Commit.
+// The file does not need to exist
+func (q *QueryList) SetUserPack(file string) {
+	q.userPack = getPackName(file)
+	if _, ok := q.packs[q.userPack]; !ok {
+		q.packs[q.userPack] = &queryPack{file, false}
+	}
+}
+
+// Conflicts returns a description of every query name that is defined more than once
+func (q QueryList) Conflicts() []string {
+	return q.conflicts
+}
+
+func (q *QueryList) loadPack(pack, file, contents, schema string) error {
+	// Each pack is saved to a single file, so a second file with the same name would be merged into the first on Commit
+	if pack == DefaultPack && file != "" {
+		return fmt.Errorf("Pack '%s' has the same name as the built in pack", pack)
+	}
+	if existing, ok := q.packs[pack]; ok && existing.file != file {
+		return fmt.Errorf("Pack '%s' is already loaded from '%s'", pack, existing.file)
+	}
+	var list map[string]QueryData
+	if err := utils.ParseJSON(contents, &list, schema); err != nil {
+		return err
+	}
+	q.packs[pack] = &queryPack{file, false}
+	for name, data := range list {
+		fullName := qualify(pack, name)
+		if _, ok := q.list[fullName]; ok {
+			q.conflicts = append(q.conflicts, fmt.Sprintf("'%s' is defined more than once, keeping the version from '%s'", fullName, file))
+		}
+		q.list[fullName] = migrate(data)
+		q.sources[fullName] = pack
+	}
+	q.findAmbiguousNames(list, pack)
+	return nil
+}
+
+// findAmbiguousNames reports names in list that exist in other packs and so must be referred to with their pack
+func (q *QueryList) findAmbiguousNames(list map[string]QueryData, pack string) {
+	var names []string
+	for name := range list {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, other := range q.GetNames() {
+			if otherPack := q.sources[other]; otherPack != pack && qualify(otherPack, name) == other {
+				q.conflicts = append(q.conflicts, fmt.Sprintf("'%s' exists in packs '%s' and '%s'. Use the full name to choose one", name, otherPack, pack))
+			}
+		}
+	}
+}
+
+func (q QueryList) savePack(pack, file string) error {
+	list := map[string]QueryData{}
+	for name, data := range q.list {
+		if q.sources[name] == pack {
+			list[strings.TrimPrefix(name, qualify(pack, ""))] = data
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return err
+	}
+	return utils.SaveJSON(file, list, true)
+}
+
+// set adds or replaces name in the pack it is already in, the pack it names or else the user
+// pack. Nothing is changed if that pack is built in
+func (q *QueryList) set(name string, data QueryData) error {
+	fullName := q.resolve(name)
+	pack, ok := q.sources[fullName]
+	if !ok {
+		if pack, ok = q.splitName(name); !ok {
+			pack = q.userPack
+			fullName = qualify(pack, name)
+		}
+	}
+	if err := q.checkWritable(pack); err != nil {
+		return err
+	}
+	q.list[fullName] = data
+	q.sources[fullName] = pack
+	q.markModified(pack)
+	return nil
+}
+
+// checkWritable returns an error if pack is built into the binary, as it has no file to save to
+func (q QueryList) checkWritable(pack string) error {
+	if qp, ok := q.packs[pack]; ok && qp.file == "" {
+		return fmt.Errorf("Pack '%s' is built in so cannot be changed", pack)
+	}
+	return nil
+}
+
+func (q *QueryList) markModified(pack string) {
+	if qp, ok := q.packs[pack]; ok {
+		qp.modified = true
+	}
+}
+
+// Resolve returns the full name, e.g. default/name, of the query name refers to
+func (q QueryList) Resolve(name string) string {
+	return q.resolve(name)
+}
+
+// resolve returns the full name of a query, allowing the pack to be left out if name is unique
+func (q QueryList) resolve(name string) string {
+	if _, ok := q.list[name]; ok {
+		return name
+	}
+	var matches []string
+	for fullName, pack := range q.sources {
+		if qualify(pack, name) == fullName {
+			matches = append(matches, fullName)
+		}
+	}
+	if len(matches) == 1 {
+		return matches[0]
+	}
+	return name
+}
+
+// splitName returns the pack of name, if name starts with a loaded pack
+func (q QueryList) splitName(name string) (string, bool) {
+	if index := strings.Index(name, "/"); index > 0 {
+		if _, ok := q.packs[name[:index]]; ok {
+			return name[:index], true
+		}
+	}
+	return "", false
+}
+
+// validateQuery returns an error if the name is empty or the type cannot be saved
+func validateQuery(name string, queryType QueryEnum) error {
+	if strings.Trim(name, " ") == "" {
+		return fmt.Errorf("Query name cannot be empty")
+	}
+	if queryType != REGEX && queryType != EXPRESSION && queryType != PODSECURITY {
+		return fmt.Errorf("Invalid query type. Must be Regex, Expression or PodSecurity")
+	}
+	return nil
+}
+
+// migrate upgrades queries saved before metadata was added. These have no
+// version and are given version 1 with the default severity
+func migrate(data QueryData) QueryData {
+	if data.Version == 0 {
+		data.Version = 1
+	}
+	return data
+}
+
+func qualify(pack, name string) string {
+	if pack == "" {
+		return name
+	}
+	return pack + "/" + name
+}
+
+func getPackName(file string) string {
+	return strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+}
+
+func (qd QueryData) getMetadataHint() string {
+	hint := " [" + qd.Severity.String()
+	if len(qd.Parameters) > 0 {
+		var names []string
+		for _, param := range qd.Parameters {
+			names = append(names, param.Name)
+		}
+		hint += " | params: " + strings.Join(names, ", ")
+	}
+	if qd.Category != "" {
+		hint += " | " + qd.Category
+	}
+	if len(qd.Tags) > 0 {
+		hint += " | " + strings.Join(qd.Tags, ", ")
+	}
+	return hint + "]"
 }
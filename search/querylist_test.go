@@ -1,7 +1,10 @@
 package search_test
 
 import (
+	"io/ioutil"
 	"kube-review/search"
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 )
@@ -85,7 +88,10 @@ func TestGetHintsReturnsStringListWithMatchingQueriesAndDescriptions(t *testing.
 	ql := search.NewQueryList()
 	ql.Load("../testdata/querylist-test.json", "queryschema.json")
 	actual := ql.GetHints("test")
-	expected := []string{"test1more - \033[1;31mFirst test (REGEX)\033[0m", "test2less - \033[1;31mSecond test (Expression)\033[0m"}
+	expected := []string{
+		"test1more - \033[1;31mFirst test (REGEX)\033[0m [Low | Test | first, regex]",
+		"test2less - \033[1;31mSecond test (Expression)\033[0m [Info]",
+	}
 	if !reflect.DeepEqual(actual, expected) {
 		t.Errorf("Expected '%v' but got '%v'", expected, actual)
 	}
@@ -109,3 +115,235 @@ func TestInsertHintReturnsNameOfSelectedHint(t *testing.T) {
 		t.Errorf("Expected '%s' but instead got '%s'", expected, actual)
 	}
 }
+
+func TestAddReturnsErrorForEmptyName(t *testing.T) {
+	ql := search.NewQueryList()
+	err := ql.Add(" ", "TestRegex", "Empty name", search.REGEX)
+	if err == nil {
+		t.Errorf("Expected an error but instead got nothing")
+	}
+}
+
+func TestUpdateRenamesQuery(t *testing.T) {
+	ql := search.NewQueryList()
+	ql.Load("../testdata/querylist-test.json", "queryschema.json")
+	ql.Update("test1more", "renamed", "[0-9]", "Renamed test", search.REGEX)
+	if ql.Exists("test1more") {
+		t.Errorf("Expected 'test1more' to have been removed")
+	}
+	if actual, _ := ql.GetQuery("renamed"); actual != "[0-9]" {
+		t.Errorf("Expected '[0-9]' but instead got '%s'", actual)
+	}
+}
+
+func TestUpdateReturnsErrorIfRenamedOverExistingQuery(t *testing.T) {
+	ql := search.NewQueryList()
+	ql.Load("../testdata/querylist-test.json", "queryschema.json")
+	err := ql.Update("test1more", "test2less", "[0-9]", "Renamed test", search.REGEX)
+	if err == nil {
+		t.Errorf("Expected an error but instead got nothing")
+	}
+}
+
+func TestCommitReturnsErrorIfNotLoadedFromFile(t *testing.T) {
+	ql := search.NewQueryList()
+	if err := ql.Commit(); err == nil {
+		t.Errorf("Expected an error but instead got nothing")
+	}
+}
+
+func TestCommitWritesBackToLoadedFile(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "kube-review")
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "querylist.json")
+	contents, _ := ioutil.ReadFile("../testdata/querylist-test.json")
+	ioutil.WriteFile(file, contents, 0600)
+
+	ql := search.NewQueryList()
+	ql.Load(file, "queryschema.json")
+	ql.Add("test3", "TestRegex", "Third test", search.REGEX)
+	ql.Commit()
+
+	reloaded := search.NewQueryList()
+	if err := reloaded.Load(file, "queryschema.json"); err != nil || !reloaded.Exists("test3") {
+		t.Errorf("Expected 'test3' to have been saved")
+	}
+}
+
+func TestLoadMigratesQueriesWithoutVersion(t *testing.T) {
+	ql := search.NewQueryList()
+	ql.Load("../testdata/querylist-test.json", "queryschema.json")
+	actual, _ := ql.Get("test2less")
+	if actual.Version != 1 || actual.Severity != search.INFO {
+		t.Errorf("Expected version 1 and Info but got %d and %v", actual.Version, actual.Severity)
+	}
+}
+
+func TestLoadReadsQueryMetadata(t *testing.T) {
+	ql := search.NewQueryList()
+	ql.Load("../testdata/querylist-test.json", "queryschema.json")
+	actual, _ := ql.Get("test1more")
+	expected := search.QueryData{
+		Query:       "[a-z]{5}",
+		Description: "First test (REGEX)",
+		QueryType:   search.REGEX,
+		Severity:    search.LOW,
+		Category:    "Test",
+		Tags:        []string{"first", "regex"},
+		References:  []string{"CIS 1.1.1"},
+		Remediation: "Nothing to fix",
+		Version:     2,
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected %v but got %v", expected, actual)
+	}
+}
+
+func TestLoadReturnsErrorForInvalidSeverity(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "kube-review")
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "querylist.json")
+	ioutil.WriteFile(file, []byte(`{"test": {"query": "a", "description": "a", "queryType": 0, "severity": "Severe"}}`), 0600)
+	ql := search.NewQueryList()
+	if err := ql.Load(file, "queryschema.json"); err == nil {
+		t.Errorf("Expected an error but instead got nothing")
+	}
+}
+
+func TestUpdateKeepsMetadataAndIncreasesVersion(t *testing.T) {
+	ql := search.NewQueryList()
+	ql.Load("../testdata/querylist-test.json", "queryschema.json")
+	ql.Update("test1more", "test1more", "[0-9]", "Changed", search.REGEX)
+	actual, _ := ql.Get("test1more")
+	if actual.Version != 3 || actual.Severity != search.LOW || actual.Remediation != "Nothing to fix" {
+		t.Errorf("Expected version 3 with metadata kept but got %v", actual)
+	}
+}
+
+func TestAddOverExistingQueryKeepsMetadata(t *testing.T) {
+	ql := search.NewQueryList()
+	ql.Load("../testdata/querylist-test.json", "queryschema.json")
+	if err := ql.Add("test1more", "[0-9]", "Overwritten", search.REGEX); err != nil {
+		t.Fatalf("Expected no error but got '%s'", err.Error())
+	}
+	actual, _ := ql.Get("test1more")
+	if actual.Version != 3 || actual.Severity != search.LOW || actual.Remediation != "Nothing to fix" || actual.Description != "Overwritten" {
+		t.Errorf("Expected version 3 with metadata kept but got %v", actual)
+	}
+}
+
+func TestSeverityCanBeParsedFromName(t *testing.T) {
+	actual, err := search.ParseSeverity("critical")
+	if err != nil || actual != search.CRITICAL {
+		t.Errorf("Expected Critical but got %v", actual)
+	}
+}
+
+func getPackQueryList() search.QueryList {
+	ql := search.NewQueryList()
+	ql.LoadDefaults()
+	ql.LoadPackDir("../testdata/querypacks")
+	return ql
+}
+
+func TestLoadDefaultsNamespacesBuiltInQueries(t *testing.T) {
+	ql := search.NewQueryList()
+	if err := ql.LoadDefaults(); err != nil {
+		t.Fatalf("Expected no error but got '%s'", err.Error())
+	}
+	if !ql.Exists("default/Overly-Permissive-PSP") {
+		t.Errorf("Expected 'default/Overly-Permissive-PSP' to exist but it did not")
+	}
+}
+
+func TestUniqueQueryCanBeFoundWithoutPack(t *testing.T) {
+	ql := getPackQueryList()
+	actual, _ := ql.GetQuery("extra-regex")
+	if actual != "[a-z]{3}" {
+		t.Errorf("Expected '[a-z]{3}' but instead got '%s'", actual)
+	}
+}
+
+func TestQueryInMultiplePacksIsReportedAsConflict(t *testing.T) {
+	ql := getPackQueryList()
+	if len(ql.Conflicts()) != 1 {
+		t.Errorf("Expected one conflict but got %v", ql.Conflicts())
+	}
+	if ql.Exists("Secrets-in-ConfigMap") {
+		t.Errorf("Expected ambiguous name to need its pack")
+	}
+	if actual, _ := ql.GetQuery("extra/Secrets-in-ConfigMap"); actual != "PRIVATE KEY" {
+		t.Errorf("Expected 'PRIVATE KEY' but instead got '%s'", actual)
+	}
+}
+
+func TestLoadPackDirIgnoresMissingDirectory(t *testing.T) {
+	ql := search.NewQueryList()
+	if err := ql.LoadPackDir("nodir"); err != nil {
+		t.Errorf("Expected no error but got '%s'", err.Error())
+	}
+}
+
+func TestLoadPackReturnsErrorForInvalidPack(t *testing.T) {
+	ql := search.NewQueryList()
+	if err := ql.LoadPack("../testdata/test.json"); err == nil {
+		t.Errorf("Expected an error but instead got nothing")
+	}
+}
+
+func TestNewQueriesAreCommittedToUserPack(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "kube-review")
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "queries.d", "user.json")
+	ql := getPackQueryList()
+	ql.SetUserPack(file)
+	ql.Add("mine", "[0-9]", "My query", search.REGEX)
+	if err := ql.Commit(); err != nil {
+		t.Fatalf("Expected no error but got '%s'", err.Error())
+	}
+
+	reloaded := search.NewQueryList()
+	reloaded.LoadPack(file)
+	if !reloaded.Exists("user/mine") {
+		t.Errorf("Expected 'user/mine' to have been saved")
+	}
+}
+
+func TestBuiltInQueriesCannotBeChanged(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "kube-review")
+	defer os.RemoveAll(dir)
+	ql := getPackQueryList()
+	ql.SetUserPack(filepath.Join(dir, "user.json"))
+	if err := ql.Remove("default/test-regex"); err == nil {
+		t.Errorf("Expected an error but instead got nothing")
+	}
+	if err := ql.Update("default/test-regex", "renamed", "[a-z]", "Changed", search.REGEX); err == nil {
+		t.Errorf("Expected an error but instead got nothing")
+	}
+	if !ql.Exists("default/test-regex") || ql.Exists("default/renamed") {
+		t.Errorf("Expected the built in query to be left unchanged")
+	}
+	ql.Add("mine", "[0-9]", "My query", search.REGEX)
+	if err := ql.Commit(); err != nil {
+		t.Errorf("Expected user queries to still be saved but got '%s'", err.Error())
+	}
+}
+
+func TestLoadPackReturnsErrorForDuplicatePackName(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "kube-review")
+	defer os.RemoveAll(dir)
+	for _, file := range []string{"default.json", "system/team.json", "user/team.json"} {
+		os.MkdirAll(filepath.Join(dir, filepath.Dir(file)), 0700)
+		ioutil.WriteFile(filepath.Join(dir, file), []byte(`{"mine": {"query": "[0-9]", "description": "Mine", "queryType": 0}}`), 0600)
+	}
+	ql := getPackQueryList()
+	if err := ql.LoadPack(filepath.Join(dir, "default.json")); err == nil {
+		t.Errorf("Expected an error for a pack named after the built in pack but instead got nothing")
+	}
+	if err := ql.LoadPack(filepath.Join(dir, "system", "team.json")); err != nil {
+		t.Fatalf("Expected no error but got '%s'", err.Error())
+	}
+	if err := ql.LoadPack(filepath.Join(dir, "user", "team.json")); err == nil {
+		t.Errorf("Expected an error for a pack loaded from two files but instead got nothing")
+	}
+}
@@ -0,0 +1,54 @@
+package search
+
+import (
+	"fmt"
+	"kube-review/nodelist"
+	"sort"
+)
+
+// Match is a node matched by a search. Line is where it appears in the original JSON or 0 if unknown
+type Match struct {
+	Path string
+	Line int
+}
+
+// Resource is a Kubernetes object along with the nodes in it that a search matched
+type Resource struct {
+	nodelist.ResourceInfo
+	Matches []Match
+}
+
+func (r Resource) String() string {
+	fields := "fields"
+	if len(r.Matches) == 1 {
+		fields = "field"
+	}
+	return fmt.Sprintf("%s: %d offending %s", r.ResourceInfo, len(r.Matches), fields)
+}
+
+// GroupByResource groups nodeIndices by the object they are part of, in the order the objects
+// appear in nodeList. Duplicate indices are only counted once
+func GroupByResource(nodeIndices []int, nodeList sNodeList) ([]Resource, error) {
+	sorted := append([]int{}, nodeIndices...)
+	sort.Ints(sorted)
+
+	var resources []Resource
+	positions := map[nodelist.ResourceInfo]int{}
+	for i, index := range sorted {
+		if i > 0 && index == sorted[i-1] {
+			continue
+		}
+		info := nodeList.GetResourceInfo(index)
+		if _, ok := positions[info]; !ok {
+			positions[info] = len(resources)
+			resources = append(resources, Resource{ResourceInfo: info})
+		}
+		line, err := nodeList.GetLine(index)
+		if err != nil {
+			return nil, err
+		}
+		resource := &resources[positions[info]]
+		resource.Matches = append(resource.Matches, Match{nodeList.GetPath(index), line})
+	}
+	return resources, nil
+}
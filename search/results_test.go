@@ -0,0 +1,49 @@
+package search_test
+
+import (
+	"kube-review/mocks"
+	"kube-review/nodelist"
+	"kube-review/search"
+	"reflect"
+	"testing"
+)
+
+func TestGroupByResourceGroupsInOrderWithoutDuplicates(t *testing.T) {
+	jsonData := `{"items": [{"kind": "Pod", "metadata": {"name": "a"}}, {"kind": "Pod", "metadata": {"name": "b"}}]}`
+	nodeList, _ := nodelist.NewNodeList([]byte(jsonData), true)
+	// 0 Root, 1 items, 2 [0], 3 kind, 4 metadata, 5 name, 6 [1], 7 kind, 8 metadata, 9 name
+	actual, err := search.GroupByResource([]int{9, 3, 5, 3}, &nodeList)
+	if err != nil {
+		t.Fatalf("Expected no error but got '%s'", err.Error())
+	}
+	expected := []search.Resource{
+		{ResourceInfo: nodelist.ResourceInfo{Path: "items[0]", Kind: "Pod", Name: "a"},
+			Matches: []search.Match{{Path: "items[0].kind", Line: 1}, {Path: "items[0].metadata.name", Line: 1}}},
+		{ResourceInfo: nodelist.ResourceInfo{Path: "items[1]", Kind: "Pod", Name: "b"},
+			Matches: []search.Match{{Path: "items[1].metadata.name", Line: 1}}},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected %v but got %v", expected, actual)
+	}
+}
+
+func TestResourceStringCountsOffendingFields(t *testing.T) {
+	resource := search.Resource{ResourceInfo: nodelist.ResourceInfo{Kind: "Deployment", Namespace: "ns", Name: "foo"},
+		Matches: []search.Match{{Path: "a"}, {Path: "b"}, {Path: "c"}}}
+	if actual := resource.String(); actual != "Deployment ns/foo: 3 offending fields" {
+		t.Errorf("Expected 'Deployment ns/foo: 3 offending fields' but got '%s'", actual)
+	}
+	resource.Matches = resource.Matches[:1]
+	if actual := resource.String(); actual != "Deployment ns/foo: 1 offending field" {
+		t.Errorf("Expected 'Deployment ns/foo: 1 offending field' but got '%s'", actual)
+	}
+}
+
+func TestExecuteReturnsMatchesGroupedByResource(t *testing.T) {
+	mock := mocks.NodeListMock{Returns: [][]int{{}, {4, 2}}}
+	s := search.NewSearch(search.REGEX, getQueryList())
+	actual, err := s.Execute("test", &mock)
+	if err != nil || len(actual) != 2 || actual[0].Path != "[2]" || actual[1].Path != "[4]" {
+		t.Errorf("Expected resources [2] and [4] but got %v, %v", actual, err)
+	}
+}
@@ -3,7 +3,9 @@ package search
 import (
 	"fmt"
 	"kube-review/nodelist"
+	"kube-review/pss"
 	"regexp"
+	"strings"
 )
 
 // Search stuff
@@ -40,20 +42,25 @@ func (s Search) InsertSelectedHint(input string, cursorPos int, index int) (stri
 	return input, cursorPos
 }
 
-// Execute runs a search based on input, QueryMode and searchMode
-func (s Search) Execute(input string, nodeList sNodeList) error {
+// Execute runs a search based on input, QueryMode and searchMode, returning what was
+// matched grouped by the object it is part of
+func (s Search) Execute(input string, nodeList sNodeList) ([]Resource, error) {
 	nodeList.ResetView()
 	matchedNodes, err := s.getMatchedNodes(input, nodeList)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	resources, err := GroupByResource(matchedNodes, nodeList)
+	if err != nil {
+		return nil, err
 	}
 	if s.functionMode == FILTER {
-		return nodeList.Filter(matchedNodes)
+		return resources, nodeList.Filter(matchedNodes)
 	} else if s.functionMode == FIND {
 		nodeList.Highlight(matchedNodes)
-		return nodeList.FindNextHighlight()
+		return resources, nodeList.FindNextHighlight()
 	}
-	return fmt.Errorf("Invalid search type. Should be Filter or Find")
+	return nil, fmt.Errorf("Invalid search type. Should be Filter or Find")
 }
 
 // ToggleQueryMode switches between regex and query mode
@@ -66,6 +73,26 @@ func (s *Search) ToggleSearchMode() {
 	s.functionMode = (s.functionMode + 1) % 2
 }
 
+// SetModes sets both the query and function mode, e.g. when recalling a search from history
+func (s *Search) SetModes(queryMode QueryEnum, functionMode FunctionEnum) {
+	s.queryMode = queryMode
+	s.functionMode = functionMode
+}
+
+// NewHistoryEntry creates a HistoryEntry for input using the current modes
+func (s Search) NewHistoryEntry(input string) HistoryEntry {
+	return HistoryEntry{input, s.queryMode, s.functionMode}
+}
+
+// GetMissingParameters returns the parameters of the query in input if in query mode
+// and no arguments have been given
+func (s Search) GetMissingParameters(input string) []QueryParameter {
+	if s.queryMode != QUERY || strings.Contains(input, ":") {
+		return []QueryParameter{}
+	}
+	return s.ql.GetParameters(strings.Trim(input, " "))
+}
+
 // GetModeInfo returns the search and function type for UI title
 func (s Search) GetModeInfo() string {
 	return s.queryMode.String() + "-" + s.functionMode.String()
@@ -80,13 +107,25 @@ func (s Search) getPossibleHints(input string, cursorPos int) []string {
 	return []string{}
 }
 
+// GetQuery returns the regex or expression and its type that input will be run as.
+// In query mode this is the stored query named in input with any arguments substituted
+func (s Search) GetQuery(input string) (string, QueryEnum, error) {
+	if s.queryMode == QUERY {
+		return s.ql.GetQueryWithArgs(input)
+	}
+	return input, s.queryMode, nil
+}
+
+// GetMatchedNodes runs input against the current view of nodeList and returns the
+// indices of the matching nodes without changing the view
+func (s Search) GetMatchedNodes(input string, nodeList sNodeList) ([]int, error) {
+	return s.getMatchedNodes(input, nodeList)
+}
+
 func (s Search) getMatchedNodes(input string, nodeList sNodeList) ([]int, error) {
-	qMode := s.queryMode
-	regex := input
-	if qMode == QUERY {
-		if regex, qMode = s.ql.GetQuery(input); regex == "" {
-			return nil, fmt.Errorf("'%s' is not a valid query", input)
-		}
+	regex, qMode, err := s.GetQuery(input)
+	if err != nil {
+		return nil, err
 	}
 
 	if qMode == EXPRESSION {
@@ -95,7 +134,17 @@ func (s Search) getMatchedNodes(input string, nodeList sNodeList) ([]int, error)
 			return nil, err
 		}
 		// use output to find/filter
-		return expression.Execute(nodeList), nil
+		return expression.Execute(nodeList)
+	} else if qMode == PODSECURITY {
+		violations, err := pss.EvaluateQuery(nodeList, regex)
+		if err != nil {
+			return nil, err
+		}
+		var indices []int
+		for _, violation := range violations {
+			indices = append(indices, violation.Index)
+		}
+		return orderedUnion(indices, []int{}), nil
 	}
 	r, err := regexp.Compile(regex)
 	if err != nil {
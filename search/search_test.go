@@ -28,7 +28,8 @@ func TestGetHintsReturnsNothingInRegexMode(t *testing.T) {
 func TestGetHintsReturnsStringOfHints(t *testing.T) {
 	s := search.NewSearch(search.QUERY, getQueryList())
 	actual := s.GetHints("test", 4)
-	expected := "\ntest1more - \033[1;31mFirst test (REGEX)\033[0m\ntest2less - \033[1;31mSecond test (Expression)\033[0m"
+	expected := "\ntest1more - \033[1;31mFirst test (REGEX)\033[0m [Low | Test | first, regex]" +
+		"\ntest2less - \033[1;31mSecond test (Expression)\033[0m [Info]"
 	if actual != expected {
 		t.Errorf("Expected '%s' but got '%s'", expected, actual)
 	}
@@ -88,7 +89,7 @@ func TestResetViewIsCalledBeforeSearch(t *testing.T) {
 func TestExecuteReturnsErrorForInvalidRegex(t *testing.T) {
 	mock := mocks.NodeListMock{}
 	s := search.NewSearch(search.REGEX, getQueryList())
-	actual := s.Execute("*", &mock)
+	_, actual := s.Execute("*", &mock)
 	if actual == nil {
 		t.Errorf("Expected an error but got none")
 	}
@@ -97,7 +98,7 @@ func TestExecuteReturnsErrorForInvalidRegex(t *testing.T) {
 func TestExecuteReturnsErrorForInvalidQuery(t *testing.T) {
 	mock := mocks.NodeListMock{}
 	s := search.NewSearch(search.QUERY, getQueryList())
-	actual := s.Execute("test", &mock)
+	_, actual := s.Execute("test", &mock)
 	if actual == nil {
 		t.Errorf("Expected an error but got none")
 	}
@@ -106,7 +107,7 @@ func TestExecuteReturnsErrorForInvalidQuery(t *testing.T) {
 func TestExecuteReturnsErrorForInvalidExpression(t *testing.T) {
 	mock := mocks.NodeListMock{}
 	s := search.NewSearch(search.EXPRESSION, getQueryList())
-	actual := s.Execute("test", &mock)
+	_, actual := s.Execute("test", &mock)
 	if actual == nil {
 		t.Errorf("Expected an error but got none")
 	}
@@ -118,3 +119,19 @@ func TestSearchExpressionIntegratesWithNodelist(t *testing.T) {
 	s := search.NewSearch(search.EXPRESSION, getQueryList())
 	s.Execute("FindNodes(\"Wilma Kidd\", output=test) + FindRelative(test, \"id\", 1, 2, KEY, true)", &nodeList)
 }
+
+func TestGetQueryReturnsStoredQueryInQueryMode(t *testing.T) {
+	s := search.NewSearch(search.QUERY, getQueryList())
+	query, queryType, err := s.GetQuery("test2less")
+	if err != nil || query != "Any==\"[0-9]{2}\"" || queryType != search.EXPRESSION {
+		t.Errorf("Expected 'Any==\"[0-9]{2}\"' and Expression but got '%s' and %v", query, queryType)
+	}
+}
+
+func TestGetQueryReturnsInputAndModeOutsideQueryMode(t *testing.T) {
+	s := search.NewSearch(search.REGEX, getQueryList())
+	query, queryType, err := s.GetQuery("test")
+	if err != nil || query != "test" || queryType != search.REGEX {
+		t.Errorf("Expected 'test' and Regex but got '%s' and %v", query, queryType)
+	}
+}
@@ -1,6 +1,9 @@
 package search
 
 import (
+	"encoding/json"
+	"fmt"
+	"kube-review/k8s"
 	"kube-review/nodelist"
 	"regexp"
 	"strings"
@@ -30,10 +33,63 @@ const (
 	EXPRESSION
 	// QUERY a
 	QUERY
+	// PODSECURITY a
+	PODSECURITY
 )
 
 func (qe QueryEnum) String() string {
-	return [...]string{"Regex", "Expression", "Query"}[qe]
+	return [...]string{"Regex", "Expression", "Query", "PodSecurity"}[qe]
+}
+
+// SeverityEnum lists the possible severities of a query finding
+type SeverityEnum int
+
+const (
+	// INFO a
+	INFO SeverityEnum = iota
+	// LOW a
+	LOW
+	// MEDIUM a
+	MEDIUM
+	// HIGH a
+	HIGH
+	// CRITICAL a
+	CRITICAL
+)
+
+var severityNames = [...]string{"Info", "Low", "Medium", "High", "Critical"}
+
+func (se SeverityEnum) String() string {
+	return severityNames[se]
+}
+
+// ParseSeverity returns the severity matching name, ignoring case
+func ParseSeverity(name string) (SeverityEnum, error) {
+	for index, severity := range severityNames {
+		if strings.EqualFold(name, severity) {
+			return SeverityEnum(index), nil
+		}
+	}
+	return INFO, fmt.Errorf("Invalid severity '%s'. Must be one of %v", name, severityNames)
+}
+
+// MarshalJSON stores severity by name so query lists are easy to edit
+func (se SeverityEnum) MarshalJSON() ([]byte, error) {
+	return json.Marshal(se.String())
+}
+
+// UnmarshalJSON reads severity from its name
+func (se *SeverityEnum) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return err
+	}
+	severity, err := ParseSeverity(name)
+	if err != nil {
+		return err
+	}
+	*se = severity
+	return nil
 }
 
 // CmdFunc is enum for possible command functions
@@ -46,23 +102,50 @@ const (
 	CMDFINDNODES
 	// CMDFINDRELATIVE a
 	CMDFINDRELATIVE
+	// CMDFINDRESOURCES a
+	CMDFINDRESOURCES
+	// CMDWHOCAN a
+	CMDWHOCAN
+	// CMDFINDRBAC a
+	CMDFINDRBAC
+	// CMDFINDCONTAINERS a
+	CMDFINDCONTAINERS
+	// CMDFINDEFFECTIVE a
+	CMDFINDEFFECTIVE
+	// CMDCHECKNETWORKPOLICY a
+	CMDCHECKNETWORKPOLICY
+	// CMDFINDRELATED a
+	CMDFINDRELATED
+	// CMDFINDSECRETS a
+	CMDFINDSECRETS
+	// CMDCHECKIMAGES a
+	CMDCHECKIMAGES
+	// CMDCHECKCERTIFICATES a
+	CMDCHECKCERTIFICATES
 )
 
+// cmdFuncs lists the functions that can be called in an expression
+var cmdFuncs = []CmdFunc{CMDFINDNODES, CMDFINDRELATIVE, CMDFINDRESOURCES, CMDWHOCAN, CMDFINDRBAC, CMDFINDCONTAINERS, CMDFINDEFFECTIVE, CMDCHECKNETWORKPOLICY, CMDFINDRELATED, CMDFINDSECRETS, CMDCHECKIMAGES, CMDCHECKCERTIFICATES}
+
 func (cf CmdFunc) String() string {
-	return [...]string{"Null", "FindNodes", "FindRelative"}[cf]
+	return [...]string{"Null", "FindNodes", "FindRelative", "FindResources", "WhoCan", "FindRBAC", "FindContainers", "FindEffective", "CheckNetworkPolicy", "FindRelated", "FindSecrets", "CheckImages", "CheckCertificates"}[cf]
 }
 
 func (cf CmdFunc) template() []argTemplate {
-	return [...][]argTemplate{[]argTemplate{}, findArgs, findRelArgs}[cf]
+	return [...][]argTemplate{[]argTemplate{}, findArgs, findRelArgs, findResourcesArgs, whoCanArgs, findRBACArgs, findContainersArgs, findEffectiveArgs, checkNetworkPolicyArgs, findRelatedArgs, findSecretsArgs, checkImagesArgs, checkCertificatesArgs}[cf]
 }
 
 type sNodeList interface {
+	k8s.NodeList
 	GetNodesMatching(regex *regexp.Regexp, matchType nodelist.MatchType, equal bool) []int
 	GetRelativesMatching(nodeIndex, relativeStartLevel, depth int, regex *regexp.Regexp, matchType nodelist.MatchType, equal bool) []int
 	Filter(nodes []int) error
 	Highlight(nodes []int)
 	FindNextHighlight() error
 	ResetView()
+	GetLine(nodeIndex int) (int, error)
+	GetResourceInfo(nodeIndex int) nodelist.ResourceInfo
+	GetRawValue(nodeIndex int) string
 }
 
 const (
@@ -118,3 +201,68 @@ var findRelArgs = []argTemplate{
 	argTemplate{"equal", "bool", "should match be equal or not equal to regex"},
 	argTemplate{"output", "output", "variable that holds matched nodes. If exists, append to previous result"},
 }
+
+var findResourcesArgs = []argTemplate{
+	argTemplate{"kind", "regex", "quoted regex the whole kind must match. Empty matches any"},
+	argTemplate{"namespace", "regex", "quoted regex the whole namespace must match. Empty matches any"},
+	argTemplate{"name", "regex", "quoted regex the whole name must match. Empty matches any"},
+	argTemplate{"output", "output", "variable that holds the top node of each matched resource. If exists, append to previous result"},
+}
+
+var whoCanArgs = []argTemplate{
+	argTemplate{"verb", "string", "quoted verb, e.g. \"get\""},
+	argTemplate{"resource", "string", "quoted resource, e.g. \"secrets\", \"deployments.apps\" or \"pods/exec\""},
+	argTemplate{"namespace", "string", "quoted namespace the access is needed in. Empty matches any"},
+	argTemplate{"output", "output", "variable that holds the subjects of bindings granting the access. If exists, append to previous result"},
+}
+
+var findRBACArgs = []argTemplate{
+	argTemplate{"check", "rbacCheck", "quoted name of an RBAC check, e.g. \"pods-exec\", \"escalate-bind\" or \"anonymous\""},
+	argTemplate{"namespace", "regex", "quoted regex the whole namespace a permission applies in must match. Empty matches any"},
+	argTemplate{"output", "output", "variable that holds the rule and subject nodes of each flagged permission. If exists, append to previous result"},
+}
+
+var findContainersArgs = []argTemplate{
+	argTemplate{"kind", "regex", "quoted regex the whole kind of the workload must match. Empty matches any"},
+	argTemplate{"output", "output", "variable that holds every container, init container and ephemeral container. If exists, append to previous result"},
+}
+
+var findEffectiveArgs = []argTemplate{
+	argTemplate{"nodes", "input", "containers to run against. Must be output of FindContainers"},
+	argTemplate{"path", "string", "quoted path of the field, e.g. \"securityContext.runAsNonRoot\", looked up in the container then its pod spec"},
+	argTemplate{"regex", "regex", "quoted regex string"},
+	argTemplate{"equal", "bool", "should the value match or not match regex. If not, containers without the field are returned"},
+	argTemplate{"output", "output", "variable that holds matched nodes. If exists, append to previous result"},
+}
+
+var checkNetworkPolicyArgs = []argTemplate{
+	argTemplate{"check", "netpolCheck", "quoted name of a NetworkPolicy check, e.g. \"no-policy\", \"allow-all\" or \"metadata-egress\""},
+	argTemplate{"namespace", "regex", "quoted regex the whole namespace must match. Empty matches any"},
+	argTemplate{"output", "output", "variable that holds the namespace, pod spec or rule flagged by the check. If exists, append to previous result"},
+}
+
+var findRelatedArgs = []argTemplate{
+	argTemplate{"nodes", "input", "nodes whose resources the edges are followed from"},
+	argTemplate{"edge", "regex", "quoted regex the whole edge type must match, e.g. \"selects\", \"owns\", \"mounts\", \"references\", \"uses\" or \"binds\". Empty matches any"},
+	argTemplate{"reverse", "bool", "follow edges pointing at the resources instead, e.g. the Services that select a Deployment"},
+	argTemplate{"output", "output", "variable that holds the top node of each related resource. If exists, append to previous result"},
+}
+
+var findSecretsArgs = []argTemplate{
+	argTemplate{"rule", "regex", "quoted regex the whole rule name must match, e.g. \"private-key\", \"aws-.*\" or \"high-entropy\". Empty matches any"},
+	argTemplate{"confidence", "confidence", "quoted minimum confidence of a finding, \"Low\", \"Medium\" or \"High\""},
+	argTemplate{"output", "output", "variable that holds the string values with a credential in them. If exists, append to previous result"},
+}
+
+var checkImagesArgs = []argTemplate{
+	argTemplate{"check", "imageCheck", "quoted name of an image check, e.g. \"latest-tag\", \"untrusted-registry\" or \"insecure-registry\""},
+	argTemplate{"registries", "regex", "quoted regex the whole registry of an image must match to be allowed by untrusted-registry. Empty allows any"},
+	argTemplate{"output", "output", "variable that holds the image, imagePullPolicy or registry config flagged by the check. If exists, append to previous result"},
+}
+
+var checkCertificatesArgs = []argTemplate{
+	argTemplate{"check", "certCheck", "quoted name of a certificate check, \"expired\", \"expiring\", \"weak-key\" or \"self-signed\""},
+	argTemplate{"date", "date", "quoted date expiry is measured from, as YYYY-MM-DD or RFC3339. Empty or \"now\" is the current time"},
+	argTemplate{"days", "int", "number of days from date a certificate must expire within to be expiring"},
+	argTemplate{"output", "output", "variable that holds the string values with a flagged certificate in them. If exists, append to previous result"},
+}
@@ -0,0 +1,166 @@
+package secrets
+
+import (
+	"encoding/json"
+	"math"
+	"regexp"
+	"strings"
+)
+
+// Rule is a pattern that identifies a credential. Match is given the key of the value, which
+// for an environment variable is its name, and returns the credential found in value
+type Rule struct {
+	Name        string
+	Description string
+	Confidence  ConfidenceEnum
+	generic     bool
+	match       func(key, value string) string
+}
+
+var (
+	secretKeyRegex     = regexp.MustCompile(`(?i)(pass(word|wd)?|pwd|secret|token|api[_-]?key|access[_-]?key|private[_-]?key|credential|auth)`)
+	referenceKeyRegex  = regexp.MustCompile(`(?i)(name|ref|path|file|dir|namespace|selector|mode|seconds|type)$`)
+	assignmentRegex    = regexp.MustCompile(`(?i)(?:pass(?:word|wd)?|pwd|secret|token|api[_-]?key)["']?\s*[:=]\s*["']?([^\s"',;]{6,})`)
+	privateKeyRegex    = regexp.MustCompile(`-----BEGIN (?:[A-Z0-9]+ )*PRIVATE KEY(?: BLOCK)?-----\s*([A-Za-z0-9+/=]*)`)
+	awsSecretNameRegex = regexp.MustCompile(`(?i)aws.*secret`)
+	awsSecretRegex     = regexp.MustCompile(`^[A-Za-z0-9/+=]{40}$`)
+	awsSecretKeyRegex  = regexp.MustCompile(`(?i)aws_?secret_?access_?key["']?\s*[:=]\s*["']?([A-Za-z0-9/+=]{40})`)
+	azureKeyRegex      = regexp.MustCompile(`AccountKey=([A-Za-z0-9+/=]{40,})`)
+	basicAuthURLRegex  = regexp.MustCompile(`\b[a-zA-Z][a-zA-Z0-9+.-]*://[^/\s:@]+:([^/\s@]+)@`)
+	tokenSplitRegex    = regexp.MustCompile(`[\s"'=:,;@()\[\]{}<>]+`)
+	base64TokenRegex   = regexp.MustCompile(`^[A-Za-z0-9+/_-]{20,}={0,2}$`)
+	hexTokenRegex      = regexp.MustCompile(`^[a-fA-F0-9]{32,}$`)
+	uuidRegex          = regexp.MustCompile(`^[a-fA-F0-9]{8}-[a-fA-F0-9]{4}-[a-fA-F0-9]{4}-[a-fA-F0-9]{4}-[a-fA-F0-9]{12}$`)
+	placeholderRegex   = regexp.MustCompile(`(?i)^(\$|\{\{|<|%|\*+$|(password|changeme|secret|true|false|null|none|redacted)$)`)
+	ignoredEntropyKeys = regexp.MustCompile(`^(uid|resourceVersion|image|imageID|containerID|caBundle|certificate-authority-data|client-certificate-data|selfLink)$`)
+)
+
+var rules = []Rule{
+	{"private-key", "PEM private keys", HIGH, false, func(key, value string) string {
+		// The key itself is previewed rather than its header, unless the header is all there is
+		if match := privateKeyRegex.FindStringSubmatch(value); match != nil {
+			if match[1] != "" {
+				return match[1]
+			}
+			return match[0]
+		}
+		return ""
+	}},
+	regexRule("aws-access-key", "AWS access key IDs", HIGH, `\b(?:AKIA|ASIA|AGPA|AIDA|AROA)[0-9A-Z]{16}\b`),
+	{"aws-secret-key", "AWS secret access keys", HIGH, false, func(key, value string) string {
+		if awsSecretNameRegex.MatchString(key) && awsSecretRegex.MatchString(value) {
+			return value
+		}
+		return submatch(awsSecretKeyRegex, value)
+	}},
+	regexRule("gcp-api-key", "Google Cloud API keys", HIGH, `\bAIza[0-9A-Za-z_-]{35}\b`),
+	regexRule("gcp-service-account", "Google Cloud service account key files", HIGH, `"type"\s*:\s*"service_account"`),
+	{"azure-storage-key", "Azure storage account keys in connection strings", HIGH, false, func(key, value string) string {
+		return submatch(azureKeyRegex, value)
+	}},
+	regexRule("github-token", "GitHub tokens", HIGH, `\bgh[pousr]_[A-Za-z0-9]{36,}\b`),
+	regexRule("slack-token", "Slack tokens", HIGH, `\bxox[baprs]-[A-Za-z0-9-]{10,}`),
+	regexRule("jwt", "JSON Web Tokens, such as service account tokens", HIGH, `\beyJ[A-Za-z0-9_-]{5,}\.eyJ[A-Za-z0-9_-]{5,}\.[A-Za-z0-9_-]*`),
+	{"basic-auth-url", "URLs with a username and password", HIGH, false, func(key, value string) string {
+		if password := submatch(basicAuthURLRegex, value); !placeholderRegex.MatchString(password) {
+			return password
+		}
+		return ""
+	}},
+	{"docker-config", "Docker config JSON with registry credentials", HIGH, false, matchDockerConfig},
+	{"credential-assignment", "passwords, tokens and keys assigned to a key or variable named like one", MEDIUM, true, func(key, value string) string {
+		if secretKeyRegex.MatchString(key) && !referenceKeyRegex.MatchString(key) && len(value) >= 6 && !strings.ContainsAny(value, " \n") && !placeholderRegex.MatchString(value) {
+			return value
+		}
+		if assignment := submatch(assignmentRegex, value); !placeholderRegex.MatchString(assignment) {
+			return assignment
+		}
+		return ""
+	}},
+	{"high-entropy", "random looking strings, which are Medium confidence under a key named like a credential", LOW, true, matchHighEntropy},
+}
+
+// Rules returns every rule
+func Rules() []Rule {
+	return rules
+}
+
+// ShannonEntropy returns the entropy of value in bits per character
+func ShannonEntropy(value string) float64 {
+	counts := map[rune]float64{}
+	for _, c := range value {
+		counts[c]++
+	}
+	entropy := 0.0
+	length := float64(len([]rune(value)))
+	for _, count := range counts {
+		p := count / length
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+/////////////////////////////////////////////////////////////////////////////////
+// PRIVATE FUNCTIONS
+
+func regexRule(name, description string, confidence ConfidenceEnum, pattern string) Rule {
+	r := regexp.MustCompile(pattern)
+	return Rule{name, description, confidence, false, func(key, value string) string {
+		return r.FindString(value)
+	}}
+}
+
+// submatch returns the first group of r in value, or "" if it does not match
+func submatch(r *regexp.Regexp, value string) string {
+	if match := r.FindStringSubmatch(value); len(match) > 1 {
+		return match[1]
+	}
+	return ""
+}
+
+// matchDockerConfig returns the first auth, password or token in a docker config.json, taking
+// registries in order of name so the same credential is found every time
+func matchDockerConfig(key, value string) string {
+	if !strings.Contains(value, "auths") {
+		return ""
+	}
+	var config struct {
+		Auths map[string]interface{} `json:"auths"`
+	}
+	if err := json.Unmarshal([]byte(value), &config); err != nil {
+		return ""
+	}
+	for _, name := range getSortedKeys(config.Auths) {
+		registry, _ := config.Auths[name].(map[string]interface{})
+		for _, field := range []string{"auth", "password", "identitytoken"} {
+			if value, ok := registry[field].(string); ok && value != "" {
+				return value
+			}
+		}
+	}
+	return ""
+}
+
+// matchHighEntropy returns the first token of value that is random enough to be a key. Hex
+// strings, which are often hashes, are only returned under a key named like a credential
+func matchHighEntropy(key, value string) string {
+	if ignoredEntropyKeys.MatchString(key) {
+		return ""
+	}
+	for _, token := range tokenSplitRegex.Split(value, -1) {
+		if uuidRegex.MatchString(token) {
+			continue
+		}
+		if hexTokenRegex.MatchString(token) {
+			if secretKeyRegex.MatchString(key) && ShannonEntropy(token) >= 3.0 {
+				return token
+			}
+			continue
+		}
+		if base64TokenRegex.MatchString(token) && strings.ContainsAny(token, "0123456789") &&
+			strings.ToLower(token) != token && strings.ToUpper(token) != token && ShannonEntropy(token) >= 4.0 {
+			return token
+		}
+	}
+	return ""
+}
@@ -0,0 +1,253 @@
+package secrets
+
+import (
+	"encoding/json"
+	"fmt"
+	"kube-review/k8s"
+	"kube-review/nodelist"
+	"kube-review/utils"
+	"sort"
+	"strings"
+)
+
+// ConfidenceEnum is how likely a finding is to be a real credential
+type ConfidenceEnum int
+
+const (
+	// LOW a
+	LOW ConfidenceEnum = iota
+	// MEDIUM a
+	MEDIUM
+	// HIGH a
+	HIGH
+)
+
+var confidenceNames = [...]string{"Low", "Medium", "High"}
+
+func (ce ConfidenceEnum) String() string {
+	return confidenceNames[ce]
+}
+
+// ParseConfidence returns the confidence matching name, ignoring case
+func ParseConfidence(name string) (ConfidenceEnum, error) {
+	for index, confidence := range confidenceNames {
+		if strings.EqualFold(strings.TrimSpace(name), confidence) {
+			return ConfidenceEnum(index), nil
+		}
+	}
+	return LOW, fmt.Errorf("Invalid confidence '%s'. Must be Low, Medium or High", name)
+}
+
+// LastAppliedAnnotation holds the last configuration applied with kubectl, which for a Secret
+// includes its data
+const LastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// Finding is a credential found in the string value at Index. Location is empty if it was
+// found in the value itself, base64 if in the decoded value, or the path of the value inside
+// the last-applied-configuration annotation, followed by (base64) if it was decoded. Preview is the credential masked by Mask
+type Finding struct {
+	Rule       string
+	Confidence ConfidenceEnum
+	Resource   nodelist.ResourceInfo
+	Index      int
+	Location   string
+	Preview    string
+}
+
+// Scan checks every string value in nodeList's current view against the rules and returns the
+// findings of at least minimum confidence. The data of Secrets is skipped as that is where
+// credentials belong. Environment variable values and annotations are also checked after base64
+// decoding, and the values in the last-applied-configuration annotation are checked as well
+func Scan(nodeList k8s.ValueNodeList, minimum ConfidenceEnum) []Finding {
+	index := k8s.NewIndex(nodeList)
+	var skipped [][2]int
+	decoded := map[int]bool{}
+	keys := map[int]string{}
+	for _, resource := range index.Resources() {
+		if resource.Kind == "Secret" {
+			for _, key := range []string{"data", "stringData"} {
+				if data, ok := nodeList.GetChild(resource.Start, key); ok {
+					skipped = append(skipped, [2]int{data, nodeList.GetLastChild(data)})
+				}
+			}
+		}
+		if annotations, ok := nodeList.GetChild(resource.Start, "metadata", "annotations"); ok {
+			for _, annotation := range nodeList.GetChildren(annotations) {
+				decoded[annotation] = true
+			}
+		}
+	}
+	for _, template := range index.PodTemplates(nodeList) {
+		for _, container := range k8s.GetContainers(nodeList, template.Spec) {
+			if env, ok := nodeList.GetChild(container, "env"); ok {
+				for _, variable := range nodeList.GetChildren(env) {
+					if value, ok := nodeList.GetChild(variable, "value"); ok {
+						// Rules are given the variable's name rather than "value"
+						keys[value] = nodeList.GetChildValue(variable, "name")
+						decoded[value] = true
+					}
+				}
+			}
+		}
+	}
+
+	var findings []Finding
+	for _, nodeIndex := range k8s.GetStringNodes(nodeList, 0) {
+		if isSkipped(skipped, nodeIndex) {
+			continue
+		}
+		key, ok := keys[nodeIndex]
+		if !ok {
+			key = nodeList.GetKey(nodeIndex)
+		}
+		value := nodeList.GetValue(nodeIndex)
+		resource, _ := index.GetContaining(nodeIndex)
+		add := func(location string, matches []match) {
+			for _, m := range matches {
+				if m.confidence >= minimum {
+					findings = append(findings, Finding{m.rule, m.confidence, resource.ResourceInfo, nodeIndex, location, Mask(m.secret)})
+				}
+			}
+		}
+
+		var document interface{}
+		if key == LastAppliedAnnotation && json.Unmarshal([]byte(value), &document) == nil {
+			// Each value is checked on its own rather than the JSON as a whole
+			for _, leaf := range getLeaves(document, "") {
+				add(scanDecoded(leaf.key, leaf.value, leaf.path, true))
+			}
+			continue
+		}
+		add(scanDecoded(key, value, "", decoded[nodeIndex]))
+	}
+	return findings
+}
+
+// Find returns the findings of the rules whose name fully matches the rule regex, or of every
+// rule if it is empty, of at least minimum confidence
+func Find(nodeList k8s.ValueNodeList, rule string, minimum ConfidenceEnum) ([]Finding, error) {
+	ruleRegex, err := utils.CompileFullMatch(rule)
+	if err != nil {
+		return nil, err
+	}
+	var findings []Finding
+	for _, finding := range Scan(nodeList, minimum) {
+		if ruleRegex == nil || ruleRegex.MatchString(finding.Rule) {
+			findings = append(findings, finding)
+		}
+	}
+	return findings, nil
+}
+
+// Mask hides all but the start of secret, so a finding can be recognised without the
+// credential being shown. Short secrets only keep their first character
+func Mask(secret string) string {
+	shown := 4
+	if len(secret) < 12 {
+		shown = 1
+	}
+	return fmt.Sprintf("%s%s (%d chars)", secret[:shown], strings.Repeat("*", 8), len(secret))
+}
+
+/////////////////////////////////////////////////////////////////////////////////
+// PRIVATE FUNCTIONS
+
+type match struct {
+	rule       string
+	confidence ConfidenceEnum
+	secret     string
+}
+
+// scanValue returns the match of each rule in value. Generic rules are only tried if no
+// specific rule matched, and high entropy strings are Medium confidence under a key named
+// like a credential
+func scanValue(key, value string) []match {
+	var matches []match
+	for _, rule := range rules {
+		if rule.generic && len(matches) > 0 {
+			break
+		}
+		if secret := rule.match(key, value); secret != "" {
+			confidence := rule.Confidence
+			if rule.Name == "high-entropy" && secretKeyRegex.MatchString(key) {
+				confidence = MEDIUM
+			}
+			matches = append(matches, match{rule.Name, confidence, secret})
+		}
+	}
+	return matches
+}
+
+// scanDecoded scans value at location, or if decode is set and value is base64 of text with a
+// credential in it, the decoded text, so the same credential is not reported twice
+func scanDecoded(key, value, location string, decode bool) (string, []match) {
+	if decode {
+		if text, ok := utils.DecodeBase64Text(value, 12); ok {
+			if matches := scanValue(key, text); len(matches) > 0 {
+				if location == "" {
+					return "base64", matches
+				}
+				return location + " (base64)", matches
+			}
+		}
+	}
+	return location, scanValue(key, value)
+}
+
+func isSkipped(skipped [][2]int, nodeIndex int) bool {
+	for _, r := range skipped {
+		if nodeIndex >= r[0] && nodeIndex <= r[1] {
+			return true
+		}
+	}
+	return false
+}
+
+type leaf struct {
+	path  string
+	key   string
+	value string
+}
+
+// getLeaves returns every string in a decoded JSON document with its path, e.g. data.password
+func getLeaves(document interface{}, path string) []leaf {
+	var leaves []leaf
+	switch value := document.(type) {
+	case map[string]interface{}:
+		for _, key := range getSortedKeys(value) {
+			leafKey := key
+			if name, ok := value["name"].(string); ok && key == "value" {
+				// Environment variables are checked by their name
+				leafKey = name
+			}
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			}
+			if text, ok := value[key].(string); ok {
+				leaves = append(leaves, leaf{childPath, leafKey, text})
+			} else {
+				leaves = append(leaves, getLeaves(value[key], childPath)...)
+			}
+		}
+	case []interface{}:
+		for index, child := range value {
+			childPath := fmt.Sprintf("%s[%d]", path, index)
+			if text, ok := child.(string); ok {
+				leaves = append(leaves, leaf{childPath, "", text})
+			} else {
+				leaves = append(leaves, getLeaves(child, childPath)...)
+			}
+		}
+	}
+	return leaves
+}
+
+func getSortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,46 @@
+package ui
+
+import (
+	"fmt"
+	"kube-review/certs"
+	"kube-review/nodelist"
+	"strings"
+	"time"
+)
+
+// certificateDisplay summarises the certificates in the value of the active node above its JSON.
+// Certificates are only decoded again when the active value changes
+type certificateDisplay struct {
+	nodeList *nodelist.NodeList
+	at       time.Time
+	value    string
+	summary  []string
+}
+
+func newCertificateDisplay(nodeList *nodelist.NodeList, at time.Time) *certificateDisplay {
+	return &certificateDisplay{nodeList: nodeList, at: at}
+}
+
+// getContent returns the summary of each certificate in the active value followed by the
+// JSON of the active node, num lines long in total
+func (c *certificateDisplay) getContent(num int) string {
+	if value := c.nodeList.GetValue(c.nodeList.GetActiveNode()); value != c.value || c.summary == nil {
+		c.value, c.summary = value, []string{}
+		certificates := certs.Decode(value)
+		for index, certificate := range certificates {
+			c.summary = append(c.summary, fmt.Sprintf("Certificate %d of %d", index+1, len(certificates)))
+			for _, line := range certificate.Summary(c.at) {
+				c.summary = append(c.summary, "  "+line)
+			}
+		}
+	}
+	if len(c.summary) == 0 {
+		return c.nodeList.GetColouredJSON(num)
+	}
+	remaining := num - len(c.summary) - 1
+	if remaining < 1 {
+		// A zero or negative num would return all of the JSON
+		remaining = 1
+	}
+	return strings.Join(c.summary, "\n") + "\n\n" + c.nodeList.GetColouredJSON(remaining)
+}
@@ -2,10 +2,12 @@ package ui
 
 import (
 	"fmt"
+	"kube-review/k8s"
 	"kube-review/nodelist"
 	"kube-review/search"
 	"log"
 	"strings"
+	"time"
 
 	"github.com/awesome-gocui/gocui"
 )
@@ -16,10 +18,14 @@ type CursesUI struct {
 	win       Window
 	nodeList  *nodelist.NodeList
 	queryList *search.QueryList
+	history   *search.History
+	related   *relatedPane
+	certs     *certificateDisplay
+	lastQuery *string
 }
 
-// NewCursesUI stuff
-func NewCursesUI(nodeList *nodelist.NodeList, queryList *search.QueryList) (CursesUI, error) {
+// NewCursesUI stuff. The days remaining of certificates shown in the display are measured from at
+func NewCursesUI(nodeList *nodelist.NodeList, queryList *search.QueryList, history *search.History, at time.Time) (CursesUI, error) {
 	gui, err := gocui.NewGui(gocui.OutputNormal, true)
 	if err != nil {
 		return CursesUI{}, err
@@ -28,7 +34,7 @@ func NewCursesUI(nodeList *nodelist.NodeList, queryList *search.QueryList) (Curs
 	gui.SelFgColor = gocui.ColorRed
 	gui.Cursor = true
 
-	cui := CursesUI{gui, NewWindow(0.2, 1, 3), nodeList, queryList}
+	cui := CursesUI{gui, NewWindow(0.2, 1, 3), nodeList, queryList, history, newRelatedPane(nodeList), newCertificateDisplay(nodeList, at), new(string)}
 
 	cui.gui.SetManagerFunc(cui.update)
 
@@ -40,7 +46,10 @@ func NewCursesUI(nodeList *nodelist.NodeList, queryList *search.QueryList) (Curs
 	if err := gui.SetKeybinding("", gocui.KeyTab, gocui.ModNone, changeView); err != nil {
 		log.Panicln(err)
 	}
-	if err := gui.SetKeybinding("", gocui.KeyCtrlS, gocui.ModNone, NewSaveUI(nodeList, queryList).Save); err != nil {
+	if err := gui.SetKeybinding("", gocui.KeyCtrlS, gocui.ModNone, NewSaveUI(nodeList, queryList, cui.lastQuery).Save); err != nil {
+		log.Panicln(err)
+	}
+	if err := gui.SetKeybinding("", gocui.KeyCtrlE, gocui.ModNone, NewQueryUI(queryList).Manage); err != nil {
 		log.Panicln(err)
 	}
 	if err := gui.SetKeybinding("", gocui.KeyCtrlT, gocui.ModNone, cui.splitNodeList); err != nil {
@@ -49,6 +58,15 @@ func NewCursesUI(nodeList *nodelist.NodeList, queryList *search.QueryList) (Curs
 	if err := gui.SetKeybinding("", gocui.KeyCtrlY, gocui.ModNone, cui.selectView); err != nil {
 		log.Panicln(err)
 	}
+	if err := gui.SetKeybinding("", gocui.KeyCtrlD, gocui.ModNone, cui.removeView); err != nil {
+		log.Panicln(err)
+	}
+	if err := gui.SetKeybinding("", gocui.KeyCtrlG, gocui.ModNone, cui.related.toggle); err != nil {
+		log.Panicln(err)
+	}
+	if err := gui.SetKeybinding("", gocui.KeyCtrlO, gocui.ModNone, cui.expandEmbedded); err != nil {
+		log.Panicln(err)
+	}
 	gui.SetKeybinding("", gocui.KeyCtrlR, gocui.ModNone, func(g *gocui.Gui, v *gocui.View) error {
 		cui.nodeList.ResetView()
 		return nil
@@ -117,11 +135,17 @@ func (cui *CursesUI) SetCursor(active bool) {
 func (cui CursesUI) update(gui *gocui.Gui) error {
 	x, y := cui.gui.Size()
 	cui.win.Resize(x, y, cui.getLinesInSearch())
+	cui.win.SplitRelated(cui.related.shown)
 	return cui.setViews()
 }
 
 func (cui CursesUI) setViews() error {
 	for name, layout := range cui.win.views {
+		if name == RELATED && layout.x1 == 0 {
+			// Hidden until toggled, and gocui does not allow views without a size
+			cui.gui.DeleteView(name.String())
+			continue
+		}
 		if view, err := cui.gui.SetView(name.String(), layout.x0, layout.y0, layout.x1, layout.y1, 0); err != nil {
 			view.Title = name.String()
 			switch name {
@@ -134,11 +158,14 @@ func (cui CursesUI) setViews() error {
 				view.Editable = true
 			case SEARCH:
 				view.Title = "Search: Mode=Regex-Find"
-				view.Editor = NewSearchEditor(cui.nodeList, cui.queryList)
+				view.Editor = NewSearchEditor(cui.nodeList, cui.queryList, cui.history, cui.lastQuery)
 				view.Editable = true
 				view.Autoscroll = true
 			case HELP:
 				view.Write([]byte(HELP.Help()))
+			case RELATED:
+				view.Title = "Related Resources"
+				view.Write([]byte(cui.related.getContent()))
 			}
 		} else {
 			switch name {
@@ -147,10 +174,13 @@ func (cui CursesUI) setViews() error {
 				view.Write([]byte(cui.nodeList.GetNodes(layout.y1 - layout.y0)))
 			case DISPLAY:
 				view.Clear()
-				view.Write([]byte(cui.nodeList.GetJSON(layout.y1 - layout.y0)))
+				view.Write([]byte(cui.certs.getContent(layout.y1 - layout.y0)))
 			case VIEW:
 				view.Clear()
 				view.Write([]byte(cui.nodeList.GetCurrentView()))
+			case RELATED:
+				view.Clear()
+				view.Write([]byte(cui.related.getContent()))
 			}
 		}
 	}
@@ -159,22 +189,30 @@ func (cui CursesUI) setViews() error {
 
 func (cui CursesUI) splitNodeList(g *gocui.Gui, v *gocui.View) error {
 	var ch = make(chan string)
-	cui.CreatePopup("Split Nodes", "Define the string used to split the nodes:\n", NewWritePopupEditor(ch), true, false, true)
+	cui.CreatePopup("Split Nodes", "Define the string used to split the nodes, e.g. items = metadata.namespace, kind or @containers = image:\n", NewWritePopupEditor(ch), true, false, true)
 	go func(ch chan string, nodeList *nodelist.NodeList) {
 		splitString := <-ch
-		nodeList.SplitViews(splitString)
+		err := k8s.SplitViews(nodeList, splitString)
 		cui.ClosePopup()
+		if err != nil {
+			showResult("Split", "", err)
+		}
 	}(ch, cui.nodeList)
 	return nil
 }
 
+// expandEmbedded runs in the key handler rather than a goroutine, as it replaces the views
+// that the layout reads from
+func (cui CursesUI) expandEmbedded(g *gocui.Gui, v *gocui.View) error {
+	count, err := cui.nodeList.ExpandEmbedded()
+	result := fmt.Sprintf("Decoded %d values in view %s", count, cui.nodeList.GetCurrentView())
+	go showResult("Decode Embedded", result, err)
+	return nil
+}
+
 func (cui CursesUI) selectView(g *gocui.Gui, v *gocui.View) error {
 	var ch = make(chan string)
-	content := "Choose the nodelist view:"
-	for _, view := range cui.nodeList.ListViews() {
-		content += "\n" + view
-	}
-	cui.CreatePopup("Select View", content, NewSelectPopupEditor(ch), false, true, true)
+	cui.CreatePopup("Select View", cui.getViewTree("Choose the nodelist view:"), NewValueSelectPopupEditor(ch, cui.getViewNames()), false, true, true)
 	go func(ch chan string, nodeList *nodelist.NodeList) {
 		view := <-ch
 		nodeList.SetView(view)
@@ -183,6 +221,38 @@ func (cui CursesUI) selectView(g *gocui.Gui, v *gocui.View) error {
 	return nil
 }
 
+func (cui CursesUI) removeView(g *gocui.Gui, v *gocui.View) error {
+	var ch = make(chan string)
+	cui.CreatePopup("Remove View", cui.getViewTree("Choose the view to remove, along with any below it:"), NewValueSelectPopupEditor(ch, cui.getViewNames()), false, true, true)
+	go func(ch chan string, nodeList *nodelist.NodeList) {
+		view := <-ch
+		err := nodeList.RemoveView(view)
+		cui.ClosePopup()
+		if err != nil {
+			showResult("Remove View", "", err)
+		}
+	}(ch, cui.nodeList)
+	return nil
+}
+
+// getViewTree returns prompt followed by a line for each view in the tree of views
+func (cui CursesUI) getViewTree(prompt string) string {
+	content := prompt
+	for _, item := range cui.nodeList.GetViewTree() {
+		content += "\n" + item.Line
+	}
+	return content
+}
+
+// getViewNames returns the name of the view on each line of getViewTree
+func (cui CursesUI) getViewNames() []string {
+	var names []string
+	for _, item := range cui.nodeList.GetViewTree() {
+		names = append(names, item.Name)
+	}
+	return names
+}
+
 var lastView string
 
 // CreatePopup stuff
@@ -264,6 +334,10 @@ func determinePopupDimensions(content string, winWidth, winHeight int) (int, int
 			width = len(t)
 		}
 	}
+	if width >= winWidth-2 {
+		// Long lines, such as queries, are scrolled rather than stopping the popup showing
+		width = winWidth - 3
+	}
 	if width != 0 && height != 0 && width < winWidth && height < winHeight {
 		return winWidth/2 - width/2,
 			winHeight/2 - height/2,
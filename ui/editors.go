@@ -1,8 +1,11 @@
 package ui
 
 import (
+	"fmt"
 	"kube-review/nodelist"
 	"kube-review/search"
+	"log"
+	"strings"
 
 	"github.com/awesome-gocui/gocui"
 )
@@ -41,13 +44,7 @@ func basicEditor(v *gocui.View, key gocui.Key, ch rune, mod gocui.Modifier) {
 		v.SetOrigin(0, 0)
 	case key == gocui.KeyEnd:
 		line, _ := v.Line(0)
-		width, _ := v.Size()
-		if len(line) < width {
-			v.SetCursor(len(line), 0)
-		} else {
-			v.SetCursor(width-1, 0)
-			v.SetOrigin(len(line)-width+1, 0)
-		}
+		moveCursorToEnd(v, line)
 	}
 }
 
@@ -55,19 +52,33 @@ func basicEditor(v *gocui.View, key gocui.Key, ch rune, mod gocui.Modifier) {
 type SearchEditor struct {
 	s               search.Search
 	nodeList        *nodelist.NodeList
+	history         *search.History
+	queryUI         QueryUI
 	searchCursorPos int
+	lastQuery       *string
 }
 
 // NewSearchEditor stuff
-func NewSearchEditor(nodeList *nodelist.NodeList, queryList *search.QueryList) *SearchEditor {
-	return &SearchEditor{search.NewSearch(search.REGEX, queryList), nodeList, 0}
+// lastQuery is set to each search run without error in query mode and cleared by any other search
+func NewSearchEditor(nodeList *nodelist.NodeList, queryList *search.QueryList, history *search.History, lastQuery *string) *SearchEditor {
+	return &SearchEditor{search.NewSearch(search.REGEX, queryList), nodeList, history, NewQueryUI(queryList), 0, lastQuery}
 }
 
 // Edit stuff
 func (e *SearchEditor) Edit(v *gocui.View, key gocui.Key, ch rune, mod gocui.Modifier) {
 	if _, lineNum := v.Cursor(); lineNum == 0 {
 		e.searchCursorPos, _ = v.Cursor()
-		basicEditor(v, key, ch, mod)
+		switch {
+		case key == gocui.KeyArrowUp:
+			if entry, ok := e.history.Previous(); ok {
+				e.setSearch(v, entry)
+			}
+		case key == gocui.KeyArrowDown && e.history.IsRecalling():
+			entry, _ := e.history.Next()
+			e.setSearch(v, entry)
+		default:
+			basicEditor(v, key, ch, mod)
+		}
 	} else {
 		switch key {
 		case gocui.KeyArrowUp:
@@ -88,12 +99,17 @@ func (e *SearchEditor) Edit(v *gocui.View, key gocui.Key, ch rune, mod gocui.Mod
 	switch key {
 	case gocui.KeyEnter:
 		input, _ := v.Line(0)
-		v.Clear()
-		v.Write([]byte(input))
-		if err := e.s.Execute(input, e.nodeList); err != nil {
-			v.Write([]byte("\n" + err.Error()))
+		if params := e.s.GetMissingParameters(input); len(params) > 0 {
+			e.queryUI.RequestArguments(strings.Trim(input, " "), params, func(fullInput string) {
+				cui.gui.Update(func(g *gocui.Gui) error {
+					e.execute(v, fullInput)
+					moveCursorToEnd(v, fullInput)
+					return nil
+				})
+			})
 			return
 		}
+		e.execute(v, input)
 		return
 	case gocui.KeyCtrlQ:
 		e.s.ToggleQueryMode()
@@ -107,6 +123,18 @@ func (e *SearchEditor) Edit(v *gocui.View, key gocui.Key, ch rune, mod gocui.Mod
 		return
 	case gocui.KeyCtrlN:
 		e.nodeList.FindNextHighlight()
+	case gocui.KeyCtrlP:
+		e.searchHistory(v)
+		return
+	case gocui.KeyCtrlA:
+		input, _ := v.Line(0)
+		if query, queryType, err := e.s.GetQuery(input); err == nil {
+			e.queryUI.SaveSearch(query, queryType)
+		} else {
+			v.Clear()
+			v.Write([]byte(input + "\n" + err.Error()))
+		}
+		return
 	case gocui.KeyEsc:
 		if input, err := v.Line(0); err == nil {
 			v.Clear()
@@ -127,11 +155,87 @@ func (e *SearchEditor) Edit(v *gocui.View, key gocui.Key, ch rune, mod gocui.Mod
 	cui.SetCursor(lineNum == 0)
 }
 
+// execute runs the search and records it in the history, and in lastQuery if it succeeds in query mode
+func (e *SearchEditor) execute(v *gocui.View, input string) {
+	v.Clear()
+	v.Write([]byte(input))
+	if err := e.history.Add(e.s.NewHistoryEntry(input)); err != nil {
+		log.Println("Failed to save search history - " + err.Error())
+	}
+	resources, err := e.s.Execute(input, e.nodeList)
+	if err != nil {
+		v.Write([]byte("\n" + err.Error()))
+		return
+	}
+	*e.lastQuery = ""
+	if entry := e.s.NewHistoryEntry(input); entry.QueryMode == search.QUERY {
+		*e.lastQuery = input
+	}
+	v.Write([]byte(summariseResults(resources)))
+}
+
+// maxResultLines limits how many matched objects are listed under the search
+const maxResultLines = 5
+
+// summariseResults lists the objects matched by a search, one per line, up to maxResultLines
+func summariseResults(resources []search.Resource) string {
+	if len(resources) == 0 {
+		return "\nNo matches found"
+	}
+	var summary string
+	for index, resource := range resources {
+		if index == maxResultLines {
+			return summary + fmt.Sprintf("\n... and %d more", len(resources)-maxResultLines)
+		}
+		summary += "\n" + resource.String()
+	}
+	return summary
+}
+
+// setSearch replaces the search input with entry and restores the modes it was run in
+func (e *SearchEditor) setSearch(v *gocui.View, entry search.HistoryEntry) {
+	if entry.Input != "" {
+		e.s.SetModes(entry.QueryMode, entry.FunctionMode)
+		cui.UpdateViewTitle(SEARCH, "Search: Mode="+e.s.GetModeInfo())
+	}
+	clearInput(v)
+	v.Write([]byte(entry.Input))
+	moveCursorToEnd(v, entry.Input)
+}
+
+// searchHistory opens a popup to incrementally search through previous searches
+func (e *SearchEditor) searchHistory(v *gocui.View) {
+	var ch = make(chan search.HistoryEntry)
+	editor := NewHistoryPopupEditor(ch, e.history)
+	if err := cui.CreatePopup("Search History", editor.content(""), editor, true, false, true); err != nil {
+		log.Println(err.Error())
+		return
+	}
+	go func(ch chan search.HistoryEntry) {
+		entry := <-ch
+		cui.ClosePopup()
+		cui.gui.Update(func(g *gocui.Gui) error {
+			e.setSearch(v, entry)
+			return nil
+		})
+	}(ch)
+}
+
 func clearInput(v *gocui.View) {
 	v.Clear()
 	v.SetCursor(0, 0)
 }
 
+func moveCursorToEnd(v *gocui.View, line string) {
+	width, _ := v.Size()
+	if len(line) < width {
+		v.SetCursor(len(line), 0)
+	} else {
+		v.SetCursor(width-1, 0)
+		v.SetOrigin(len(line)-width+1, 0)
+	}
+}
+
 // NodesEditor is the editor for the PANEL view
 type NodesEditor struct {
 	nodeList *nodelist.NodeList
@@ -207,12 +311,19 @@ func (e *DisplayEditor) Edit(v *gocui.View, key gocui.Key, ch rune, mod gocui.Mo
 
 // SelectPopupEditor stuff
 type SelectPopupEditor struct {
-	ch chan string
+	ch     chan string
+	values []string
 }
 
 // NewSelectPopupEditor stuff
 func NewSelectPopupEditor(ch chan string) *SelectPopupEditor {
-	return &SelectPopupEditor{ch}
+	return &SelectPopupEditor{ch, nil}
+}
+
+// NewValueSelectPopupEditor sends the value matching the selected line, after the first,
+// instead of the line itself. Used when lines are not unique, such as in a tree
+func NewValueSelectPopupEditor(ch chan string, values []string) *SelectPopupEditor {
+	return &SelectPopupEditor{ch, values}
 }
 
 // Edit stuff
@@ -228,7 +339,12 @@ func (s *SelectPopupEditor) Edit(v *gocui.View, key gocui.Key, ch rune, mod gocu
 		v.MoveCursor(0, 1, false)
 	case key == gocui.KeyEnter:
 		_, cursorY := v.Cursor()
-		if line, err := v.Line(cursorY); err == nil {
+		if s.values != nil {
+			_, originY := v.Origin()
+			if index := originY + cursorY - 1; index >= 0 && index < len(s.values) {
+				s.ch <- s.values[index]
+			}
+		} else if line, err := v.Line(cursorY); err == nil {
 			s.ch <- line
 		}
 	case key == gocui.KeyEsc:
@@ -276,6 +392,81 @@ func (w *WritePopupEditor) Edit(v *gocui.View, key gocui.Key, ch rune, mod gocui
 	}
 }
 
+// HistoryPopupEditor lets the user filter previous searches by typing on the first
+// line and select one of the matches below it
+type HistoryPopupEditor struct {
+	ch      chan search.HistoryEntry
+	history *search.History
+	matches []search.HistoryEntry
+}
+
+const historyPopupMatches = 10
+
+// NewHistoryPopupEditor creates a new HistoryPopupEditor
+func NewHistoryPopupEditor(ch chan search.HistoryEntry, history *search.History) *HistoryPopupEditor {
+	return &HistoryPopupEditor{ch, history, []search.HistoryEntry{}}
+}
+
+// Edit updates the matching searches as user types and sends selected search on enter
+func (h *HistoryPopupEditor) Edit(v *gocui.View, key gocui.Key, ch rune, mod gocui.Modifier) {
+	_, lineNum := v.Cursor()
+	switch {
+	case key == gocui.KeyArrowUp:
+		if lineNum > 1 {
+			v.MoveCursor(0, -1, false)
+		}
+	case key == gocui.KeyArrowDown:
+		if lineNum-1 < len(h.matches) {
+			v.MoveCursor(0, 1, false)
+		}
+	case key == gocui.KeyEnter:
+		if lineNum > 1 {
+			h.ch <- h.matches[lineNum-2]
+		} else if len(h.matches) > 0 {
+			h.ch <- h.matches[0]
+		}
+		return
+	case key == gocui.KeyEsc:
+		cui.ClosePopup()
+		return
+	case lineNum == 1:
+		switch {
+		case ch != 0 && mod == 0:
+			v.EditWrite(ch)
+		case key == gocui.KeySpace:
+			v.EditWrite(' ')
+		case key == gocui.KeyBackspace || key == gocui.KeyBackspace2:
+			if !atLineBeginning(v) {
+				v.EditDelete(true)
+			}
+		case key == gocui.KeyArrowLeft:
+			if !atLineBeginning(v) {
+				v.MoveCursor(-1, 0, false)
+			}
+		case key == gocui.KeyArrowRight:
+			v.MoveCursor(1, 0, false)
+		}
+		cursorPos, _ := v.Cursor()
+		input, _ := v.Line(1)
+		v.Clear()
+		v.Write([]byte(h.content(input)))
+		v.SetCursor(cursorPos, 1)
+	}
+	_, lineNum = v.Cursor()
+	v.Highlight = lineNum > 1
+	cui.SetCursor(lineNum == 1)
+}
+
+// content returns the popup text for the searches matching input
+func (h *HistoryPopupEditor) content(input string) string {
+	h.matches = h.history.Find(input, historyPopupMatches)
+	content := "Type to search previous searches:\n" + input
+	for _, match := range h.matches {
+		content += "\n" + match.String()
+	}
+	return content
+}
+
 func atLineBeginning(v *gocui.View) bool {
 	xCursor, _ := v.Cursor()
 	xOrigin, _ := v.Origin()
@@ -0,0 +1,45 @@
+package ui
+
+import "fmt"
+
+// These helpers block until the user responds so must be run outside of the main gui loop
+
+// getSelection asks the user to choose one of the lines, after the first, of content
+func getSelection(title, content string) (string, error) {
+	var ch = make(chan string)
+	if err := cui.CreatePopup(title, content, NewSelectPopupEditor(ch), false, true, true); err != nil {
+		return "", fmt.Errorf("Could not create popup")
+	}
+	selection := <-ch
+	cui.ClosePopup()
+	return selection, nil
+}
+
+// getText asks the user to write a line of text, which starts as initial
+func getText(title, prompt, initial string) (string, error) {
+	var ch = make(chan string)
+	if err := cui.CreatePopup(title, prompt+"\n"+initial, NewWritePopupEditor(ch), true, false, true); err != nil {
+		return "", fmt.Errorf("Could not create popup")
+	}
+	text := <-ch
+	cui.ClosePopup()
+	return text, nil
+}
+
+// getConfirmation returns true if the user answers yes to question
+func getConfirmation(title, question string) bool {
+	var ch = make(chan string)
+	if err := cui.CreatePopup(title, question+" (Y/N)", NewConfirmPopupEditor(ch), false, false, true); err != nil {
+		return false
+	}
+	return <-ch == "y"
+}
+
+// showResult tells the user whether action succeeded
+func showResult(action, success string, err error) {
+	if err == nil {
+		cui.CreatePopup(action+" Successful", success, NewConfirmPopupEditor(nil), false, false, true)
+	} else {
+		cui.CreatePopup(action+" Failed", err.Error(), NewConfirmPopupEditor(nil), false, false, true)
+	}
+}
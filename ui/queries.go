@@ -0,0 +1,137 @@
+package ui
+
+import (
+	"fmt"
+	"kube-review/search"
+	"log"
+
+	"github.com/awesome-gocui/gocui"
+)
+
+// QueryUI sets up processes for creating, editing and deleting saved queries
+type QueryUI struct {
+	queryList *search.QueryList
+}
+
+// NewQueryUI stuff
+func NewQueryUI(queryList *search.QueryList) QueryUI {
+	return QueryUI{queryList}
+}
+
+// SaveSearch prompts the user for a name and description and stores query in the query list
+func (q QueryUI) SaveSearch(query string, queryType search.QueryEnum) {
+	go q.saveSearchProcess(query, queryType)
+}
+
+// RequestArguments prompts the user for a value for each parameter of the query called
+// name and passes the query with its arguments, e.g. "name:param=value", to run
+func (q QueryUI) RequestArguments(name string, params []search.QueryParameter, run func(string)) {
+	go func() {
+		args := map[string]string{}
+		for _, param := range params {
+			value, err := getText("Query Parameters", param.String()+":", param.Default)
+			if err != nil {
+				log.Println(err.Error())
+				return
+			}
+			args[param.Name] = value
+		}
+		run(search.FormatQueryArgs(name, args))
+	}()
+}
+
+// Manage opens the query manager to edit or delete saved queries
+func (q QueryUI) Manage(g *gocui.Gui, v *gocui.View) error {
+	go q.manageProcess()
+	return nil
+}
+
+func (q QueryUI) saveSearchProcess(query string, queryType search.QueryEnum) {
+	name, errName := getText("Save Query", "Provide query name:", "")
+	if errName != nil {
+		log.Println(errName.Error())
+		return
+	}
+	if q.queryList.Exists(name) && !getConfirmation("Query Exists", "'"+name+"' already exists. Do you want to overwrite:") {
+		return
+	}
+	description, errDescription := getText("Save Query", "Provide query description:", "")
+	if errDescription != nil {
+		log.Println(errDescription.Error())
+		return
+	}
+
+	err := q.queryList.Add(name, query, description, queryType)
+	if err == nil {
+		err = q.queryList.Commit()
+	}
+	showResult("Save Query", queryType.String()+" query '"+name+"' has been saved", err)
+}
+
+func (q QueryUI) manageProcess() {
+	content := "Choose a query:"
+	for _, name := range q.queryList.GetNames() {
+		content += "\n" + name
+	}
+	name, errName := getSelection("Manage Queries", content)
+	if errName != nil {
+		log.Println(errName.Error())
+		return
+	}
+	action, errAction := getSelection("Manage "+name, "Choose an action:\nEdit\nDelete")
+	if errAction != nil {
+		log.Println(errAction.Error())
+		return
+	}
+
+	var err error
+	var result string
+	if action == "Edit" {
+		err = q.editQuery(name)
+		result = "'" + name + "' has been successfully updated"
+	} else if action == "Delete" {
+		if !getConfirmation("Delete Query", "Are you sure you want to delete '"+name+"':") {
+			return
+		}
+		if err = q.queryList.Remove(name); err == nil {
+			err = q.queryList.Commit()
+		}
+		result = "'" + name + "' has been successfully deleted"
+	} else {
+		err = fmt.Errorf(action + " is not a valid action")
+	}
+	showResult(action+" Query", result, err)
+}
+
+func (q QueryUI) editQuery(name string) error {
+	query, queryType := q.queryList.GetQuery(name)
+	newName, err := getText("Edit "+name, "Query name:", name)
+	if err != nil {
+		return err
+	}
+	newQuery, err := getText("Edit "+name, "Query:", query)
+	if err != nil {
+		return err
+	}
+	newDescription, err := getText("Edit "+name, "Description:", q.queryList.GetDescription(name))
+	if err != nil {
+		return err
+	}
+	newType, err := getSelection("Edit "+name, "Query type (currently "+queryType.String()+"):\n"+
+		search.REGEX.String()+"\n"+search.EXPRESSION.String()+"\n"+search.PODSECURITY.String())
+	if err != nil {
+		return err
+	}
+	if newType == search.EXPRESSION.String() {
+		queryType = search.EXPRESSION
+	} else if newType == search.PODSECURITY.String() {
+		queryType = search.PODSECURITY
+	} else {
+		queryType = search.REGEX
+	}
+
+	if err := q.queryList.Update(name, newName, newQuery, newDescription, queryType); err != nil {
+		return err
+	}
+	return q.queryList.Commit()
+}
@@ -0,0 +1,57 @@
+package ui
+
+import (
+	"fmt"
+	"kube-review/k8s"
+	"kube-review/nodelist"
+	"reflect"
+
+	"github.com/awesome-gocui/gocui"
+)
+
+// relatedPane lists the resources related to the one containing the active node. The graph is
+// only rebuilt when the view or the resources in it change
+type relatedPane struct {
+	nodeList  *nodelist.NodeList
+	shown     bool
+	viewName  string
+	resources []int
+	index     k8s.Index
+	graph     k8s.Graph
+}
+
+func newRelatedPane(nodeList *nodelist.NodeList) *relatedPane {
+	return &relatedPane{nodeList: nodeList}
+}
+
+func (r *relatedPane) toggle(g *gocui.Gui, v *gocui.View) error {
+	r.shown = !r.shown
+	return nil
+}
+
+// getContent returns the active resource followed by a line for each edge to or from it
+func (r *relatedPane) getContent() string {
+	viewName, resources := r.nodeList.GetCurrentView(), r.nodeList.GetResourceIndices()
+	if viewName != r.viewName || !reflect.DeepEqual(resources, r.resources) {
+		r.viewName, r.resources = viewName, resources
+		r.index = k8s.NewIndex(r.nodeList)
+		r.graph = r.index.Graph(r.nodeList)
+	}
+	resource, ok := r.index.GetContaining(r.nodeList.GetActiveNode())
+	if !ok {
+		return "No resource selected"
+	}
+	content := resource.ResourceInfo.String()
+	edges := r.graph.Related(resource)
+	for _, edge := range edges {
+		if edge.From.Start == resource.Start {
+			content += fmt.Sprintf("\n  %s %s", edge.Type, edge.To.ResourceInfo)
+		} else {
+			content += fmt.Sprintf("\n  %s %s", edge.Type.Inverse(), edge.From.ResourceInfo)
+		}
+	}
+	if len(edges) == 0 {
+		content += "\n  No related resources"
+	}
+	return content
+}
@@ -3,7 +3,9 @@ package ui
 import (
 	"fmt"
 	"kube-review/nodelist"
+	"kube-review/report"
 	"kube-review/search"
+	"kube-review/utils"
 	"log"
 	"os"
 
@@ -14,11 +16,13 @@ import (
 type SaveUI struct {
 	nodeList  *nodelist.NodeList
 	queryList *search.QueryList
+	lastQuery *string
 }
 
 // NewSaveUI stuff
-func NewSaveUI(nodeList *nodelist.NodeList, queryList *search.QueryList) SaveUI {
-	return SaveUI{nodeList, queryList}
+// lastQuery is the most recent search run in query mode this session, see SearchEditor
+func NewSaveUI(nodeList *nodelist.NodeList, queryList *search.QueryList, lastQuery *string) SaveUI {
+	return SaveUI{nodeList, queryList, lastQuery}
 }
 
 // Save stuff
@@ -28,7 +32,12 @@ func (s SaveUI) Save(g *gocui.Gui, v *gocui.View) error {
 }
 
 func (s SaveUI) saveProcess() {
-	saveType, errType := getSaveType()
+	options := "Choose what to save:\nRaw\nQuery"
+	lastSearch, isQuery := s.getLastQuery()
+	if isQuery {
+		options += "\nVulnXML"
+	}
+	saveType, errType := getSelection("Save", options)
 	if errType != nil {
 		log.Println(errType.Error())
 		return
@@ -45,40 +54,41 @@ func (s SaveUI) saveProcess() {
 		err = s.nodeList.Save(filename)
 	} else if saveType == "Query" {
 		err = s.queryList.Save(filename)
+	} else if saveType == "VulnXML" && isQuery {
+		err = s.saveVulnXML(filename, lastSearch)
 	} else {
 		err = fmt.Errorf(saveType + " is not a valid save option")
 	}
+	showResult("Save", saveType+" data has been successfully saved to "+filename, err)
+}
 
-	if err == nil {
-		cui.CreatePopup("Save Successful", saveType+" data has been successfully saved to "+filename, NewConfirmPopupEditor(nil), false, false, true)
-	} else {
-		cui.CreatePopup("Save Failed", err.Error(), NewConfirmPopupEditor(nil), false, false, true)
-	}
+// getLastQuery returns the most recent search of this session if it was run in query mode
+func (s SaveUI) getLastQuery() (string, bool) {
+	return *s.lastQuery, *s.lastQuery != ""
 }
 
-func getSaveType() (string, error) {
-	var ch = make(chan string)
-	content := "Choose what to save:\nRaw\nQuery"
-	if err := cui.CreatePopup("Save", content, NewSelectPopupEditor(ch), false, true, true); err != nil {
-		return "", fmt.Errorf("Could not create popup")
+func (s SaveUI) saveVulnXML(filename, input string) error {
+	finding, err := report.NewFinding(s.queryList, input, *s.nodeList)
+	if err != nil {
+		return err
+	}
+	if !finding.Matched() {
+		return fmt.Errorf("'%s' did not match anything so there is no issue to save", input)
+	}
+	content, err := report.VulnXML([]report.Finding{finding})
+	if err != nil {
+		return err
 	}
-	saveType := <-ch
-	cui.ClosePopup()
-	return saveType, nil
+	return utils.Save(filename, content, true)
 }
 
 func getFilename(saveType string) (string, error) {
-	var ch = make(chan string)
-	if err := cui.CreatePopup("Save "+saveType, "Provide filename:\n", NewWritePopupEditor(ch), true, false, true); err != nil {
-		return "", fmt.Errorf("Could not create popup")
+	filename, err := getText("Save "+saveType, "Provide filename:", "")
+	if err != nil {
+		return "", err
 	}
-	filename := <-ch
-	cui.ClosePopup()
 	if _, err := os.Stat(filename); err == nil {
-		cui.CreatePopup("File Exists", "This file already exists. Do you want to overwrite: (Y/N)", NewConfirmPopupEditor(ch), false, false, true)
-		overwrite := <-ch
-		cui.ClosePopup()
-		if overwrite == "n" {
+		if !getConfirmation("File Exists", "This file already exists. Do you want to overwrite:") {
 			return "", fmt.Errorf("User chose not to overwrite file")
 		}
 	}
@@ -3,6 +3,7 @@ package ui
 import (
 	"kube-review/nodelist"
 	"kube-review/search"
+	"time"
 )
 
 var cui CursesUI
@@ -21,27 +22,30 @@ const (
 	HELP
 	// VIEW a
 	VIEW
+	// RELATED a
+	RELATED
 )
 
 func (ve ViewEnum) String() string {
-	return [...]string{"Panel", "Search", "Display", "Help", "View"}[ve]
+	return [...]string{"Panel", "Search", "Display", "Help", "View", "Related"}[ve]
 }
 
 // Help stuff
 func (ve ViewEnum) Help() string {
 	return [...]string{
-		" | E: Expand Node | C: Collapse Node",             //PANEL
-		" | Ctrl+Q: Toggle Query Mode | Ctrl+N: Find Next", //SEARCH
+		" | E: Expand Node | C: Collapse Node", //PANEL
+		" | Ctrl+Q: Toggle Query Mode | Ctrl+N: Find Next | Up/Down: History | Ctrl+P: Search History | Ctrl+A: Save as Query", //SEARCH
 		"", //DISPLAY
-		"Ctrl+C: Exit  | Tab: Next View | Ctrl+R: Reset View | Ctrl+T: Split View | Ctrl+Y: Change View | Ctrl+S: Save ", //HELP
+		"Ctrl+C: Exit  | Tab: Next View | Ctrl+R: Reset View | Ctrl+T: Split View | Ctrl+Y: Change View | Ctrl+D: Remove View | Ctrl+G: Related | Ctrl+O: Decode Embedded | Ctrl+S: Save | Ctrl+E: Edit Queries ", //HELP
 		"", //VIEW
+		"", //RELATED
 	}[ve]
 }
 
-// Run is the entry point for the curses UI interface
-func Run(nodeList *nodelist.NodeList, queryList *search.QueryList) error {
+// Run is the entry point for the curses UI interface. Certificate expiry is measured from at
+func Run(nodeList *nodelist.NodeList, queryList *search.QueryList, history *search.History, at time.Time) error {
 	var err error
-	cui, err = NewCursesUI(nodeList, queryList)
+	cui, err = NewCursesUI(nodeList, queryList, history, at)
 	if err != nil {
 		return err
 	}
@@ -16,6 +16,7 @@ func NewWindow(panelRelativeWidth float64, border, tbBaseBuffer int) Window {
 		SEARCH:  newLayout(1, 2),
 		HELP:    newLayout(1, 2),
 		VIEW:    newLayout(10, 1),
+		RELATED: newLayout(1, 2),
 	}, panelRelativeWidth, border, tbBaseBuffer}
 }
 
@@ -87,6 +88,19 @@ func (w *Window) Resize(maxWidth, maxHeight, searchHeight int) error {
 	return nil
 }
 
+// SplitRelated gives the bottom third of the display to the related view if show is set and
+// there is room, otherwise the related view is hidden. It must be called after Resize
+func (w *Window) SplitRelated(show bool) {
+	display := w.views[DISPLAY]
+	height := (display.y1 - display.y0) / 3
+	if !show || height < w.views[RELATED].minHeight+1 {
+		w.updateViewDimensions(RELATED, 0, 0, 0, 0)
+		return
+	}
+	w.updateViewDimensions(DISPLAY, display.x0, display.y0, display.x1, display.y1-height-1)
+	w.updateViewDimensions(RELATED, display.x0, display.y1-height, display.x1, display.y1)
+}
+
 func (w *Window) updateViewDimensions(view ViewEnum, x0, y0, x1, y1 int) {
 	v := w.views[view]
 	v.x0, v.y0, v.x1, v.y1 = x0, y0, x1, y1
@@ -2,6 +2,7 @@ package ui_test
 
 import (
 	"kube-review/ui"
+	"reflect"
 	"testing"
 )
 
@@ -46,6 +47,25 @@ func TestCanAlterSearchHeightWithoutEffectingOtherViews(t *testing.T) {
 	assertEqualDimensions(t, views, expectedExtendedSearch)
 }
 
+func TestSplitRelatedTakesBottomOfDisplay(t *testing.T) {
+	views := ui.NewWindow(panelRelativeWidth, border, tbBaseBuffer)
+	views.Resize(45, 100, 3)
+	views.SplitRelated(true)
+	expected := [][]int{[]int{1, 4, 44, 65}, []int{1, 66, 44, 96}}
+	for i, view := range []ui.ViewEnum{ui.DISPLAY, ui.RELATED} {
+		x0, y0, x1, y1 := views.GetDimensions(view)
+		if actual := []int{x0, y0, x1, y1}; !reflect.DeepEqual(actual, expected[i]) {
+			t.Errorf("Expected %v but got %v for %v", expected[i], actual, view)
+		}
+	}
+	views.Resize(45, 100, 3)
+	views.SplitRelated(false)
+	assertEqualDimensions(t, views, expectedPanelless)
+	if x0, y0, x1, y1 := views.GetDimensions(ui.RELATED); x0+y0+x1+y1 != 0 {
+		t.Errorf("Expected related view to be hidden but got [%d,%d,%d,%d]", x0, y0, x1, y1)
+	}
+}
+
 func assertEqualDimensions(t *testing.T, window ui.Window, expected [][]int) {
 	for i := 0; i < 4; i++ {
 		x0, y0, x1, y1 := window.GetDimensions(ui.ViewEnum(i))
@@ -0,0 +1,21 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ParseDate parses a date given as YYYY-MM-DD or RFC3339. Empty or "now" is the current time
+func ParseDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || strings.EqualFold(value, "now") {
+		return time.Now().UTC(), nil
+	}
+	for _, layout := range []string{"2006-01-02", time.RFC3339} {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("Invalid date '%s'. Must be now, YYYY-MM-DD or RFC3339", value)
+}
@@ -24,7 +24,11 @@ func LoadJSON(filename string, out interface{}, schema string) error {
 	if errLoad != nil {
 		return errLoad
 	}
+	return ParseJSON(contents, out, schema)
+}
 
+// ParseJSON unmarshals contents into out. If schema is not "" then it will validate json against schema
+func ParseJSON(contents string, out interface{}, schema string) error {
 	if schema != "" {
 		if errValidate := validateJSON(contents, schema); errValidate != nil {
 			return errValidate
@@ -0,0 +1,31 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// CompileFullMatch compiles a regex that must match the whole of a value. An empty pattern
+// returns nil, which callers treat as matching anything
+func CompileFullMatch(pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, nil
+	}
+	r, err := regexp.Compile("^(?:" + pattern + ")$")
+	if err != nil {
+		return nil, fmt.Errorf("Invalid regex '%s' - %s", pattern, err.Error())
+	}
+	return r, nil
+}
+
+// FindName returns the index of name in names, ignoring case. The error lists every name and
+// calls name a what, e.g. RBAC check
+func FindName(what, name string, names []string) (int, error) {
+	for index, n := range names {
+		if strings.EqualFold(n, name) {
+			return index, nil
+		}
+	}
+	return -1, fmt.Errorf("Invalid %s '%s'. Must be one of %s", what, name, strings.Join(names, ", "))
+}
@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"encoding/base64"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var base64Regex = regexp.MustCompile(`^[A-Za-z0-9+/]+={0,2}$`)
+
+// DecodeBase64Text returns value decoded if it is base64 of printable text, with or without
+// padding. Values with fewer than minLength base64 characters are not decoded, as short words
+// are often valid base64
+func DecodeBase64Text(value string, minLength int) (string, bool) {
+	value = strings.TrimSpace(value)
+	if len(strings.TrimRight(value, "=")) < minLength || !base64Regex.MatchString(value) {
+		return "", false
+	}
+	decoded, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		if decoded, err = base64.RawStdEncoding.DecodeString(value); err != nil {
+			return "", false
+		}
+	}
+	if len(decoded) == 0 || !utf8.Valid(decoded) {
+		return "", false
+	}
+	text := string(decoded)
+	for _, c := range text {
+		if !unicode.IsPrint(c) && !unicode.IsSpace(c) {
+			return "", false
+		}
+	}
+	return text, true
+}